@@ -1,10 +1,19 @@
 package chaos
 
 import (
+	"maps"
+
 	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 )
 
+const (
+	// ManagedByLabelKey marks every chaos object and workflow built by this package, so the ones
+	// created by the library can be listed and cleaned up apart from the rest of the cluster
+	ManagedByLabelKey   = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue = "chaos-experiment"
+)
+
 type ConfigChaos struct {
 	Name        string
 	Namespace   string
@@ -44,6 +53,14 @@ func WithLabels(labels map[string]string) OptChaos {
 	}
 }
 
+// managedLabels returns a copy of the labels carrying ManagedByLabelKey
+func managedLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	maps.Copy(result, labels)
+	result[ManagedByLabelKey] = ManagedByLabelValue
+	return result
+}
+
 func WithAnnotations(annotations map[string]string) OptChaos {
 	return func(opt *ConfigChaos) {
 		opt.Annotations = annotations
@@ -103,3 +120,21 @@ func WithWorkflowSpec(spec *chaosmeshv1alpha1.WorkflowSpec) OptChaos {
 		opt.Workflow = spec
 	}
 }
+
+func WithKernelChaosSpec(spec *chaosmeshv1alpha1.KernelChaosSpec) OptChaos {
+	return func(config *ConfigChaos) {
+		config.KernelChaos = spec
+	}
+}
+
+func WithBlockChaosSpec(spec *chaosmeshv1alpha1.BlockChaosSpec) OptChaos {
+	return func(config *ConfigChaos) {
+		config.BlockChaos = spec
+	}
+}
+
+func WithPhysicalMachineChaosSpec(spec *chaosmeshv1alpha1.PhysicalMachineChaosSpec) OptChaos {
+	return func(config *ConfigChaos) {
+		config.PhysicalMachineChaos = spec
+	}
+}
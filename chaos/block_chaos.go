@@ -2,6 +2,7 @@ package chaos
 
 import (
 	"errors"
+
 	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 )
 
@@ -20,7 +21,7 @@ func NewBlockChaos(opts ...OptChaos) (*chaosmeshv1alpha1.BlockChaos, error) {
 		return nil, errors.New("the namespace is required")
 	}
 	if config.BlockChaos == nil {
-		return nil, errors.New("httpChaos is required")
+		return nil, errors.New("blockChaos is required")
 	}
 
 	blockChaos := chaosmeshv1alpha1.BlockChaos{}
@@ -28,12 +29,65 @@ func NewBlockChaos(opts ...OptChaos) (*chaosmeshv1alpha1.BlockChaos, error) {
 	blockChaos.Namespace = config.Namespace
 	config.BlockChaos.DeepCopyInto(&blockChaos.Spec)
 
-	if config.Labels != nil {
-		blockChaos.Labels = config.Labels
-	}
+	blockChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		blockChaos.Annotations = config.Annotations
 	}
 
 	return &blockChaos, nil
 }
+
+// OptBlockChaos defines options for block chaos
+type OptBlockChaos func(*chaosmeshv1alpha1.BlockChaosSpec)
+
+// WithBlockDelayAction configures the block chaos to delay every IO request of the device
+func WithBlockDelayAction(latency string, correlation string, jitter string) OptBlockChaos {
+	return func(spec *chaosmeshv1alpha1.BlockChaosSpec) {
+		spec.Action = chaosmeshv1alpha1.BlockDelay
+		spec.Delay = &chaosmeshv1alpha1.BlockDelaySpec{
+			Latency:     latency,
+			Correlation: correlation,
+			Jitter:      jitter,
+		}
+	}
+}
+
+// WithBlockContainerNames sets specific container names for block chaos
+func WithBlockContainerNames(containerNames []string) OptBlockChaos {
+	return func(spec *chaosmeshv1alpha1.BlockChaosSpec) {
+		spec.ContainerNames = containerNames
+	}
+}
+
+// WithBlockPodSelector narrows the pods the block chaos is injected into
+func WithBlockPodSelector(opts ...OptPodSelector) OptBlockChaos {
+	return func(spec *chaosmeshv1alpha1.BlockChaosSpec) {
+		for _, opt := range opts {
+			opt(&spec.PodSelector)
+		}
+	}
+}
+
+// GenerateBlockChaosSpec creates a block chaos spec for the volume of the app's pods, the volume
+// has to be backed by a block device
+func GenerateBlockChaosSpec(namespace string, appName string, duration *string, volumeName string, opts ...OptBlockChaos) *chaosmeshv1alpha1.BlockChaosSpec {
+	spec := &chaosmeshv1alpha1.BlockChaosSpec{
+		Action: chaosmeshv1alpha1.BlockDelay,
+		ContainerNodeVolumePathSelector: chaosmeshv1alpha1.ContainerNodeVolumePathSelector{
+			ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
+				PodSelector: NewPodSelector(namespace, appName),
+			},
+			VolumeName: volumeName,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(spec)
+	}
+
+	if duration != nil && *duration != "" {
+		spec.Duration = duration
+	}
+
+	return spec
+}
@@ -29,9 +29,7 @@ func NewDnsChaos(opts ...OptChaos) (*chaosmeshv1alpha1.DNSChaos, error) {
 	dnsChaos.Namespace = config.Namespace
 	config.DNSChaos.DeepCopyInto(&dnsChaos.Spec)
 
-	if config.Labels != nil {
-		dnsChaos.Labels = config.Labels
-	}
+	dnsChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		dnsChaos.Annotations = config.Annotations
 	}
@@ -40,21 +38,11 @@ func NewDnsChaos(opts ...OptChaos) (*chaosmeshv1alpha1.DNSChaos, error) {
 }
 
 // GenerateDnsChaosSpec creates a DNS chaos spec for the given namespace, app and patterns
-func GenerateDnsChaosSpec(namespace string, appName string, duration *string, action chaosmeshv1alpha1.DNSChaosAction, patterns []string) *chaosmeshv1alpha1.DNSChaosSpec {
+func GenerateDnsChaosSpec(namespace string, appName string, duration *string, action chaosmeshv1alpha1.DNSChaosAction, patterns []string, opts ...OptPodSelector) *chaosmeshv1alpha1.DNSChaosSpec {
 	spec := &chaosmeshv1alpha1.DNSChaosSpec{
 		Action: action,
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector: NewPodSelector(namespace, appName, opts...),
 		},
 		DomainNamePatterns: patterns,
 	}
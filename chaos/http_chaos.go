@@ -32,9 +32,7 @@ func NewHttpChaos(opts ...OptChaos) (*chaosmeshv1alpha1.HTTPChaos, error) {
 	httpChaos.Namespace = config.Namespace
 	config.HttpChaos.DeepCopyInto(&httpChaos.Spec)
 
-	if config.Labels != nil {
-		httpChaos.Labels = config.Labels
-	}
+	httpChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		httpChaos.Annotations = config.Annotations
 	}
@@ -199,18 +197,19 @@ func WithReplaceHeaders(headers map[string]string) OptHTTPChaos {
 	}
 }
 
+// WithHTTPPodSelector narrows the pods the HTTP chaos is injected into
+func WithHTTPPodSelector(opts ...OptPodSelector) OptHTTPChaos {
+	return func(opt *chaosmeshv1alpha1.HTTPChaosSpec) {
+		for _, selectorOpt := range opts {
+			selectorOpt(&opt.PodSelector)
+		}
+	}
+}
+
 func GenerateHttpChaosSpec(namespace string, appName string, duration *string, opts ...OptHTTPChaos) *chaosmeshv1alpha1.HTTPChaosSpec {
 	spec := &chaosmeshv1alpha1.HTTPChaosSpec{
-		PodSelector: chaosmeshv1alpha1.PodSelector{
-			Selector: chaosmeshv1alpha1.PodSelectorSpec{
-				GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-					Namespaces:     []string{namespace},
-					LabelSelectors: map[string]string{"app": appName},
-				},
-			},
-			Mode: chaosmeshv1alpha1.AllMode,
-		},
-		Target: chaosmeshv1alpha1.PodHttpRequest,
+		PodSelector: NewPodSelector(namespace, appName),
+		Target:      chaosmeshv1alpha1.PodHttpRequest,
 	}
 	if duration != nil && *duration != "" {
 		spec.Duration = duration
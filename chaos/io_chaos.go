@@ -29,9 +29,7 @@ func NewIOChaos(opts ...OptChaos) (*chaosmeshv1alpha1.IOChaos, error) {
 	ioChaos.Namespace = config.Namespace
 	config.IOChaos.DeepCopyInto(&ioChaos.Spec)
 
-	if config.Labels != nil {
-		ioChaos.Labels = config.Labels
-	}
+	ioChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		ioChaos.Annotations = config.Annotations
 	}
@@ -113,21 +111,20 @@ func WithIOContainerNames(containerNames []string) OptIOChaos {
 	}
 }
 
+// WithIOPodSelector narrows the pods the IO chaos is injected into
+func WithIOPodSelector(opts ...OptPodSelector) OptIOChaos {
+	return func(spec *chaosmeshv1alpha1.IOChaosSpec) {
+		for _, opt := range opts {
+			opt(&spec.PodSelector)
+		}
+	}
+}
+
 // GenerateIOChaosSpec creates an IO chaos spec
 func GenerateIOChaosSpec(namespace string, appName string, duration *string, volumePath string, opts ...OptIOChaos) *chaosmeshv1alpha1.IOChaosSpec {
 	spec := &chaosmeshv1alpha1.IOChaosSpec{
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector: NewPodSelector(namespace, appName),
 		},
 		VolumePath: volumePath,
 		Percent:    100, // Default to 100%
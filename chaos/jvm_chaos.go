@@ -31,9 +31,7 @@ func NewJvmChaos(opts ...OptChaos) (*chaosmeshv1alpha1.JVMChaos, error) {
 	jvmChaos.Namespace = config.Namespace
 	config.JVMChaos.DeepCopyInto(&jvmChaos.Spec)
 
-	if config.Labels != nil {
-		jvmChaos.Labels = config.Labels
-	}
+	jvmChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		jvmChaos.Annotations = config.Annotations
 	}
@@ -160,20 +158,19 @@ func WithJVMMySQLType(sqlType string) OptJVMChaos {
 	}
 }
 
+// WithJVMPodSelector narrows the pods the JVM chaos is injected into
+func WithJVMPodSelector(opts ...OptPodSelector) OptJVMChaos {
+	return func(opt *chaosmeshv1alpha1.JVMChaosSpec) {
+		for _, selectorOpt := range opts {
+			selectorOpt(&opt.PodSelector)
+		}
+	}
+}
+
 func GenerateJVMChaosSpec(namespace string, appName string, duration *string, opts ...OptJVMChaos) *chaosmeshv1alpha1.JVMChaosSpec {
 	spec := &chaosmeshv1alpha1.JVMChaosSpec{
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector: NewPodSelector(namespace, appName),
 		},
 		JVMParameter: chaosmeshv1alpha1.JVMParameter{},
 	}
@@ -2,6 +2,7 @@ package chaos
 
 import (
 	"errors"
+
 	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 )
 
@@ -19,7 +20,7 @@ func NewKernelChaos(opts ...OptChaos) (*chaosmeshv1alpha1.KernelChaos, error) {
 	if config.Namespace == "" {
 		return nil, errors.New("the namespace is required")
 	}
-	if config.HttpChaos == nil {
+	if config.KernelChaos == nil {
 		return nil, errors.New("kernelChaos is required")
 	}
 
@@ -28,12 +29,80 @@ func NewKernelChaos(opts ...OptChaos) (*chaosmeshv1alpha1.KernelChaos, error) {
 	kernelChaos.Namespace = config.Namespace
 	config.KernelChaos.DeepCopyInto(&kernelChaos.Spec)
 
-	if config.Labels != nil {
-		kernelChaos.Labels = config.Labels
-	}
+	kernelChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		kernelChaos.Annotations = config.Annotations
 	}
 
 	return &kernelChaos, nil
 }
+
+// OptKernelChaos defines options for kernel chaos
+type OptKernelChaos func(*chaosmeshv1alpha1.KernelChaosSpec)
+
+// WithKernelProbability sets the percentage of matching calls that fail
+func WithKernelProbability(probability uint32) OptKernelChaos {
+	return func(spec *chaosmeshv1alpha1.KernelChaosSpec) {
+		spec.FailKernRequest.Probability = probability
+	}
+}
+
+// WithKernelTimes limits how often the kernel call fails, 0 means no limit
+func WithKernelTimes(times uint32) OptKernelChaos {
+	return func(spec *chaosmeshv1alpha1.KernelChaosSpec) {
+		spec.FailKernRequest.Times = times
+	}
+}
+
+// WithKernelCallchain only fails the kernel call when it is reached through the call chain
+func WithKernelCallchain(callchain []chaosmeshv1alpha1.Frame) OptKernelChaos {
+	return func(spec *chaosmeshv1alpha1.KernelChaosSpec) {
+		spec.FailKernRequest.Callchain = callchain
+	}
+}
+
+// WithKernelHeaders sets the kernel headers the call chain predicates need
+func WithKernelHeaders(headers []string) OptKernelChaos {
+	return func(spec *chaosmeshv1alpha1.KernelChaosSpec) {
+		spec.FailKernRequest.Headers = headers
+	}
+}
+
+// WithKernelContainerNames sets specific container names for kernel chaos
+func WithKernelContainerNames(containerNames []string) OptKernelChaos {
+	return func(spec *chaosmeshv1alpha1.KernelChaosSpec) {
+		spec.ContainerNames = containerNames
+	}
+}
+
+// WithKernelPodSelector narrows the pods the kernel chaos is injected into
+func WithKernelPodSelector(opts ...OptPodSelector) OptKernelChaos {
+	return func(spec *chaosmeshv1alpha1.KernelChaosSpec) {
+		for _, opt := range opts {
+			opt(&spec.PodSelector)
+		}
+	}
+}
+
+// GenerateKernelChaosSpec creates a kernel chaos spec, failType selects slab (0), alloc_page (1) or bio (2)
+func GenerateKernelChaosSpec(namespace string, appName string, duration *string, failType int32, opts ...OptKernelChaos) *chaosmeshv1alpha1.KernelChaosSpec {
+	spec := &chaosmeshv1alpha1.KernelChaosSpec{
+		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
+			PodSelector: NewPodSelector(namespace, appName),
+		},
+		FailKernRequest: chaosmeshv1alpha1.FailKernRequest{
+			FailType:    failType,
+			Probability: 100, // Default to 100%
+		},
+	}
+
+	for _, opt := range opts {
+		opt(spec)
+	}
+
+	if duration != nil && *duration != "" {
+		spec.Duration = duration
+	}
+
+	return spec
+}
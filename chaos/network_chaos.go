@@ -29,9 +29,7 @@ func NewNetworkChaos(opts ...OptChaos) (*chaosmeshv1alpha1.NetworkChaos, error)
 	networkChaos.Namespace = config.Namespace
 	config.NetworkChaos.DeepCopyInto(&networkChaos.Spec)
 
-	if config.Labels != nil {
-		networkChaos.Labels = config.Labels
-	}
+	networkChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		networkChaos.Annotations = config.Annotations
 	}
@@ -92,6 +90,15 @@ func WithNetworkTargetAndDirection(namespace string, targetAppName string, direc
 	}
 }
 
+// WithNetworkPodSelector narrows the source pods the network chaos is injected into
+func WithNetworkPodSelector(opts ...OptPodSelector) OptNetworkChaos {
+	return func(opt *chaosmeshv1alpha1.NetworkChaosSpec) {
+		for _, selectorOpt := range opts {
+			selectorOpt(&opt.PodSelector)
+		}
+	}
+}
+
 // Specific TC parameters options
 
 func WithNetworkDelay(latency string, correlation string, jitter string) OptNetworkChaos {
@@ -143,17 +150,9 @@ func WithNetworkBandwidth(rate string, limit uint32, buffer uint32) OptNetworkCh
 
 func GenerateNetworkChaosSpec(namespace string, appName string, duration *string, action chaosmeshv1alpha1.NetworkChaosAction, opts ...OptNetworkChaos) *chaosmeshv1alpha1.NetworkChaosSpec {
 	spec := &chaosmeshv1alpha1.NetworkChaosSpec{
-		Action: action,
-		PodSelector: chaosmeshv1alpha1.PodSelector{
-			Selector: chaosmeshv1alpha1.PodSelectorSpec{
-				GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-					Namespaces:     []string{namespace},
-					LabelSelectors: map[string]string{"app": appName},
-				},
-			},
-			Mode: chaosmeshv1alpha1.AllMode,
-		},
-		Direction: chaosmeshv1alpha1.To, // Default direction
+		Action:      action,
+		PodSelector: NewPodSelector(namespace, appName),
+		Direction:   chaosmeshv1alpha1.To, // Default direction
 	}
 
 	if duration != nil && *duration != "" {
@@ -171,13 +170,6 @@ func GenerateNetworkChaosSpec(namespace string, appName string, duration *string
 
 // Helper function to create target pod selector
 func CreateTargetPodSelector(namespace string, appName string, mode chaosmeshv1alpha1.SelectorMode) *chaosmeshv1alpha1.PodSelector {
-	return &chaosmeshv1alpha1.PodSelector{
-		Selector: chaosmeshv1alpha1.PodSelectorSpec{
-			GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-				Namespaces:     []string{namespace},
-				LabelSelectors: map[string]string{"app": appName},
-			},
-		},
-		Mode: mode,
-	}
+	selector := NewPodSelector(namespace, appName, WithSelectorMode(mode, ""))
+	return &selector
 }
@@ -2,6 +2,7 @@ package chaos
 
 import (
 	"errors"
+
 	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 )
 
@@ -28,12 +29,100 @@ func NewPhysicalMachineChaos(opts ...OptChaos) (*chaosmeshv1alpha1.PhysicalMachi
 	physicalMachineChaos.Namespace = config.Namespace
 	config.PhysicalMachineChaos.DeepCopyInto(&physicalMachineChaos.Spec)
 
-	if config.Labels != nil {
-		physicalMachineChaos.Labels = config.Labels
-	}
+	physicalMachineChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		physicalMachineChaos.Annotations = config.Annotations
 	}
 
 	return &physicalMachineChaos, nil
 }
+
+// OptPhysicalMachineChaos defines options for physical machine chaos
+type OptPhysicalMachineChaos func(*chaosmeshv1alpha1.PhysicalMachineChaosSpec)
+
+// WithPMStressCPUAction configures the physical machine chaos to burn CPU
+func WithPMStressCPUAction(load int, workers int) OptPhysicalMachineChaos {
+	return func(spec *chaosmeshv1alpha1.PhysicalMachineChaosSpec) {
+		spec.Action = chaosmeshv1alpha1.PMStressCPUAction
+		spec.StressCPU = &chaosmeshv1alpha1.StressCPUSpec{
+			Load:    load,
+			Workers: workers,
+		}
+	}
+}
+
+// WithPMStressMemoryAction configures the physical machine chaos to allocate memory, e.g. "256MB"
+func WithPMStressMemoryAction(size string) OptPhysicalMachineChaos {
+	return func(spec *chaosmeshv1alpha1.PhysicalMachineChaosSpec) {
+		spec.Action = chaosmeshv1alpha1.PMStressMemAction
+		spec.StressMemory = &chaosmeshv1alpha1.StressMemorySpec{
+			Size: size,
+		}
+	}
+}
+
+// WithPMDiskFillAction configures the physical machine chaos to fill the disk of the path, e.g. "1GB"
+func WithPMDiskFillAction(size string, path string, fillByFallocate bool) OptPhysicalMachineChaos {
+	return func(spec *chaosmeshv1alpha1.PhysicalMachineChaosSpec) {
+		spec.Action = chaosmeshv1alpha1.PMDiskFillAction
+		spec.DiskFill = &chaosmeshv1alpha1.DiskFillSpec{
+			DiskFileSpec: chaosmeshv1alpha1.DiskFileSpec{
+				Size: size,
+				Path: path,
+			},
+			FillByFallocate: fillByFallocate,
+		}
+	}
+}
+
+// WithPMNetworkDelayAction configures the physical machine chaos to delay the traffic of a device
+func WithPMNetworkDelayAction(device string, latency string, jitter string, correlation string) OptPhysicalMachineChaos {
+	return func(spec *chaosmeshv1alpha1.PhysicalMachineChaosSpec) {
+		spec.Action = chaosmeshv1alpha1.PMNetworkDelayAction
+		spec.NetworkDelay = &chaosmeshv1alpha1.NetworkDelaySpec{
+			NetworkCommonSpec: chaosmeshv1alpha1.NetworkCommonSpec{
+				Device:      device,
+				Correlation: correlation,
+			},
+			Latency: latency,
+			Jitter:  jitter,
+		}
+	}
+}
+
+// WithPMAddress targets chaosd addresses directly instead of PhysicalMachine objects
+func WithPMAddress(addresses []string) OptPhysicalMachineChaos {
+	return func(spec *chaosmeshv1alpha1.PhysicalMachineChaosSpec) {
+		spec.Address = addresses
+		spec.Selector = chaosmeshv1alpha1.PhysicalMachineSelectorSpec{}
+	}
+}
+
+// GeneratePhysicalMachineChaosSpec creates a physical machine chaos spec selecting the PhysicalMachine
+// objects of the namespace labeled with the app they host, under the label key of DefaultAppSelector.
+// The action is set by one of the options.
+func GeneratePhysicalMachineChaosSpec(namespace string, appName string, duration *string, opts ...OptPhysicalMachineChaos) *chaosmeshv1alpha1.PhysicalMachineChaosSpec {
+	spec := &chaosmeshv1alpha1.PhysicalMachineChaosSpec{
+		PhysicalMachineSelector: chaosmeshv1alpha1.PhysicalMachineSelector{
+			Selector: chaosmeshv1alpha1.PhysicalMachineSelectorSpec{
+				GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
+					Namespaces: []string{namespace},
+					LabelSelectors: map[string]string{
+						DefaultAppSelector.Key(): appName,
+					},
+				},
+			},
+			Mode: chaosmeshv1alpha1.AllMode,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(spec)
+	}
+
+	if duration != nil && *duration != "" {
+		spec.Duration = duration
+	}
+
+	return spec
+}
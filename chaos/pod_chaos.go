@@ -29,9 +29,7 @@ func NewPodChaos(opts ...OptChaos) (*chaosmeshv1alpha1.PodChaos, error) {
 	podChaos.Namespace = config.Namespace
 	config.PodChaos.DeepCopyInto(&podChaos.Spec)
 
-	if config.Labels != nil {
-		podChaos.Labels = config.Labels
-	}
+	podChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		podChaos.Annotations = config.Annotations
 	}
@@ -39,22 +37,12 @@ func NewPodChaos(opts ...OptChaos) (*chaosmeshv1alpha1.PodChaos, error) {
 	return &podChaos, nil
 }
 
-func GeneratePodChaosSpec(namespace string, appName string, duration *string, action chaosmeshv1alpha1.PodChaosAction) *chaosmeshv1alpha1.PodChaosSpec {
+func GeneratePodChaosSpec(namespace string, appName string, duration *string, action chaosmeshv1alpha1.PodChaosAction, opts ...OptPodSelector) *chaosmeshv1alpha1.PodChaosSpec {
 
 	spec := &chaosmeshv1alpha1.PodChaosSpec{
 		Action: action,
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector: NewPodSelector(namespace, appName, opts...),
 		},
 	}
 
@@ -66,21 +54,11 @@ func GeneratePodChaosSpec(namespace string, appName string, duration *string, ac
 }
 
 // GeneratePodChaosSpecWithContainers creates a PodChaosSpec with specified container names
-func GeneratePodChaosSpecWithContainers(namespace string, appName string, duration *string, action chaosmeshv1alpha1.PodChaosAction, containerNames []string) *chaosmeshv1alpha1.PodChaosSpec {
+func GeneratePodChaosSpecWithContainers(namespace string, appName string, duration *string, action chaosmeshv1alpha1.PodChaosAction, containerNames []string, opts ...OptPodSelector) *chaosmeshv1alpha1.PodChaosSpec {
 	spec := &chaosmeshv1alpha1.PodChaosSpec{
 		Action: action,
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector:    NewPodSelector(namespace, appName, opts...),
 			ContainerNames: containerNames,
 		},
 	}
@@ -0,0 +1,181 @@
+package chaos
+
+import (
+	"maps"
+	"strings"
+
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DefaultAppLabelKey is the label holding the app name when AppSelector.LabelKey is empty
+const DefaultAppLabelKey = "app"
+
+// WorkloadKind is a kind of workload owning the pods of an app
+type WorkloadKind string
+
+const (
+	Deployment  WorkloadKind = "Deployment"
+	StatefulSet WorkloadKind = "StatefulSet"
+)
+
+// AppSelector maps the app names of a system to their pods. Every generator selects the pods of an
+// app through DefaultAppSelector, and the client package names the app of a pod with it, so both
+// sides agree on any labeling. The zero value selects the pods labeled app=<name>.
+type AppSelector struct {
+	// LabelKey is the label holding the app name, e.g. app.kubernetes.io/name
+	LabelKey string
+
+	// Labels, Expressions and Annotations narrow the pods of every app further, e.g. to a release
+	Labels      map[string]string
+	Expressions []metav1.LabelSelectorRequirement
+	Annotations map[string]string
+
+	// Workload names the apps after the Deployment or StatefulSet owning their pods instead of the
+	// label. Chaos Mesh cannot select pods by owner, so the pods of an app are selected with the
+	// selector of its workload from Workloads, see client.ResolveWorkloads, and with the label
+	// while the workload is not resolved.
+	Workload  WorkloadKind
+	Workloads map[string]metav1.LabelSelector
+}
+
+// DefaultAppSelector is the app selector of the generators and the container lookup. The handler
+// sets its label key to TargetLabelKey.
+var DefaultAppSelector AppSelector
+
+// Key returns the label holding the app name
+func (s AppSelector) Key() string {
+	if s.LabelKey == "" {
+		return DefaultAppLabelKey
+	}
+	return s.LabelKey
+}
+
+// Spec returns the Chaos Mesh selector of the pods of the app in the namespace
+func (s AppSelector) Spec(namespace string, appName string) chaosmeshv1alpha1.GenericSelectorSpec {
+	labelSelectors := map[string]string{}
+	var expressions chaosmeshv1alpha1.LabelSelectorRequirements
+	if workload, ok := s.Workloads[appName]; ok && s.Workload != "" {
+		maps.Copy(labelSelectors, workload.MatchLabels)
+		expressions = append(expressions, workload.MatchExpressions...)
+	} else {
+		labelSelectors[s.Key()] = appName
+	}
+	maps.Copy(labelSelectors, s.Labels)
+	expressions = append(expressions, s.Expressions...)
+
+	spec := chaosmeshv1alpha1.GenericSelectorSpec{
+		Namespaces:          []string{namespace},
+		LabelSelectors:      labelSelectors,
+		ExpressionSelectors: expressions,
+	}
+	if len(s.Annotations) > 0 {
+		spec.AnnotationSelectors = maps.Clone(s.Annotations)
+	}
+
+	return spec
+}
+
+// LabelSelector returns the label selector of the pods of the app, to list them from the cluster.
+// It leaves out the annotations and the workload owner, AppOf checks those on the listed pods.
+func (s AppSelector) LabelSelector(appName string) (labels.Selector, error) {
+	spec := s.Spec("", appName)
+	return metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels:      spec.LabelSelectors,
+		MatchExpressions: spec.ExpressionSelectors,
+	})
+}
+
+// AppOf returns the app of the pod, empty when the pod belongs to none of the apps
+func (s AppSelector) AppOf(pod *corev1.Pod) string {
+	if !s.matches(pod) {
+		return ""
+	}
+
+	if s.Workload == "" {
+		return pod.Labels[s.Key()]
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+
+	switch {
+	case s.Workload == StatefulSet && owner.Kind == string(StatefulSet):
+		return owner.Name
+	case s.Workload == Deployment && owner.Kind == "ReplicaSet":
+		// A Deployment names its ReplicaSets after itself and the pod template hash
+		hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		if !ok || !strings.HasSuffix(owner.Name, "-"+hash) {
+			return ""
+		}
+		return strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+
+	return ""
+}
+
+// matches reports whether the pod has the labels, expressions and annotations common to every app
+func (s AppSelector) matches(pod *corev1.Pod) bool {
+	for key, value := range s.Labels {
+		if pod.Labels[key] != value {
+			return false
+		}
+	}
+	for key, value := range s.Annotations {
+		if pod.Annotations[key] != value {
+			return false
+		}
+	}
+
+	if len(s.Expressions) > 0 {
+		selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: s.Expressions})
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// OptPodSelector narrows the pods of a selector a chaos is injected into
+type OptPodSelector func(selector *chaosmeshv1alpha1.PodSelector)
+
+// WithSelectorMode sets how Chaos Mesh picks among the selected pods when the chaos starts, e.g.
+// FixedPercentMode with value "50" injects half of them. OneMode and AllMode ignore the value.
+func WithSelectorMode(mode chaosmeshv1alpha1.SelectorMode, value string) OptPodSelector {
+	return func(selector *chaosmeshv1alpha1.PodSelector) {
+		selector.Mode = mode
+		selector.Value = value
+	}
+}
+
+// WithSelectedPods limits the selector to the named pods of the namespace
+func WithSelectedPods(namespace string, pods []string) OptPodSelector {
+	return func(selector *chaosmeshv1alpha1.PodSelector) {
+		selector.Selector.Pods = map[string][]string{namespace: pods}
+	}
+}
+
+// NewPodSelector selects the pods of the app in the namespace through DefaultAppSelector, all of
+// them unless the options narrow the selection
+func NewPodSelector(namespace string, appName string, opts ...OptPodSelector) chaosmeshv1alpha1.PodSelector {
+	selector := chaosmeshv1alpha1.PodSelector{
+		Selector: chaosmeshv1alpha1.PodSelectorSpec{
+			GenericSelectorSpec: DefaultAppSelector.Spec(namespace, appName),
+		},
+		Mode: chaosmeshv1alpha1.AllMode,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&selector)
+		}
+	}
+
+	return selector
+}
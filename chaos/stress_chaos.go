@@ -29,9 +29,7 @@ func NewStressChaos(opts ...OptChaos) (*chaosmeshv1alpha1.StressChaos, error) {
 	stressChaos.Namespace = config.Namespace
 	config.StressChaos.DeepCopyInto(&stressChaos.Spec)
 
-	if config.Labels != nil {
-		stressChaos.Labels = config.Labels
-	}
+	stressChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		stressChaos.Annotations = config.Annotations
 	}
@@ -39,21 +37,11 @@ func NewStressChaos(opts ...OptChaos) (*chaosmeshv1alpha1.StressChaos, error) {
 	return &stressChaos, nil
 }
 
-func GenerateStressChaosSpec(namespace string, appName string, duration *string, Stressors chaosmeshv1alpha1.Stressors) *chaosmeshv1alpha1.StressChaosSpec {
+func GenerateStressChaosSpec(namespace string, appName string, duration *string, Stressors chaosmeshv1alpha1.Stressors, opts ...OptPodSelector) *chaosmeshv1alpha1.StressChaosSpec {
 
 	spec := &chaosmeshv1alpha1.StressChaosSpec{
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector: NewPodSelector(namespace, appName, opts...),
 		},
 		Stressors: &Stressors,
 	}
@@ -64,20 +52,10 @@ func GenerateStressChaosSpec(namespace string, appName string, duration *string,
 }
 
 // GenerateStressChaosSpecWithContainers creates a StressChaosSpec with specified container names
-func GenerateStressChaosSpecWithContainers(namespace string, appName string, duration *string, Stressors chaosmeshv1alpha1.Stressors, containerNames []string) *chaosmeshv1alpha1.StressChaosSpec {
+func GenerateStressChaosSpecWithContainers(namespace string, appName string, duration *string, Stressors chaosmeshv1alpha1.Stressors, containerNames []string, opts ...OptPodSelector) *chaosmeshv1alpha1.StressChaosSpec {
 	spec := &chaosmeshv1alpha1.StressChaosSpec{
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector:    NewPodSelector(namespace, appName, opts...),
 			ContainerNames: containerNames,
 		},
 		Stressors: &Stressors,
@@ -29,9 +29,7 @@ func NewTimeChaos(opts ...OptChaos) (*chaosmeshv1alpha1.TimeChaos, error) {
 	timeChaos.Namespace = config.Namespace
 	config.TimeChaos.DeepCopyInto(&timeChaos.Spec)
 
-	if config.Labels != nil {
-		timeChaos.Labels = config.Labels
-	}
+	timeChaos.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		timeChaos.Annotations = config.Annotations
 	}
@@ -39,21 +37,11 @@ func NewTimeChaos(opts ...OptChaos) (*chaosmeshv1alpha1.TimeChaos, error) {
 	return &timeChaos, nil
 }
 
-func GenerateTimeChaosSpec(namespace string, appName string, duration *string, timeOffset string) *chaosmeshv1alpha1.TimeChaosSpec {
+func GenerateTimeChaosSpec(namespace string, appName string, duration *string, timeOffset string, opts ...OptPodSelector) *chaosmeshv1alpha1.TimeChaosSpec {
 	spec := &chaosmeshv1alpha1.TimeChaosSpec{
 		TimeOffset: timeOffset,
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector: NewPodSelector(namespace, appName, opts...),
 		},
 	}
 
@@ -65,21 +53,11 @@ func GenerateTimeChaosSpec(namespace string, appName string, duration *string, t
 }
 
 // GenerateTimeChaosSpecWithContainers creates a TimeChaosSpec with specified container names
-func GenerateTimeChaosSpecWithContainers(namespace string, appName string, duration *string, timeOffset string, containerNames []string) *chaosmeshv1alpha1.TimeChaosSpec {
+func GenerateTimeChaosSpecWithContainers(namespace string, appName string, duration *string, timeOffset string, containerNames []string, opts ...OptPodSelector) *chaosmeshv1alpha1.TimeChaosSpec {
 	spec := &chaosmeshv1alpha1.TimeChaosSpec{
 		TimeOffset: timeOffset,
 		ContainerSelector: chaosmeshv1alpha1.ContainerSelector{
-			PodSelector: chaosmeshv1alpha1.PodSelector{
-				Selector: chaosmeshv1alpha1.PodSelectorSpec{
-					GenericSelectorSpec: chaosmeshv1alpha1.GenericSelectorSpec{
-						Namespaces: []string{namespace},
-						LabelSelectors: map[string]string{
-							"app": appName,
-						},
-					},
-				},
-				Mode: chaosmeshv1alpha1.AllMode,
-			},
+			PodSelector:    NewPodSelector(namespace, appName, opts...),
 			ContainerNames: containerNames,
 		},
 	}
@@ -28,9 +28,7 @@ func NewWorkflowChaos(opts ...OptChaos) (*chaosmeshv1alpha1.Workflow, error) {
 	workflow.Namespace = config.Namespace
 	config.Workflow.DeepCopyInto(&workflow.Spec)
 
-	if config.Labels != nil {
-		workflow.Labels = config.Labels
-	}
+	workflow.Labels = managedLabels(config.Labels)
 	if config.Annotations != nil {
 		workflow.Annotations = config.Annotations
 	}
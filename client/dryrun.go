@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+type ManifestFormat string
+
+const (
+	FormatYAML ManifestFormat = "yaml"
+	FormatJSON ManifestFormat = "json"
+)
+
+// DryRunClient is a client.Client that renders created objects instead of sending them to a cluster.
+// Reads and later updates go to an in-memory fake client, so code that creates and then queries
+// its chaos keeps working offline.
+type DryRunClient struct {
+	client.Client
+
+	mu      sync.Mutex
+	scheme  *runtime.Scheme
+	output  io.Writer
+	format  ManifestFormat
+	objects []client.Object
+}
+
+type DryRunOption func(*DryRunClient)
+
+// WithOutput writes every created object to w as soon as it is created
+func WithOutput(w io.Writer) DryRunOption {
+	return func(c *DryRunClient) {
+		c.output = w
+	}
+}
+
+// WithFormat sets the format used by WithOutput and Render, defaults to YAML
+func WithFormat(format ManifestFormat) DryRunOption {
+	return func(c *DryRunClient) {
+		c.format = format
+	}
+}
+
+// WithInitObjects seeds the in-memory cluster, e.g. with the pods the specs look up
+func WithInitObjects(objs ...client.Object) DryRunOption {
+	return func(c *DryRunClient) {
+		c.Client = fake.NewClientBuilder().WithScheme(c.scheme).WithObjects(objs...).Build()
+	}
+}
+
+// NewDryRunClient returns a client that records created objects without a live API server
+func NewDryRunClient(opts ...DryRunOption) (*DryRunClient, error) {
+	scheme, err := NewScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &DryRunClient{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		scheme: scheme,
+		format: FormatYAML,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Create records the fully built object and stores it in the in-memory cluster
+func (c *DryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return fmt.Errorf("failed to resolve kind of %T: %w", obj, err)
+	}
+
+	rendered := obj.DeepCopyObject().(client.Object)
+	rendered.GetObjectKind().SetGroupVersionKind(gvk)
+
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.objects = append(c.objects, rendered)
+	if c.output == nil {
+		return nil
+	}
+
+	data, err := marshalManifest(rendered, c.format)
+	if err != nil {
+		return err
+	}
+
+	if c.format == FormatYAML && len(c.objects) > 1 {
+		data = append([]byte("---\n"), data...)
+	}
+
+	if _, err := c.output.Write(data); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Objects returns the objects created so far in creation order
+func (c *DryRunClient) Objects() []client.Object {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]client.Object(nil), c.objects...)
+}
+
+// Render returns every object created so far as a multi-document YAML stream or a JSON array
+func (c *DryRunClient) Render() ([]byte, error) {
+	objects := c.Objects()
+
+	if c.format == FormatJSON {
+		data, err := json.MarshalIndent(objects, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal manifests: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+
+	var out []byte
+	for i, obj := range objects {
+		data, err := marshalManifest(obj, FormatYAML)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			out = append(out, "---\n"...)
+		}
+		out = append(out, data...)
+	}
+
+	return out, nil
+}
+
+func marshalManifest(obj client.Object, format ManifestFormat) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s to JSON: %w", obj.GetName(), err)
+		}
+		return append(data, '\n'), nil
+	case FormatYAML:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s to YAML: %w", obj.GetName(), err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+}
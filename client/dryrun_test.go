@@ -0,0 +1,58 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDryRunClient(t *testing.T) {
+	var out bytes.Buffer
+	dryRun, err := NewDryRunClient(WithOutput(&out))
+	if err != nil {
+		t.Fatalf("NewDryRunClient() error = %v", err)
+	}
+
+	for _, app := range []string{"ts-order-service", "ts-travel-service"} {
+		spec := chaos.GeneratePodChaosSpec("ts0", app, nil, v1alpha1.PodKillAction)
+		podChaos, err := chaos.NewPodChaos(chaos.WithName(app+"-kill"), chaos.WithNamespace("ts0"), chaos.WithPodChaosSpec(spec))
+		if err != nil {
+			t.Fatalf("NewPodChaos() error = %v", err)
+		}
+
+		if err := dryRun.Create(context.Background(), podChaos); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if got := len(dryRun.Objects()); got != 2 {
+		t.Fatalf("recorded %d objects, want 2", got)
+	}
+
+	rendered, err := dryRun.Render()
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !bytes.Equal(rendered, out.Bytes()) {
+		t.Errorf("Render() differs from the streamed output:\n%s\nvs\n%s", rendered, out.String())
+	}
+
+	manifest := string(rendered)
+	for _, want := range []string{"kind: PodChaos", "apiVersion: chaos-mesh.org/v1alpha1", "name: ts-order-service-kill", "---\n"} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("manifest is missing %q:\n%s", want, manifest)
+		}
+	}
+
+	// Created objects stay readable so status lookups work offline
+	var stored v1alpha1.PodChaos
+	if err := dryRun.Get(context.Background(), client.ObjectKey{Namespace: "ts0", Name: "ts-travel-service-kill"}, &stored); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+}
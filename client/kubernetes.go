@@ -3,71 +3,83 @@ package client
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"slices"
-	"sync"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
 	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-var (
-	k8sClientInstance client.Client
-	once              sync.Once
-)
+const serviceAccountMountPath = "/var/run/secrets/kubernetes.io"
 
-// GetK8sConfig returns Kubernetes configuration
+// GetK8sConfig returns the Kubernetes configuration of the default provider and panics if there is none
 func GetK8sConfig() *rest.Config {
-	kubeconfig := filepath.Join(os.Getenv("HOME"), ".kube", "config")
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := LoadConfig()
 	if err != nil {
 		panic(err.Error())
 	}
 	return config
 }
 
-// NewK8sClient initializes a new Kubernetes client using singleton pattern
-func NewK8sClient() client.Client {
-	once.Do(func() {
-		cfg := GetK8sConfig()
-		scheme := runtime.NewScheme()
+// NewScheme returns a scheme with the Chaos Mesh CRDs, the core and the apps types registered
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
 
-		// Register Chaos Mesh CRD scheme
-		err := chaosmeshv1alpha1.AddToScheme(scheme)
-		if err != nil {
-			logrus.Fatalf("Failed to add Chaos Mesh v1alpha1 scheme: %v", err)
-		}
+	// Register Chaos Mesh CRD scheme
+	if err := chaosmeshv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add Chaos Mesh v1alpha1 scheme: %w", err)
+	}
 
-		// Register CoreV1 scheme
-		err = corev1.AddToScheme(scheme)
-		if err != nil {
-			logrus.Fatalf("Failed to add CoreV1 scheme: %v", err)
-		}
+	// Register CoreV1 scheme
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add CoreV1 scheme: %w", err)
+	}
 
-		// Create Kubernetes client
-		k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
-		if err != nil {
-			logrus.Fatalf("Failed to create Kubernetes client: %v", err)
-		}
-		k8sClientInstance = k8sClient
-	})
+	// Register AppsV1 scheme for the workloads owning the pods
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add AppsV1 scheme: %w", err)
+	}
+
+	return scheme, nil
+}
+
+// NewK8sClient returns the client of the default provider and exits if it cannot be created
+func NewK8sClient() client.Client {
+	k8sClient, err := DefaultProvider().Client()
+	if err != nil {
+		logrus.Fatalf("Failed to create Kubernetes client: %v", err)
+	}
 
-	return k8sClientInstance
+	return k8sClient
 }
 
 func ListNamespaces() ([]string, error) {
+	k8sClient, err := DefaultProvider().Client()
+	if err != nil {
+		return nil, err
+	}
+
+	return ListNamespacesWithClient(context.TODO(), k8sClient)
+}
+
+// ListNamespacesWithClient lists the namespaces of the cluster behind the client
+func ListNamespacesWithClient(ctx context.Context, k8sClient client.Client) ([]string, error) {
 	var namespaceList corev1.NamespaceList
-	if err := NewK8sClient().List(context.TODO(), &namespaceList); err != nil {
+	if err := k8sClient.List(ctx, &namespaceList); err != nil {
 		return nil, fmt.Errorf("failed to list namespaces: %v", err)
 	}
 
@@ -79,12 +91,20 @@ func ListNamespaces() ([]string, error) {
 	return namespaces, nil
 }
 
+// GetLabels returns the sorted values of the label key on the pods of the namespace. The values of
+// the app key of chaos.DefaultAppSelector are the apps it names the pods after, so pods outside its
+// labels, expressions and annotations are left out and apps named after workloads are listed.
 func GetLabels(ctx context.Context, namespace string, key string) ([]string, error) {
 	labelValues := []string{}
 
 	// List all pods in the specified namespace
+	k8sClient, err := ProviderFor(namespace).Client()
+	if err != nil {
+		return nil, err
+	}
+
 	podList := &corev1.PodList{}
-	err := NewK8sClient().List(ctx, podList, &client.ListOptions{
+	err = k8sClient.List(ctx, podList, &client.ListOptions{
 		Namespace: namespace,
 	})
 	if err != nil {
@@ -93,7 +113,7 @@ func GetLabels(ctx context.Context, namespace string, key string) ([]string, err
 	}
 
 	for _, pod := range podList.Items {
-		if value, exists := pod.Labels[key]; exists {
+		if value, exists := podLabel(&pod, key); exists {
 			labelValues = append(labelValues, value)
 		}
 	}
@@ -107,20 +127,25 @@ func GetLabels(ctx context.Context, namespace string, key string) ([]string, err
 }
 
 // GetContainersWithAppLabel retrieves all containers along with their pod names and app labels
-// in the specified namespace
+// in the specified namespace. The app label is the app chaos.DefaultAppSelector names the pod after.
 func GetContainersWithAppLabel(ctx context.Context, namespace string) ([]map[string]string, error) {
 	result := []map[string]string{}
 
 	// List all pods in the specified namespace
+	k8sClient, err := ProviderFor(namespace).Client()
+	if err != nil {
+		return nil, err
+	}
+
 	podList := &corev1.PodList{}
-	if err := NewK8sClient().List(ctx, podList, &client.ListOptions{
+	if err := k8sClient.List(ctx, podList, &client.ListOptions{
 		Namespace: namespace,
 	}); err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace %s: %v", namespace, err)
 	}
 
 	for _, pod := range podList.Items {
-		appLabel := pod.Labels["app"]
+		appLabel := chaos.DefaultAppSelector.AppOf(&pod)
 
 		// Add each container with its pod name and app label
 		for _, container := range pod.Spec.Containers {
@@ -136,68 +161,225 @@ func GetContainersWithAppLabel(ctx context.Context, namespace string) ([]map[str
 	return result, nil
 }
 
+// GetContainersWithVolumeMounts retrieves every volume mount of every container along with
+// its pod name and app label in the specified namespace. Service account token mounts are skipped
+// because IOChaos cannot be injected into them.
+func GetContainersWithVolumeMounts(ctx context.Context, namespace string) ([]map[string]string, error) {
+	result := []map[string]string{}
+
+	k8sClient, err := ProviderFor(namespace).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := k8sClient.List(ctx, podList, &client.ListOptions{
+		Namespace: namespace,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %v", namespace, err)
+	}
+
+	for _, pod := range podList.Items {
+		appLabel := chaos.DefaultAppSelector.AppOf(&pod)
+
+		// Volumes backed by a persistent volume claim are the ones with a block device under them
+		claims := make(map[string]string)
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				claims[volume.Name] = volume.PersistentVolumeClaim.ClaimName
+			}
+		}
+
+		for _, container := range pod.Spec.Containers {
+			for _, mount := range container.VolumeMounts {
+				if strings.HasPrefix(mount.MountPath, serviceAccountMountPath) {
+					continue
+				}
+
+				mountInfo := map[string]string{
+					"podName":       pod.Name,
+					"appLabel":      appLabel,
+					"containerName": container.Name,
+					"volumeName":    mount.Name,
+					"volumePath":    mount.MountPath,
+					"claimName":     claims[mount.Name],
+				}
+				result = append(result, mountInfo)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetPhysicalMachineLabels returns the app labels carried by the PhysicalMachine objects of the namespace,
+// under the key the physical machine chaos selects them by. Unlike GetLabels a namespace without
+// physical machines, or a cluster without the PhysicalMachine CRD, is not an error, most systems run on pods only.
+func GetPhysicalMachineLabels(ctx context.Context, namespace string) ([]string, error) {
+	k8sClient, err := ProviderFor(namespace).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	machineList := &chaosmeshv1alpha1.PhysicalMachineList{}
+	if err := k8sClient.List(ctx, machineList, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to list physical machines in namespace %s: %w", namespace, err)
+	}
+
+	key := chaos.DefaultAppSelector.Key()
+	labelValues := []string{}
+	for _, machine := range machineList.Items {
+		if value, exists := machine.Labels[key]; exists {
+			labelValues = append(labelValues, value)
+		}
+	}
+
+	slices.Sort(labelValues)
+	return slices.Compact(labelValues), nil
+}
+
+// GetPodsByLabel returns the pods of the namespace whose label key has the value, matched like GetLabels.
+// The pods of an app are listed with the label selector of chaos.DefaultAppSelector.
 func GetPodsByLabel(namespace, labelKey, labelValue string) ([]string, error) {
-	pods := &corev1.PodList{}
-	err := NewK8sClient().List(context.Background(), pods,
-		client.InNamespace(namespace),
-		client.MatchingLabels{labelKey: labelValue})
+	k8sClient, err := ProviderFor(namespace).Client()
 	if err != nil {
 		return nil, err
 	}
 
+	var selector labels.Selector
+	if labelKey == chaos.DefaultAppSelector.Key() {
+		if selector, err = chaos.DefaultAppSelector.LabelSelector(labelValue); err != nil {
+			return nil, fmt.Errorf("invalid selector of app %s: %w", labelValue, err)
+		}
+	} else {
+		selector = labels.SelectorFromSet(labels.Set{labelKey: labelValue})
+	}
+
+	pods := &corev1.PodList{}
+	if err := k8sClient.List(context.Background(), pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
 	podNames := make([]string, 0, len(pods.Items))
 	for _, pod := range pods.Items {
-		podNames = append(podNames, pod.Name)
+		if value, exists := podLabel(&pod, labelKey); exists && value == labelValue {
+			podNames = append(podNames, pod.Name)
+		}
 	}
 
 	return podNames, nil
 }
 
-// TODO: 添加需要的类型
+// podLabel returns the value of the label key on the pod, the app chaos.DefaultAppSelector names
+// the pod after for its app key
+func podLabel(pod *corev1.Pod, key string) (string, bool) {
+	if key == chaos.DefaultAppSelector.Key() {
+		app := chaos.DefaultAppSelector.AppOf(pod)
+		return app, app != ""
+	}
+
+	value, exists := pod.Labels[key]
+	return value, exists
+}
+
+// ResolveWorkloads reads the label selectors of the workloads of the selector's kind in the namespace
+// into its Workloads, keyed by workload name, so that the generators can select the pods of an app
+// named after its workload. The workloads of one namespace stand for every namespace of a system.
+func ResolveWorkloads(ctx context.Context, namespace string, selector *chaos.AppSelector) error {
+	k8sClient, err := ProviderFor(namespace).Client()
+	if err != nil {
+		return err
+	}
+
+	workloads := make(map[string]metav1.LabelSelector)
+	switch selector.Workload {
+	case chaos.Deployment:
+		var deployments appsv1.DeploymentList
+		if err := k8sClient.List(ctx, &deployments, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+		}
+		for _, item := range deployments.Items {
+			if item.Spec.Selector != nil {
+				workloads[item.Name] = *item.Spec.Selector
+			}
+		}
+	case chaos.StatefulSet:
+		var statefulSets appsv1.StatefulSetList
+		if err := k8sClient.List(ctx, &statefulSets, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list statefulsets in namespace %s: %w", namespace, err)
+		}
+		for _, item := range statefulSets.Items {
+			if item.Spec.Selector != nil {
+				workloads[item.Name] = *item.Spec.Selector
+			}
+		}
+	default:
+		return fmt.Errorf("unknown workload kind %q", selector.Workload)
+	}
+
+	selector.Workloads = workloads
+	return nil
+}
+
 func GetCRDMapping() map[schema.GroupVersionResource]client.Object {
 	return map[schema.GroupVersionResource]client.Object{
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "dnschaos"}:     &v1alpha1.DNSChaos{},
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "httpchaos"}:    &v1alpha1.HTTPChaos{},
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "jvmchaos"}:     &v1alpha1.JVMChaos{},
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "networkchaos"}: &v1alpha1.NetworkChaos{},
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "podchaos"}:     &v1alpha1.PodChaos{},
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "stresschaos"}:  &v1alpha1.StressChaos{},
-		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "timechaos"}:    &v1alpha1.TimeChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "blockchaos"}:           &v1alpha1.BlockChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "dnschaos"}:             &v1alpha1.DNSChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "httpchaos"}:            &v1alpha1.HTTPChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "iochaos"}:              &v1alpha1.IOChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "jvmchaos"}:             &v1alpha1.JVMChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "kernelchaos"}:          &v1alpha1.KernelChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "networkchaos"}:         &v1alpha1.NetworkChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "physicalmachinechaos"}: &v1alpha1.PhysicalMachineChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "podchaos"}:             &v1alpha1.PodChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "stresschaos"}:          &v1alpha1.StressChaos{},
+		{Group: "chaos-mesh.org", Version: "v1alpha1", Resource: "timechaos"}:            &v1alpha1.TimeChaos{},
 	}
 }
 
-// QueryCRDByName 查询指定命名空间和名称的 CRD，并检查其状态
-func QueryCRDByName(namespace, nameToQuery string) (time.Time, time.Time, error) {
-	k8sClient := NewK8sClient()
-	ctx := context.Background()
-
-	// 定义支持的 CRD 类型和对应的 GVR 映射
-	crdMapping := GetCRDMapping()
-	for gvr, obj := range crdMapping {
+// GetChaosByName looks up a chaos object of any kind in GetCRDMapping by its name
+func GetChaosByName(ctx context.Context, k8sClient client.Client, namespace, name string) (chaosmeshv1alpha1.StatefulObject, schema.GroupVersionResource, error) {
+	for gvr, obj := range GetCRDMapping() {
 		objCopy := obj.DeepCopyObject().(client.Object)
-		err := k8sClient.Get(ctx, client.ObjectKey{Name: nameToQuery, Namespace: namespace}, objCopy)
+		err := k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, objCopy)
 		if err == nil {
-			logrus.Infof("Found resource in GroupVersionResource: %s\n", gvr)
+			stateful, ok := objCopy.(chaosmeshv1alpha1.StatefulObject)
+			if !ok {
+				return nil, gvr, fmt.Errorf("resource %s of %s has no chaos status", name, gvr.Resource)
+			}
 
-			switch resource := objCopy.(type) {
-			case *chaosmeshv1alpha1.HTTPChaos:
-				return checkStatus(resource.Status.ChaosStatus)
+			return stateful, gvr, nil
+		}
 
-			case *chaosmeshv1alpha1.NetworkChaos:
-				return checkStatus(resource.Status.ChaosStatus)
+		if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, gvr, fmt.Errorf("failed to get %s %s/%s: %w", gvr.Resource, namespace, name, err)
+		}
+	}
 
-			case *chaosmeshv1alpha1.PodChaos:
-				return checkStatus(resource.Status.ChaosStatus)
+	return nil, schema.GroupVersionResource{}, apierrors.NewNotFound(schema.GroupResource{Group: "chaos-mesh.org"}, name)
+}
 
-			case *chaosmeshv1alpha1.StressChaos:
-				return checkStatus(resource.Status.ChaosStatus)
-			}
+// QueryCRDByName 查询指定命名空间和名称的 CRD，并检查其状态
+func QueryCRDByName(namespace, nameToQuery string) (time.Time, time.Time, error) {
+	k8sClient, err := ProviderFor(namespace).Client()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
 
-			return time.Time{}, time.Time{}, fmt.Errorf("CRD type not found")
+	obj, gvr, err := GetChaosByName(context.Background(), k8sClient, namespace, nameToQuery)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return time.Time{}, time.Time{}, fmt.Errorf("No resource found for name '%s' in namespace '%s'\n", nameToQuery, namespace)
 		}
+
+		return time.Time{}, time.Time{}, err
 	}
 
-	return time.Time{}, time.Time{}, fmt.Errorf("No resource found for name '%s' in namespace '%s'\n", nameToQuery, namespace)
+	logrus.Infof("Found resource in GroupVersionResource: %s\n", gvr)
+	return checkStatus(*obj.GetStatus())
 }
 
 // checkStatus 检查 Chaos 状态是否注入成功和恢复成功
@@ -3,12 +3,19 @@ package client
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"slices"
 	"testing"
 
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
 	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 	"github.com/k0kubun/pp/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestGetLabel(t *testing.T) {
@@ -57,3 +64,132 @@ func TestGetContainersWithAppLabel(t *testing.T) {
 
 	pp.Println(containerInfos)
 }
+
+func TestAppSelector(t *testing.T) {
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("NewScheme() error = %v", err)
+	}
+
+	controller := true
+	pod := func(name, app, owner string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "selector1",
+				Labels:      map[string]string{"app.kubernetes.io/name": app, "tier": "backend", "version": "v2", appsv1.DefaultDeploymentUniqueLabelKey: "5d4f8"},
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: owner + "-5d4f8", Controller: &controller},
+				},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: app}}},
+		}
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "order-v2", Namespace: "selector1"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": "order", "version": "v2"}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pod("order-0", "order", "order-v2", map[string]string{"inject": "true"}),
+		pod("travel-0", "travel", "travel", nil),
+		deployment,
+	).Build()
+
+	RegisterProvider("selector", NewStaticProvider(fakeClient))
+	prevSelector := chaos.DefaultAppSelector
+	defer func() {
+		chaos.DefaultAppSelector = prevSelector
+		UnregisterProvider("selector")
+	}()
+
+	apps := func() []string {
+		containers, err := GetContainersWithAppLabel(context.Background(), "selector1")
+		if err != nil {
+			t.Fatalf("GetContainersWithAppLabel() error = %v", err)
+		}
+		var result []string
+		for _, container := range containers {
+			result = append(result, container["appLabel"])
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		selector chaos.AppSelector
+		want     []string
+		app      string
+		wantSpec v1alpha1.GenericSelectorSpec
+	}{
+		{
+			name:     "label key",
+			selector: chaos.AppSelector{LabelKey: "app.kubernetes.io/name"},
+			want:     []string{"order", "travel"},
+			app:      "order",
+			wantSpec: v1alpha1.GenericSelectorSpec{
+				Namespaces:     []string{"selector1"},
+				LabelSelectors: map[string]string{"app.kubernetes.io/name": "order"},
+			},
+		},
+		{
+			name: "labels, expressions and annotations",
+			selector: chaos.AppSelector{
+				LabelKey:    "app.kubernetes.io/name",
+				Labels:      map[string]string{"tier": "backend"},
+				Expressions: []metav1.LabelSelectorRequirement{{Key: "app.kubernetes.io/name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"travel"}}},
+				Annotations: map[string]string{"inject": "true"},
+			},
+			want: []string{"", "order"},
+			app:  "order",
+			wantSpec: v1alpha1.GenericSelectorSpec{
+				Namespaces:          []string{"selector1"},
+				LabelSelectors:      map[string]string{"app.kubernetes.io/name": "order", "tier": "backend"},
+				ExpressionSelectors: v1alpha1.LabelSelectorRequirements{{Key: "app.kubernetes.io/name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"travel"}}},
+				AnnotationSelectors: map[string]string{"inject": "true"},
+			},
+		},
+		{
+			name:     "owner deployment",
+			selector: chaos.AppSelector{Workload: chaos.Deployment},
+			want:     []string{"order-v2", "travel"},
+			app:      "order-v2",
+			wantSpec: v1alpha1.GenericSelectorSpec{
+				Namespaces:     []string{"selector1"},
+				LabelSelectors: map[string]string{"app.kubernetes.io/name": "order", "version": "v2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chaos.DefaultAppSelector = tt.selector
+			if tt.selector.Workload != "" {
+				if err := ResolveWorkloads(context.Background(), "selector1", &chaos.DefaultAppSelector); err != nil {
+					t.Fatalf("ResolveWorkloads() error = %v", err)
+				}
+			}
+
+			if got := apps(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apps = %v, want %v", got, tt.want)
+			}
+
+			// The label lookups name and select the apps the same way
+			wantLabels := slices.DeleteFunc(slices.Clone(tt.want), func(app string) bool { return app == "" })
+			if got, err := GetLabels(context.Background(), "selector1", tt.selector.Key()); err != nil || !reflect.DeepEqual(got, wantLabels) {
+				t.Errorf("GetLabels() = %v, %v, want %v", got, err, wantLabels)
+			}
+			if got, err := GetPodsByLabel("selector1", tt.selector.Key(), tt.app); err != nil || !reflect.DeepEqual(got, []string{"order-0"}) {
+				t.Errorf("GetPodsByLabel(%s) = %v, %v, want [order-0]", tt.app, got, err)
+			}
+
+			selector := chaos.NewPodSelector("selector1", tt.app)
+			if !reflect.DeepEqual(selector.Selector.GenericSelectorSpec, tt.wantSpec) {
+				t.Errorf("NewPodSelector() = %+v, want %+v", selector.Selector.GenericSelectorSpec, tt.wantSpec)
+			}
+		})
+	}
+}
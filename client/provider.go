@@ -0,0 +1,206 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/utils"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider hands out the client of one cluster
+type Provider interface {
+	Client() (client.Client, error)
+}
+
+type ProviderConf struct {
+	Kubeconfig string
+	Context    string
+	InCluster  bool
+}
+
+type ProviderOption func(*ProviderConf)
+
+// WithKubeconfig loads the given kubeconfig file instead of $KUBECONFIG or $HOME/.kube/config
+func WithKubeconfig(path string) ProviderOption {
+	return func(c *ProviderConf) {
+		c.Kubeconfig = path
+	}
+}
+
+// WithKubeContext selects a context of the kubeconfig instead of its current context
+func WithKubeContext(name string) ProviderOption {
+	return func(c *ProviderConf) {
+		c.Context = name
+	}
+}
+
+// WithInCluster uses the service account of the pod the process runs in
+func WithInCluster() ProviderOption {
+	return func(c *ProviderConf) {
+		c.InCluster = true
+	}
+}
+
+// LoadConfig resolves the REST config. Without options it follows kubectl: $KUBECONFIG, then
+// $HOME/.kube/config, and falls back to the in-cluster config when no kubeconfig is found.
+func LoadConfig(opts ...ProviderOption) (*rest.Config, error) {
+	conf := ProviderConf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return loadConfig(conf)
+}
+
+func loadConfig(conf ProviderConf) (*rest.Config, error) {
+	if conf.InCluster {
+		cfg, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+		}
+		return cfg, nil
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = conf.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: conf.Context}
+
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		if conf.Kubeconfig == "" && conf.Context == "" {
+			if inClusterCfg, inClusterErr := rest.InClusterConfig(); inClusterErr == nil {
+				return inClusterCfg, nil
+			}
+		}
+
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	return cfg, nil
+}
+
+type configProvider struct {
+	conf ProviderConf
+
+	once   sync.Once
+	client client.Client
+	err    error
+}
+
+// NewProvider returns a provider that builds its client on first use from the resolved config
+func NewProvider(opts ...ProviderOption) Provider {
+	p := &configProvider{}
+	for _, opt := range opts {
+		opt(&p.conf)
+	}
+
+	return p
+}
+
+func (p *configProvider) Client() (client.Client, error) {
+	p.once.Do(func() {
+		cfg, err := loadConfig(p.conf)
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		scheme, err := NewScheme()
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		p.client, p.err = client.New(cfg, client.Options{Scheme: scheme})
+		if p.err != nil {
+			p.err = fmt.Errorf("failed to create Kubernetes client: %w", p.err)
+		}
+	})
+
+	return p.client, p.err
+}
+
+type staticProvider struct {
+	client client.Client
+}
+
+// NewStaticProvider wraps an existing client, e.g. a fake or dry-run client
+func NewStaticProvider(c client.Client) Provider {
+	return staticProvider{client: c}
+}
+
+func (p staticProvider) Client() (client.Client, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("static provider has no client")
+	}
+	return p.client, nil
+}
+
+var (
+	providerMu      sync.RWMutex
+	defaultProvider = NewProvider()
+	prefixProviders = map[string]Provider{}
+)
+
+// DefaultProvider returns the provider used for namespaces without a registered provider
+func DefaultProvider() Provider {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+
+	return defaultProvider
+}
+
+// SetDefaultProvider replaces the provider used for namespaces without a registered provider
+func SetDefaultProvider(p Provider) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+
+	defaultProvider = p
+}
+
+// RegisterProvider routes every namespace of a prefix (ts -> ts0, ts1, ...) to the provider,
+// so systems living in different clusters can be used in one process
+func RegisterProvider(prefix string, p Provider) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+
+	prefixProviders[prefix] = p
+}
+
+// RegisteredProvider returns the provider registered for the prefix, without falling back to the default one
+func RegisteredProvider(prefix string) (Provider, bool) {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+
+	p, ok := prefixProviders[prefix]
+	return p, ok
+}
+
+// UnregisterProvider drops the provider of a prefix, whose namespaces go to the default provider again
+func UnregisterProvider(prefix string) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+
+	delete(prefixProviders, prefix)
+}
+
+// ProviderFor returns the provider registered for the namespace or its prefix, or the default provider
+func ProviderFor(namespace string) Provider {
+	providerMu.RLock()
+	defer providerMu.RUnlock()
+
+	if p, ok := prefixProviders[namespace]; ok {
+		return p
+	}
+
+	if prefix, err := utils.ExtractNsPrefix(namespace); err == nil {
+		if p, ok := prefixProviders[prefix]; ok {
+			return p
+		}
+	}
+
+	return defaultProvider
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProviderFor(t *testing.T) {
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("NewScheme() error = %v", err)
+	}
+
+	pod := func(name, app string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "fake1", Labels: map[string]string{"app": app}}}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pod("order-0", "order"),
+		pod("travel-0", "travel"),
+		pod("order-1", "order"),
+	).Build()
+
+	RegisterProvider("fake", NewStaticProvider(fakeClient))
+	defer UnregisterProvider("fake")
+
+	if ProviderFor("other0") != DefaultProvider() {
+		t.Errorf("ProviderFor() of an unregistered prefix is not the default provider")
+	}
+
+	labels, err := GetLabels(context.Background(), "fake1", "app")
+	if err != nil {
+		t.Fatalf("GetLabels() error = %v", err)
+	}
+
+	if want := []string{"order", "travel"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("GetLabels() = %v, want %v", labels, want)
+	}
+}
+
+func TestLoadConfigContext(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	data := `apiVersion: v1
+kind: Config
+current-context: first
+clusters:
+- name: first
+  cluster:
+    server: https://first.example.com
+- name: second
+  cluster:
+    server: https://second.example.com
+contexts:
+- name: first
+  context:
+    cluster: first
+    user: user
+- name: second
+  context:
+    cluster: second
+    user: user
+users:
+- name: user
+  user:
+    token: secret
+`
+	if err := os.WriteFile(kubeconfig, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []ProviderOption
+		want string
+	}{
+		{name: "current context", opts: []ProviderOption{WithKubeconfig(kubeconfig)}, want: "https://first.example.com"},
+		{name: "selected context", opts: []ProviderOption{WithKubeconfig(kubeconfig), WithKubeContext("second")}, want: "https://second.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			if cfg.Host != tt.want {
+				t.Errorf("Host = %s, want %s", cfg.Host, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/handler"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+func main() {
+	planPath := flag.String("plan", "", "Path to the campaign plan (YAML or JSON)")
+	logPath := flag.String("log", "", "Path to the run log, rerun with the same log to resume (default: <campaign name>.jsonl)")
+	list := flag.Bool("list", false, "Print the expanded injections and exit")
+	dryRun := flag.Bool("dry-run", false, "Print the chaos manifests instead of creating them, without waiting")
+	snapshotPath := flag.String("snapshot", "", "Resolve the injection points from a saved catalog snapshot instead of the cluster, requires -dry-run")
+	saveSnapshotPath := flag.String("save-snapshot", "", "Save the injection points of the campaign's systems for -snapshot and exit")
+	flag.Parse()
+
+	if *planPath == "" {
+		fmt.Println("Please provide a campaign plan with -plan")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	campaign, err := handler.LoadCampaignFile(*planPath)
+	if err != nil {
+		fmt.Printf("Error loading campaign: %v\n", err)
+		os.Exit(1)
+	}
+
+	labelKey := campaign.LabelKey
+	if labelKey == "" {
+		labelKey = "app"
+	}
+	if *snapshotPath != "" {
+		if !*dryRun {
+			fmt.Println("-snapshot only resolves injection points, use it with -dry-run")
+			os.Exit(1)
+		}
+
+		snapshot, err := resourcelookup.LoadSnapshot(*snapshotPath)
+		if err != nil {
+			fmt.Printf("Error loading catalog snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		err = handler.InitTargetConfigFromSnapshot(snapshot, campaign.Namespaces, labelKey)
+		if err != nil {
+			fmt.Printf("Error initializing target namespaces: %v\n", err)
+			os.Exit(1)
+		}
+	} else if err := handler.InitTargetConfig(campaign.Namespaces, labelKey); err != nil {
+		fmt.Printf("Error initializing target namespaces: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *saveSnapshotPath != "" {
+		snapshot, err := handler.TakeCatalogSnapshot()
+		if err == nil {
+			err = resourcelookup.SaveSnapshot(snapshot, *saveSnapshotPath)
+		}
+		if err != nil {
+			fmt.Printf("Error saving catalog snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *list {
+		injections, err := campaign.Expand()
+		if err != nil {
+			fmt.Printf("Error expanding campaign: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, injection := range injections {
+			fmt.Printf("%s\t%s\tcooldown %s\n", injection.ID, injection.Duration, injection.Cooldown)
+		}
+		fmt.Printf("Total: %d injections\n", len(injections))
+		return
+	}
+
+	var opts []handler.CampaignOption
+	if *dryRun {
+		if *logPath == "" {
+			logFile, err := os.CreateTemp("", campaign.Name+"-*.jsonl")
+			if err != nil {
+				fmt.Printf("Error creating run log: %v\n", err)
+				os.Exit(1)
+			}
+			logFile.Close()
+			*logPath = logFile.Name()
+		}
+
+		dryRunClient, err := client.NewDryRunClient(client.WithOutput(os.Stdout))
+		if err != nil {
+			fmt.Printf("Error creating dry-run client: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts,
+			handler.WithCampaignClient(dryRunClient),
+			handler.WithCampaignDryRun(),
+			handler.WithCampaignSleep(func(ctx context.Context, _ time.Duration) error { return ctx.Err() }),
+		)
+	}
+
+	if *logPath == "" {
+		*logPath = campaign.Name + ".jsonl"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	records, err := handler.RunCampaign(ctx, campaign, *logPath, opts...)
+	fmt.Fprintf(os.Stderr, "Recorded %d injections, run log: %s\n", len(records), *logPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running campaign: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
+
 	"github.com/LGU-SE-Internal/chaos-experiment/client"
 	"github.com/k0kubun/pp/v3"
 )
 
 func main() {
-	list, _ := client.GetContainersWithAppLabel("ts")
+	list, _ := client.GetContainersWithAppLabel(context.Background(), "ts")
 	pp.Print(list)
 }
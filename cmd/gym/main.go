@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/handler"
+	"github.com/sirupsen/logrus"
+)
+
+type stepRequest struct {
+	Action map[string]any `json:"action"`
+	// NamespaceTarget overrides -namespace-target for this step
+	NamespaceTarget *int `json:"namespace_target,omitempty"`
+}
+
+type server struct {
+	env             *handler.Environment
+	namespaceTarget int
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "Address to listen on")
+	namespaces := flag.String("namespaces", "ts=1", "Comma separated namespace prefixes with their target counts, e.g. ts=2,otel=1")
+	labelKey := flag.String("label-key", "app", "Label selecting the target pods")
+	system := flag.String("system", "", "Namespace prefix of the system to expose (default: the first of -namespaces)")
+	experimentID := flag.String("experiment-id", "", "Label value marking the injections of this server, reset deletes only those (default: <system>-env)")
+	namespaceTarget := flag.Int("namespace-target", 0, "Target namespace index of a step, -1 leases a free one")
+	seed := flag.Int64("seed", 0, "Seed of sample, 0 picks one from the clock")
+	dryRun := flag.Bool("dry-run", false, "Print the chaos manifests instead of creating them")
+	flag.Parse()
+
+	namespaceTargetMap, err := parseNamespaces(*namespaces)
+	if err != nil {
+		fmt.Printf("Error parsing -namespaces: %v\n", err)
+		os.Exit(1)
+	}
+	if *system == "" {
+		*system = strings.TrimSpace(strings.Split(*namespaces, "=")[0])
+	}
+
+	if err := handler.InitTargetConfig(namespaceTargetMap, *labelKey); err != nil {
+		fmt.Printf("Error initializing target namespaces: %v\n", err)
+		os.Exit(1)
+	}
+
+	var opts []handler.EnvironmentOption
+	if *experimentID != "" {
+		opts = append(opts, handler.WithEnvironmentExperimentID(*experimentID))
+	}
+	if *seed != 0 {
+		opts = append(opts, handler.WithEnvironmentSeed(*seed))
+	}
+	if *dryRun {
+		dryRunClient, err := client.NewDryRunClient(client.WithOutput(os.Stdout))
+		if err != nil {
+			fmt.Printf("Error creating dry-run client: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, handler.WithEnvironmentClient(dryRunClient))
+	}
+
+	env, err := handler.NewEnvironment(*system, opts...)
+	if err != nil {
+		fmt.Printf("Error creating environment: %v\n", err)
+		os.Exit(1)
+	}
+
+	s := &server{env: env, namespaceTarget: *namespaceTarget}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /action_space", s.actionSpace)
+	mux.HandleFunc("GET /schema", s.schema)
+	mux.HandleFunc("GET /sample", s.sample)
+	mux.HandleFunc("POST /step", s.step)
+	mux.HandleFunc("POST /reset", s.reset)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{Addr: *addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logrus.Errorf("Failed to shut down: %v", err)
+		}
+	}()
+
+	logrus.Infof("Serving the %s environment on %s", *system, *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Printf("Error serving: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseNamespaces parses "ts=2,otel=1" into the target count of each prefix
+func parseNamespaces(value string) (map[string]int, error) {
+	result := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		prefix, count, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || prefix == "" {
+			return nil, fmt.Errorf("invalid entry %q, want <prefix>=<count>", entry)
+		}
+
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid target count %q of %s", count, prefix)
+		}
+		result[prefix] = n
+	}
+	return result, nil
+}
+
+// actionSpace returns the Node tree with the dynamic ranges resolved
+func (s *server) actionSpace(w http.ResponseWriter, r *http.Request) {
+	space, err := s.env.ActionSpace()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, handler.NodeToMap(space, true))
+}
+
+// schema returns the JSON Schema of the actions in their value form
+func (s *server) schema(w http.ResponseWriter, r *http.Request) {
+	schema, err := s.env.Schema()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// sample returns a random valid action, ready to be sent to step
+func (s *server) sample(w http.ResponseWriter, r *http.Request) {
+	action, err := s.env.Sample()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"action": handler.NodeToMap(action, true)})
+}
+
+// step injects the action and returns the chaos name with its groundtruth
+func (s *server) step(w http.ResponseWriter, r *http.Request) {
+	var req stepRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	action, err := handler.MapToNode(req.Action)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %w", handler.ErrInvalidAction, err))
+		return
+	}
+
+	namespaceTarget := s.namespaceTarget
+	if req.NamespaceTarget != nil {
+		namespaceTarget = *req.NamespaceTarget
+	}
+
+	result, err := s.env.Step(r.Context(), action, namespaceTarget)
+	switch {
+	case errors.Is(err, handler.ErrInvalidAction):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, handler.ErrNoFreeTarget):
+		writeError(w, http.StatusConflict, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// reset deletes every chaos injected by the server
+func (s *server) reset(w http.ResponseWriter, r *http.Request) {
+	deleted, err := s.env.Reset(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"deleted": deleted})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logrus.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/LGU-SE-Internal/chaos-experiment/internal/serviceendpoints"
 )
 
+// The generated data is served for the namespace prefix of the system it was extracted from
+const catalogPrefix = resourcelookup.GeneratedCatalogPrefix
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -78,7 +81,7 @@ func printUsage() {
 }
 
 func listNetworkServices() {
-	networkPairs, err := resourcelookup.GetAllNetworkPairs()
+	networkPairs, err := resourcelookup.GetAllNetworkPairs(catalogPrefix)
 	if err != nil {
 		fmt.Printf("Error retrieving network services: %v\n", err)
 		return
@@ -112,7 +115,7 @@ func listNetworkServices() {
 }
 
 func listServiceDependencies(serviceName string) {
-	networkPairs, err := resourcelookup.GetAllNetworkPairs()
+	networkPairs, err := resourcelookup.GetAllNetworkPairs(catalogPrefix)
 	if err != nil {
 		fmt.Printf("Error retrieving network dependencies: %v\n", err)
 		return
@@ -289,7 +292,7 @@ func listDatabaseOperations(serviceName string) {
 }
 
 func listDatabaseTables() {
-	dbOps, err := resourcelookup.GetAllDatabaseOperations()
+	dbOps, err := resourcelookup.GetAllDatabaseOperations(catalogPrefix)
 	if err != nil {
 		fmt.Printf("Error retrieving database operations: %v\n", err)
 		return
@@ -322,7 +325,7 @@ func listDatabaseTables() {
 }
 
 func listAllDatabaseOperations() {
-	dbOps, err := resourcelookup.GetAllDatabaseOperations()
+	dbOps, err := resourcelookup.GetAllDatabaseOperations(catalogPrefix)
 	if err != nil {
 		fmt.Printf("Error retrieving database operations: %v\n", err)
 		return
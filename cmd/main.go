@@ -5,6 +5,7 @@ import (
 	"github.com/LGU-SE-Internal/chaos-experiment/client"
 	controllers "github.com/LGU-SE-Internal/chaos-experiment/controllers"
 	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/utils/pointer"
 )
@@ -43,6 +44,10 @@ func main() {
 	}
 	controllers.AddHTTPChaosWorkflowNodes(workflowSpec, namespace, appList, "response-replace", injectTime, sleepTime, opts2...)
 	// create workflow
-	controllers.CreateWorkflow(k8sClient, workflowSpec, namespace)
+	name, err := controllers.CreateWorkflow(k8sClient, workflowSpec, namespace)
+	if err != nil {
+		logrus.Fatalf("Failed to create workflow: %v", err)
+	}
+	logrus.Infof("Created workflow %s", name)
 
 }
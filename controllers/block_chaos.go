@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreateBlockChaos creates a block chaos experiment on the block device behind the volume
+func CreateBlockChaos(cli client.Client, ctx context.Context, namespace string, appName string, volumeName string, duration *string, annotations map[string]string, labels map[string]string, opts ...chaos.OptBlockChaos) (string, error) {
+	spec := chaos.GenerateBlockChaosSpec(namespace, appName, duration, volumeName, opts...)
+	name := strings.ToLower(fmt.Sprintf("%s-%s-block-%s-%s", namespace, appName, spec.Action, rand.String(6)))
+	blockChaos, err := chaos.NewBlockChaos(
+		chaos.WithAnnotations(annotations),
+		chaos.WithLabels(labels),
+		chaos.WithName(name),
+		chaos.WithNamespace(namespace),
+		chaos.WithBlockChaosSpec(spec),
+	)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	create, err := blockChaos.ValidateCreate()
+	if err != nil {
+		logrus.Errorf("Failed to validate create chaos: %v", err)
+		return "", err
+	}
+	logrus.Infof("create warning: %v", create)
+	err = cli.Create(ctx, blockChaos)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	return name, nil
+}
+
+func AddBlockChaosWorkflowNodes(workflowSpec *v1alpha1.WorkflowSpec, namespace string, appList []string, volumeName string, injectTime *string, sleepTime *string, opts ...chaos.OptBlockChaos) *v1alpha1.WorkflowSpec {
+	for _, appName := range appList {
+		spec := chaos.GenerateBlockChaosSpec(namespace, appName, nil, volumeName, opts...)
+
+		workflowSpec.Templates = append(workflowSpec.Templates, v1alpha1.Template{
+			Name: strings.ToLower(fmt.Sprintf("%s-%s-block-%s-%s", namespace, appName, spec.Action, rand.String(6))),
+			Type: v1alpha1.TypeBlockChaos,
+			EmbedChaos: &v1alpha1.EmbedChaos{
+				BlockChaos: spec,
+			},
+			Deadline: injectTime,
+		})
+
+		workflowSpec.Templates = append(workflowSpec.Templates, v1alpha1.Template{
+			Name:     fmt.Sprintf("%s-%s", "sleep", rand.String(6)),
+			Type:     v1alpha1.TypeSuspend,
+			Deadline: sleepTime,
+		})
+	}
+
+	return workflowSpec
+}
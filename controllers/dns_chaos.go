@@ -15,8 +15,8 @@ import (
 )
 
 // CreateDnsChaos creates a DNS chaos experiment with the specified parameters
-func CreateDnsChaos(cli client.Client, ctx context.Context, namespace string, appName string, action v1alpha1.DNSChaosAction, patterns []string, duration *string, annotations map[string]string, labels map[string]string) (string, error) {
-	spec := chaos.GenerateDnsChaosSpec(namespace, appName, duration, action, patterns)
+func CreateDnsChaos(cli client.Client, ctx context.Context, namespace string, appName string, action v1alpha1.DNSChaosAction, patterns []string, duration *string, annotations map[string]string, labels map[string]string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GenerateDnsChaosSpec(namespace, appName, duration, action, patterns, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-dns-%s", namespace, appName, rand.String(6)))
 	dnsChaos, err := chaos.NewDnsChaos(
 		chaos.WithAnnotations(annotations),
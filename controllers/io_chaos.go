@@ -37,6 +37,36 @@ func CreateIOChaos(cli client.Client, ctx context.Context, namespace string, app
 	return name
 }
 
+// CreateIOChaosWithContainer creates an IO chaos experiment with specified container names
+func CreateIOChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, volumePath string, chaosType string, duration *string, annotations map[string]string, labels map[string]string, containerNames []string, opts ...chaos.OptIOChaos) (string, error) {
+	opts = append(opts, chaos.WithIOContainerNames(containerNames))
+	spec := chaos.GenerateIOChaosSpec(namespace, appName, duration, volumePath, opts...)
+	name := strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", namespace, appName, chaosType, rand.String(6)))
+	ioChaos, err := chaos.NewIOChaos(
+		chaos.WithAnnotations(annotations),
+		chaos.WithLabels(labels),
+		chaos.WithName(name),
+		chaos.WithNamespace(namespace),
+		chaos.WithIOChaosSpec(spec),
+	)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	create, err := ioChaos.ValidateCreate()
+	if err != nil {
+		logrus.Errorf("Failed to validate create chaos: %v", err)
+		return "", err
+	}
+	logrus.Infof("create warning: %v", create)
+	err = cli.Create(ctx, ioChaos)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	return name, nil
+}
+
 // CreateIODelayExperiment creates an IO delay experiment
 func CreateIODelayExperiment(cli client.Client, ctx context.Context, namespace string, appName string, volumePath string, path string, delay string, duration *string) string {
 	opts := []chaos.OptIOChaos{
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreateKernelChaos creates a kernel chaos experiment failing the kernel calls of the fail type
+func CreateKernelChaos(cli client.Client, ctx context.Context, namespace string, appName string, failType int32, duration *string, annotations map[string]string, labels map[string]string, opts ...chaos.OptKernelChaos) (string, error) {
+	spec := chaos.GenerateKernelChaosSpec(namespace, appName, duration, failType, opts...)
+	name := strings.ToLower(fmt.Sprintf("%s-%s-kernel-%s", namespace, appName, rand.String(6)))
+	kernelChaos, err := chaos.NewKernelChaos(
+		chaos.WithAnnotations(annotations),
+		chaos.WithLabels(labels),
+		chaos.WithName(name),
+		chaos.WithNamespace(namespace),
+		chaos.WithKernelChaosSpec(spec),
+	)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	create, err := kernelChaos.ValidateCreate()
+	if err != nil {
+		logrus.Errorf("Failed to validate create chaos: %v", err)
+		return "", err
+	}
+	logrus.Infof("create warning: %v", create)
+	err = cli.Create(ctx, kernelChaos)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	return name, nil
+}
+
+func AddKernelChaosWorkflowNodes(workflowSpec *v1alpha1.WorkflowSpec, namespace string, appList []string, failType int32, injectTime *string, sleepTime *string, opts ...chaos.OptKernelChaos) *v1alpha1.WorkflowSpec {
+	for _, appName := range appList {
+		spec := chaos.GenerateKernelChaosSpec(namespace, appName, nil, failType, opts...)
+
+		workflowSpec.Templates = append(workflowSpec.Templates, v1alpha1.Template{
+			Name: strings.ToLower(fmt.Sprintf("%s-%s-kernel-%s", namespace, appName, rand.String(6))),
+			Type: v1alpha1.TypeKernelChaos,
+			EmbedChaos: &v1alpha1.EmbedChaos{
+				KernelChaos: spec,
+			},
+			Deadline: injectTime,
+		})
+
+		workflowSpec.Templates = append(workflowSpec.Templates, v1alpha1.Template{
+			Name:     fmt.Sprintf("%s-%s", "sleep", rand.String(6)),
+			Type:     v1alpha1.TypeSuspend,
+			Deadline: sleepTime,
+		})
+	}
+
+	return workflowSpec
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreatePhysicalMachineChaos creates a physical machine chaos experiment on the machines hosting the app,
+// the action is chosen by one of the chaos.WithPM*Action options
+func CreatePhysicalMachineChaos(cli client.Client, ctx context.Context, namespace string, appName string, duration *string, annotations map[string]string, labels map[string]string, opts ...chaos.OptPhysicalMachineChaos) (string, error) {
+	spec := chaos.GeneratePhysicalMachineChaosSpec(namespace, appName, duration, opts...)
+	if spec.Action == "" {
+		return "", fmt.Errorf("physical machine chaos action is required")
+	}
+
+	name := strings.ToLower(fmt.Sprintf("%s-%s-pm-%s-%s", namespace, appName, spec.Action, rand.String(6)))
+	physicalMachineChaos, err := chaos.NewPhysicalMachineChaos(
+		chaos.WithAnnotations(annotations),
+		chaos.WithLabels(labels),
+		chaos.WithName(name),
+		chaos.WithNamespace(namespace),
+		chaos.WithPhysicalMachineChaosSpec(spec),
+	)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	create, err := physicalMachineChaos.ValidateCreate()
+	if err != nil {
+		logrus.Errorf("Failed to validate create chaos: %v", err)
+		return "", err
+	}
+	logrus.Infof("create warning: %v", create)
+	err = cli.Create(ctx, physicalMachineChaos)
+	if err != nil {
+		logrus.Errorf("Failed to create chaos: %v", err)
+		return "", err
+	}
+	return name, nil
+}
+
+func AddPhysicalMachineChaosWorkflowNodes(workflowSpec *v1alpha1.WorkflowSpec, namespace string, appList []string, injectTime *string, sleepTime *string, opts ...chaos.OptPhysicalMachineChaos) *v1alpha1.WorkflowSpec {
+	for _, appName := range appList {
+		spec := chaos.GeneratePhysicalMachineChaosSpec(namespace, appName, nil, opts...)
+
+		workflowSpec.Templates = append(workflowSpec.Templates, v1alpha1.Template{
+			Name: strings.ToLower(fmt.Sprintf("%s-%s-pm-%s-%s", namespace, appName, spec.Action, rand.String(6))),
+			Type: v1alpha1.TypePhysicalMachineChaos,
+			EmbedChaos: &v1alpha1.EmbedChaos{
+				PhysicalMachineChaos: spec,
+			},
+			Deadline: injectTime,
+		})
+
+		workflowSpec.Templates = append(workflowSpec.Templates, v1alpha1.Template{
+			Name:     fmt.Sprintf("%s-%s", "sleep", rand.String(6)),
+			Type:     v1alpha1.TypeSuspend,
+			Deadline: sleepTime,
+		})
+	}
+
+	return workflowSpec
+}
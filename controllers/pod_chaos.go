@@ -14,8 +14,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreatePodChaos(cli client.Client, ctx context.Context, namespace string, appName string, action v1alpha1.PodChaosAction, duration *string, annotations map[string]string, labels map[string]string) (string, error) {
-	spec := chaos.GeneratePodChaosSpec(namespace, appName, duration, action)
+func CreatePodChaos(cli client.Client, ctx context.Context, namespace string, appName string, action v1alpha1.PodChaosAction, duration *string, annotations map[string]string, labels map[string]string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GeneratePodChaosSpec(namespace, appName, duration, action, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", namespace, appName, action, rand.String(6)))
 	podChaos, err := chaos.NewPodChaos(
 		chaos.WithAnnotations(annotations),
@@ -43,8 +43,8 @@ func CreatePodChaos(cli client.Client, ctx context.Context, namespace string, ap
 }
 
 // CreatePodChaosWithContainer creates a pod chaos experiment with specified container names
-func CreatePodChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, action v1alpha1.PodChaosAction, duration *string, annotations map[string]string, labels map[string]string, containerNames []string) (string, error) {
-	spec := chaos.GeneratePodChaosSpecWithContainers(namespace, appName, duration, action, containerNames)
+func CreatePodChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, action v1alpha1.PodChaosAction, duration *string, annotations map[string]string, labels map[string]string, containerNames []string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GeneratePodChaosSpecWithContainers(namespace, appName, duration, action, containerNames, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", namespace, appName, action, rand.String(6)))
 	podChaos, err := chaos.NewPodChaos(
 		chaos.WithAnnotations(annotations),
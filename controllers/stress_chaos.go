@@ -14,8 +14,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateStressChaos(cli client.Client, namespace string, appName string, stressors v1alpha1.Stressors, stressType string, duration *string) (string, error) {
-	spec := chaos.GenerateStressChaosSpec(namespace, appName, duration, stressors)
+func CreateStressChaos(cli client.Client, namespace string, appName string, stressors v1alpha1.Stressors, stressType string, duration *string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GenerateStressChaosSpec(namespace, appName, duration, stressors, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", namespace, appName, stressType, rand.String(6)))
 	stressChaos, err := chaos.NewStressChaos(chaos.WithName(name), chaos.WithNamespace(namespace), chaos.WithStressChaosSpec(spec))
 	if err != nil {
@@ -37,8 +37,8 @@ func CreateStressChaos(cli client.Client, namespace string, appName string, stre
 }
 
 // CreateStressChaosWithContainer creates a stress chaos experiment with specified container names
-func CreateStressChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, stressors v1alpha1.Stressors, stressType string, duration *string, annotations map[string]string, labels map[string]string, containerNames []string) (string, error) {
-	spec := chaos.GenerateStressChaosSpecWithContainers(namespace, appName, duration, stressors, containerNames)
+func CreateStressChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, stressors v1alpha1.Stressors, stressType string, duration *string, annotations map[string]string, labels map[string]string, containerNames []string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GenerateStressChaosSpecWithContainers(namespace, appName, duration, stressors, containerNames, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", namespace, appName, stressType, rand.String(6)))
 	stressChaos, err := chaos.NewStressChaos(
 		chaos.WithAnnotations(annotations),
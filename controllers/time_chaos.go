@@ -13,8 +13,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateTimeChaos(cli client.Client, namespace string, appName string, timeOffset string, duration *string) (string, error) {
-	spec := chaos.GenerateTimeChaosSpec(namespace, appName, duration, timeOffset)
+func CreateTimeChaos(cli client.Client, namespace string, appName string, timeOffset string, duration *string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GenerateTimeChaosSpec(namespace, appName, duration, timeOffset, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-time-%s", namespace, appName, rand.String(6)))
 	timeChaos, err := chaos.NewTimeChaos(chaos.WithName(name), chaos.WithNamespace(namespace), chaos.WithTimeChaosSpec(spec))
 	if err != nil {
@@ -36,8 +36,8 @@ func CreateTimeChaos(cli client.Client, namespace string, appName string, timeOf
 }
 
 // CreateTimeChaosWithContainer creates a time chaos experiment with specified container names
-func CreateTimeChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, timeOffset string, duration *string, annotations map[string]string, labels map[string]string, containerNames []string) (string, error) {
-	spec := chaos.GenerateTimeChaosSpecWithContainers(namespace, appName, duration, timeOffset, containerNames)
+func CreateTimeChaosWithContainer(cli client.Client, ctx context.Context, namespace string, appName string, timeOffset string, duration *string, annotations map[string]string, labels map[string]string, containerNames []string, opts ...chaos.OptPodSelector) (string, error) {
+	spec := chaos.GenerateTimeChaosSpecWithContainers(namespace, appName, duration, timeOffset, containerNames, opts...)
 	name := strings.ToLower(fmt.Sprintf("%s-%s-time-%s", namespace, appName, rand.String(6)))
 	timeChaos, err := chaos.NewTimeChaos(
 		chaos.WithAnnotations(annotations),
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
 	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -44,7 +43,9 @@ func NewWorkflowSpec(namespace string) *v1alpha1.WorkflowSpec {
 	}
 }
 
-func CreateWorkflow(cli client.Client, workflowSpec *v1alpha1.WorkflowSpec, namespace string) {
+// CreateWorkflow runs every template after the entry one after another and returns the created
+// workflow name. Use WorkflowBuilder for parallel groups, status checks and branches.
+func CreateWorkflow(cli client.Client, workflowSpec *v1alpha1.WorkflowSpec, namespace string) (string, error) {
 	for i, template := range workflowSpec.Templates {
 		if i == 0 {
 			continue
@@ -52,22 +53,5 @@ func CreateWorkflow(cli client.Client, workflowSpec *v1alpha1.WorkflowSpec, name
 		workflowSpec.Templates[0].Children = append(workflowSpec.Templates[0].Children, template.Name)
 	}
 
-	workflowChaos, err := chaos.NewWorkflowChaos(chaos.WithName(workflowSpec.Entry), chaos.WithNamespace(namespace), chaos.WithWorkflowSpec(workflowSpec))
-	if err != nil {
-		logrus.Errorf("Failed to create chaos workflow: %v", err)
-	}
-
-	if err != nil {
-		logrus.Errorf("Failed to create chaos: %v", err)
-	}
-
-	create, err := workflowChaos.ValidateCreate()
-	if err != nil {
-		logrus.Errorf("Failed to validate create chaos: %v", err)
-	}
-	logrus.Infof("create warning: %v", create)
-	err = cli.Create(context.Background(), workflowChaos)
-	if err != nil {
-		logrus.Errorf("Failed to create chaos: %v", err)
-	}
+	return SubmitWorkflow(cli, context.Background(), namespace, workflowSpec.Entry, workflowSpec, nil, nil)
 }
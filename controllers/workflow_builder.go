@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WorkflowBuilder builds a Chaos Mesh workflow DAG template by template. Errors are collected while
+// building and returned by Build, so calls can be chained.
+type WorkflowBuilder struct {
+	name      string
+	namespace string
+	entry     string
+	templates []v1alpha1.Template
+	errs      []error
+}
+
+// NewWorkflowBuilder returns a builder of a workflow in the namespace, an empty name is generated
+// from the namespace like NewWorkflowSpec does
+func NewWorkflowBuilder(namespace string, name string) *WorkflowBuilder {
+	if name == "" {
+		name = strings.ToLower(fmt.Sprintf("%s-%s", namespace, rand.String(6)))
+	}
+
+	return &WorkflowBuilder{name: name, namespace: namespace}
+}
+
+// Name returns the name the workflow is created with
+func (b *WorkflowBuilder) Name() string {
+	return b.name
+}
+
+// Entry sets the template the workflow starts with, by default it is the first template added
+func (b *WorkflowBuilder) Entry(name string) *WorkflowBuilder {
+	b.entry = name
+	return b
+}
+
+// Serial adds a template running the children one after another
+func (b *WorkflowBuilder) Serial(name string, deadline string, children ...string) *WorkflowBuilder {
+	return b.Template(v1alpha1.Template{
+		Name:     name,
+		Type:     v1alpha1.TypeSerial,
+		Deadline: optionalString(deadline),
+		Children: children,
+	})
+}
+
+// Parallel adds a template running the children at the same time
+func (b *WorkflowBuilder) Parallel(name string, deadline string, children ...string) *WorkflowBuilder {
+	return b.Template(v1alpha1.Template{
+		Name:     name,
+		Type:     v1alpha1.TypeParallel,
+		Deadline: optionalString(deadline),
+		Children: children,
+	})
+}
+
+// Chaos adds a template injecting the chaos object, e.g. one built by handler.InjectionConf.BuildObject.
+// An empty name uses the name of the object. Workflows do not allow a duration inside the chaos spec,
+// so the duration of the object becomes the deadline unless one is given.
+func (b *WorkflowBuilder) Chaos(name string, obj client.Object, deadline string) *WorkflowBuilder {
+	if obj == nil {
+		b.errs = append(b.errs, fmt.Errorf("template %q: chaos object is nil", name))
+		return b
+	}
+	if name == "" {
+		name = obj.GetName()
+	}
+
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	embed := &v1alpha1.EmbedChaos{}
+	spec := reflect.ValueOf(embed).Elem().FieldByName(kind)
+	if !spec.IsValid() || spec.Kind() != reflect.Ptr {
+		b.errs = append(b.errs, fmt.Errorf("template %q: %s can not be embedded in a workflow", name, kind))
+		return b
+	}
+	spec.Set(reflect.New(spec.Type().Elem()))
+
+	if err := embed.RestoreChaosSpec(obj); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("template %q: %w", name, err))
+		return b
+	}
+
+	if duration := spec.Elem().FieldByName("Duration"); duration.IsValid() && duration.Kind() == reflect.Ptr && !duration.IsNil() {
+		if deadline == "" {
+			deadline = duration.Elem().String()
+		}
+		duration.Set(reflect.Zero(duration.Type()))
+	}
+
+	return b.Template(v1alpha1.Template{
+		Name:       name,
+		Type:       v1alpha1.TemplateType(kind),
+		Deadline:   optionalString(deadline),
+		EmbedChaos: embed,
+	})
+}
+
+// Suspend adds a template waiting for the deadline, which Chaos Mesh requires
+func (b *WorkflowBuilder) Suspend(name string, deadline string) *WorkflowBuilder {
+	return b.Template(v1alpha1.Template{
+		Name:     name,
+		Type:     v1alpha1.TypeSuspend,
+		Deadline: optionalString(deadline),
+	})
+}
+
+// StatusCheck adds a template probing the system, with abort the whole workflow is aborted once the
+// check fails
+func (b *WorkflowBuilder) StatusCheck(name string, spec v1alpha1.StatusCheckSpec, deadline string, abort bool) *WorkflowBuilder {
+	return b.Template(v1alpha1.Template{
+		Name:                 name,
+		Type:                 v1alpha1.TypeStatusCheck,
+		Deadline:             optionalString(deadline),
+		StatusCheck:          &spec,
+		AbortWithStatusCheck: abort,
+	})
+}
+
+// Task adds a template running the container and picking the branches whose expression holds
+func (b *WorkflowBuilder) Task(name string, container v1alpha1.Task, branches ...v1alpha1.ConditionalBranch) *WorkflowBuilder {
+	return b.Template(v1alpha1.Template{
+		Name:                name,
+		Type:                v1alpha1.TypeTask,
+		Task:                &container,
+		ConditionalBranches: branches,
+	})
+}
+
+// Template adds a raw template for anything the other methods do not cover
+func (b *WorkflowBuilder) Template(template v1alpha1.Template) *WorkflowBuilder {
+	b.templates = append(b.templates, template)
+	return b
+}
+
+// Build validates the graph and returns the workflow spec. Names must be unique, every child and
+// branch target must exist, the graph must be acyclic with every template reachable from the entry,
+// and deadlines must be valid durations no longer than the deadline of the parent.
+func (b *WorkflowBuilder) Build() (*v1alpha1.WorkflowSpec, error) {
+	errs := append([]error(nil), b.errs...)
+
+	if len(b.templates) == 0 {
+		errs = append(errs, fmt.Errorf("workflow %s has no template", b.name))
+		return nil, errors.Join(errs...)
+	}
+
+	entry := b.entry
+	if entry == "" {
+		entry = b.templates[0].Name
+	}
+
+	templates := make(map[string]*v1alpha1.Template, len(b.templates))
+	deadlines := make(map[string]time.Duration, len(b.templates))
+	for i := range b.templates {
+		template := &b.templates[i]
+		if template.Name == "" {
+			errs = append(errs, fmt.Errorf("template %d has no name", i))
+			continue
+		}
+		if _, ok := templates[template.Name]; ok {
+			errs = append(errs, fmt.Errorf("template name %q is duplicated", template.Name))
+			continue
+		}
+		templates[template.Name] = template
+
+		if template.Deadline == nil {
+			if template.Type == v1alpha1.TypeSuspend {
+				errs = append(errs, fmt.Errorf("template %q: suspend requires a deadline", template.Name))
+			}
+			continue
+		}
+
+		deadline, err := time.ParseDuration(*template.Deadline)
+		if err != nil || deadline <= 0 {
+			errs = append(errs, fmt.Errorf("template %q: invalid deadline %q", template.Name, *template.Deadline))
+			continue
+		}
+		deadlines[template.Name] = deadline
+	}
+
+	if _, ok := templates[entry]; !ok {
+		errs = append(errs, fmt.Errorf("entry template %q does not exist", entry))
+		return nil, errors.Join(errs...)
+	}
+
+	for _, template := range b.templates {
+		for _, next := range nextTemplates(template) {
+			if _, ok := templates[next]; !ok {
+				errs = append(errs, fmt.Errorf("template %q refers to the missing template %q", template.Name, next))
+				continue
+			}
+
+			parent, ok := deadlines[template.Name]
+			if child, childOK := deadlines[next]; ok && childOK && child > parent {
+				errs = append(errs, fmt.Errorf("template %q: deadline %s exceeds the deadline %s of %q", next, child, parent, template.Name))
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(templates))
+	var visit func(name string)
+	visit = func(name string) {
+		template, ok := templates[name]
+		if !ok {
+			return
+		}
+
+		switch state[name] {
+		case visiting:
+			errs = append(errs, fmt.Errorf("template %q is part of a cycle", name))
+			return
+		case visited:
+			return
+		}
+
+		state[name] = visiting
+		for _, next := range nextTemplates(*template) {
+			visit(next)
+		}
+		state[name] = visited
+	}
+	visit(entry)
+
+	for _, template := range b.templates {
+		if _, ok := templates[template.Name]; ok && state[template.Name] == unvisited {
+			errs = append(errs, fmt.Errorf("template %q is not reachable from the entry %q", template.Name, entry))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid workflow %s: %w", b.name, errors.Join(errs...))
+	}
+
+	spec := &v1alpha1.WorkflowSpec{Entry: entry}
+	for _, template := range b.templates {
+		spec.Templates = append(spec.Templates, *template.DeepCopy())
+	}
+
+	return spec, nil
+}
+
+// Submit builds the workflow and creates it, returning the workflow name
+func (b *WorkflowBuilder) Submit(cli client.Client, ctx context.Context, annotations map[string]string, labels map[string]string) (string, error) {
+	spec, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+
+	return SubmitWorkflow(cli, ctx, b.namespace, b.name, spec, annotations, labels)
+}
+
+// SubmitWorkflow validates the workflow spec with the Chaos Mesh webhook rules and creates it
+func SubmitWorkflow(cli client.Client, ctx context.Context, namespace string, name string, workflowSpec *v1alpha1.WorkflowSpec, annotations map[string]string, labels map[string]string) (string, error) {
+	workflowChaos, err := chaos.NewWorkflowChaos(
+		chaos.WithAnnotations(annotations),
+		chaos.WithLabels(labels),
+		chaos.WithName(name),
+		chaos.WithNamespace(namespace),
+		chaos.WithWorkflowSpec(workflowSpec),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to build workflow %s: %w", name, err)
+	}
+
+	if _, err := workflowChaos.ValidateCreate(); err != nil {
+		return "", fmt.Errorf("failed to validate workflow %s: %w", name, err)
+	}
+
+	if err := cli.Create(ctx, workflowChaos); err != nil {
+		return "", fmt.Errorf("failed to create workflow %s: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// nextTemplates returns the templates the template can continue with
+func nextTemplates(template v1alpha1.Template) []string {
+	next := append([]string(nil), template.Children...)
+	for _, branch := range template.ConditionalBranches {
+		next = append(next, branch.Target)
+	}
+	return next
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorkflowBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *WorkflowBuilder
+		want    string
+	}{
+		{
+			name: "duplicate name",
+			builder: NewWorkflowBuilder("flow0", "").
+				Serial("entry", "", "sleep").
+				Suspend("sleep", "1m").
+				Suspend("sleep", "2m"),
+			want: `template name "sleep" is duplicated`,
+		},
+		{
+			name: "unreachable",
+			builder: NewWorkflowBuilder("flow0", "").
+				Serial("entry", "", "sleep").
+				Suspend("sleep", "1m").
+				Suspend("orphan", "1m"),
+			want: `template "orphan" is not reachable`,
+		},
+		{
+			name: "missing child",
+			builder: NewWorkflowBuilder("flow0", "").
+				Parallel("entry", "", "missing"),
+			want: `refers to the missing template "missing"`,
+		},
+		{
+			name: "cycle",
+			builder: NewWorkflowBuilder("flow0", "").
+				Serial("entry", "", "loop").
+				Serial("loop", "", "entry"),
+			want: "part of a cycle",
+		},
+		{
+			name: "suspend without deadline",
+			builder: NewWorkflowBuilder("flow0", "").
+				Serial("entry", "", "sleep").
+				Suspend("sleep", ""),
+			want: "suspend requires a deadline",
+		},
+		{
+			name: "child deadline exceeds parent",
+			builder: NewWorkflowBuilder("flow0", "").
+				Serial("entry", "1m", "sleep").
+				Suspend("sleep", "5m"),
+			want: "exceeds the deadline",
+		},
+		{
+			name: "invalid deadline",
+			builder: NewWorkflowBuilder("flow0", "").
+				Suspend("entry", "soon"),
+			want: `invalid deadline "soon"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Build() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	chaos "github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	controllers "github.com/LGU-SE-Internal/chaos-experiment/controllers"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	"k8s.io/utils/pointer"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BlockDelaySpec defines the block device latency chaos injection parameters, BlockVolumeIdx only ranges
+// over the volumes backed by a persistent volume claim since configmaps, emptyDirs and the like have
+// no block device of their own
+type BlockDelaySpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	BlockVolumeIdx  int `range:"0-0" dynamic:"true" description:"Flattened claim-backed container volume index"`
+	Latency         int `range:"1-5000" description:"Latency in milliseconds"`
+	Correlation     int `range:"0-100" description:"Correlation percentage"`
+	Jitter          int `range:"0-1000" description:"Jitter in milliseconds"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
+}
+
+// Helper function to validate and get a claim-backed volume from index
+func getBlockVolumeByIndex(namespace string, volumeIdx int) (*resourcelookup.VolumeInfo, error) {
+	volumes, err := resourcelookup.GetAllBlockVolumes(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block volumes: %w", err)
+	}
+
+	if volumeIdx < 0 || volumeIdx >= len(volumes) {
+		return nil, fmt.Errorf("block volume index out of range: %d (max: %d)", volumeIdx, len(volumes)-1)
+	}
+
+	return &volumes[volumeIdx], nil
+}
+
+func (s *BlockDelaySpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	volume, err := getBlockVolumeByIndex(ns, s.BlockVolumeIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+
+	optss := []chaos.OptBlockChaos{
+		chaos.WithBlockDelayAction(fmt.Sprintf("%dms", s.Latency), strconv.Itoa(s.Correlation), fmt.Sprintf("%dms", s.Jitter)),
+		chaos.WithBlockContainerNames([]string{volume.ContainerName}),
+	}
+
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(volume.AppLabel)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithBlockPodSelector(selector...))
+
+	return controllers.CreateBlockChaos(cli, ctx, ns, volume.AppLabel, volume.VolumeName, duration, annotations, labels, optss...)
+}
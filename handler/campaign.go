@@ -0,0 +1,747 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Campaign is a plan of injections, loaded from YAML or JSON with LoadCampaignFile
+type Campaign struct {
+	Name string `json:"name"`
+	// Namespaces maps each namespace prefix to its number of targets, as passed to InitTargetConfig
+	Namespaces map[string]int `json:"namespaces"`
+	LabelKey   string         `json:"label_key,omitempty"`
+	// Targets restricts the namespace target indices injected into, by default all of them
+	Targets []int `json:"targets,omitempty"`
+	// Parallel injects into every target at the same time instead of one injection at a time
+	Parallel    bool              `json:"parallel,omitempty"`
+	Cooldown    string            `json:"cooldown,omitempty"`
+	Repetitions int               `json:"repetitions,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Faults      []CampaignFault   `json:"faults"`
+}
+
+// CampaignFault is one fault type of a campaign. The injection points are selected by index, by
+// stable identifier or by app, without a selector every injection point of the type is used.
+type CampaignFault struct {
+	Type string `json:"type"`
+	// Duration in minutes
+	Duration        int            `json:"duration"`
+	Params          map[string]int `json:"params,omitempty"`
+	Apps            []string       `json:"apps,omitempty"`
+	Points          []int          `json:"points,omitempty"`
+	InjectionPoints []string       `json:"injection_points,omitempty"`
+	// Cooldown and Repetitions override the campaign defaults
+	Cooldown    string `json:"cooldown,omitempty"`
+	Repetitions int    `json:"repetitions,omitempty"`
+}
+
+// CampaignInjection is one expanded injection of a campaign. The ID is derived from the plan and the
+// stable identifier of the injection point, so it is the same on every expansion of the plan.
+type CampaignInjection struct {
+	ID         string
+	Prefix     string
+	Fault      int
+	Repetition int
+	Conf       *InjectionConf
+	Duration   time.Duration
+	Cooldown   time.Duration
+}
+
+// campaignLeaseWait is how often a campaign polls for the lease of a target namespace someone else holds
+const campaignLeaseWait = 10 * time.Second
+
+// CampaignInjectionLabelKey labels the chaos objects of a campaign injection with a hash of the
+// campaign name and the injection ID, so a resumed run finds what an interrupted one created
+const CampaignInjectionLabelKey = "chaos-experiment/campaign-injection"
+
+// CampaignRecord is one line of the run log
+type CampaignRecord struct {
+	ID          string       `json:"id"`
+	ChaosType   string       `json:"chaos_type"`
+	Namespace   string       `json:"namespace"`
+	Name        string       `json:"name,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	InjectedAt  time.Time    `json:"injected_at"`
+	RecoversAt  time.Time    `json:"recovers_at"`
+	Cooldown    string       `json:"cooldown,omitempty"`
+	Groundtruth *Groundtruth `json:"groundtruth,omitempty"`
+	// GroundtruthError is why the groundtruth of a created chaos object could not be resolved, the
+	// injection itself still succeeded
+	GroundtruthError string `json:"groundtruth_error,omitempty"`
+	// Pending marks the record written right before the chaos object is created, it is followed by
+	// the outcome unless the run crashed in between
+	Pending bool `json:"pending,omitempty"`
+	// DryRun marks records of a run with WithCampaignDryRun, which only rendered the objects
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// Succeeded reports whether the chaos object of the record was created
+func (r CampaignRecord) Succeeded() bool {
+	return r.Name != "" && r.Error == "" && !r.Pending
+}
+
+type CampaignConf struct {
+	Client cli.Client
+	Sleep  func(ctx context.Context, d time.Duration) error
+	DryRun bool
+}
+
+type CampaignOption func(*CampaignConf)
+
+// WithCampaignClient injects through the client instead of the registered providers
+func WithCampaignClient(k8sClient cli.Client) CampaignOption {
+	return func(c *CampaignConf) {
+		c.Client = k8sClient
+	}
+}
+
+// WithCampaignSleep replaces waiting for the fault duration and cool-down, e.g. to render a campaign
+// through a client.DryRunClient without waiting
+func WithCampaignSleep(sleep func(ctx context.Context, d time.Duration) error) CampaignOption {
+	return func(c *CampaignConf) {
+		c.Sleep = sleep
+	}
+}
+
+// WithCampaignDryRun marks the records of the run as dry-run. Dry-run records and the records of real
+// runs sharing a log do not count as done for each other, so rendering a campaign into the log of a
+// real run does not make the real run skip its injections.
+func WithCampaignDryRun() CampaignOption {
+	return func(c *CampaignConf) {
+		c.DryRun = true
+	}
+}
+
+// LoadCampaignFile reads a campaign plan in YAML or JSON
+func LoadCampaignFile(filename string) (*Campaign, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read campaign file %s: %w", filename, err)
+	}
+
+	campaign := &Campaign{}
+	if err := yaml.Unmarshal(data, campaign); err != nil {
+		return nil, fmt.Errorf("failed to parse campaign file %s: %w", filename, err)
+	}
+
+	if campaign.Name == "" {
+		return nil, fmt.Errorf("campaign file %s has no name", filename)
+	}
+	if len(campaign.Faults) == 0 {
+		return nil, fmt.Errorf("campaign file %s has no fault", filename)
+	}
+
+	return campaign, nil
+}
+
+// Expand turns the plan into injections against the configured namespace prefixes, repetition by
+// repetition. Prefixes of the plan that are not configured are rejected.
+func (c *Campaign) Expand() ([]CampaignInjection, error) {
+	prefixes := sortedKeys(c.Namespaces)
+	if len(prefixes) == 0 {
+		prefixes = NamespacePrefixs
+	}
+
+	for _, prefix := range prefixes {
+		if !slices.Contains(NamespacePrefixs, prefix) {
+			return nil, fmt.Errorf("namespace prefix %s is not configured (available: %v)", prefix, NamespacePrefixs)
+		}
+	}
+
+	var result []CampaignInjection
+	for faultIdx, fault := range c.Faults {
+		if _, ok := chaosTypeByName(fault.Type); !ok {
+			return nil, fmt.Errorf("fault %d: unknown chaos type: %s", faultIdx, fault.Type)
+		}
+		if fault.Duration <= 0 {
+			return nil, fmt.Errorf("fault %d: duration must be positive, got %d", faultIdx, fault.Duration)
+		}
+
+		repetitions := fault.Repetitions
+		if repetitions <= 0 {
+			repetitions = max(c.Repetitions, 1)
+		}
+
+		cooldown, err := parseCooldown(fault.Cooldown, c.Cooldown)
+		if err != nil {
+			return nil, fmt.Errorf("fault %d: %w", faultIdx, err)
+		}
+
+		for _, prefix := range prefixes {
+			points, err := fault.selectPoints(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("fault %d (%s): %w", faultIdx, fault.Type, err)
+			}
+
+			for _, point := range points {
+				// every repetition gets its own conf, the create sets the target namespace on it and
+				// repetitions may run in parallel
+				for rep := range repetitions {
+					conf, err := fault.conf(prefix, point.idx)
+					if err != nil {
+						return nil, fmt.Errorf("fault %d (%s): %w", faultIdx, fault.Type, err)
+					}
+
+					result = append(result, CampaignInjection{
+						ID:         fmt.Sprintf("%d-%s-%s-%s-%d", faultIdx, fault.Type, prefix, point.id, rep),
+						Prefix:     prefix,
+						Fault:      faultIdx,
+						Repetition: rep,
+						Conf:       conf,
+						Duration:   time.Duration(fault.Duration) * time.Minute,
+						Cooldown:   cooldown,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b CampaignInjection) int {
+		return a.Repetition - b.Repetition
+	})
+
+	return result, nil
+}
+
+type campaignPoint struct {
+	idx int
+	id  string
+}
+
+// selectPoints returns the injection points of the fault in the system of the prefix
+func (f CampaignFault) selectPoints(prefix string) ([]campaignPoint, error) {
+	entry, ok := chaosTypeByName(f.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown chaos type: %s", f.Type)
+	}
+
+	pointField, ok := injectionPointField(entry.specType)
+	if !ok {
+		return []campaignPoint{{idx: 0, id: "none"}}, nil
+	}
+
+	kind := injectionPointKinds[pointField]
+	system, err := currentSystem(prefix, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := system.PointIDs(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []campaignPoint
+	for _, idx := range f.Points {
+		if idx < 0 || idx >= len(ids) {
+			return nil, fmt.Errorf("%s out of range: %d (max: %d)", pointField, idx, len(ids)-1)
+		}
+		result = append(result, campaignPoint{idx: idx, id: ids[idx]})
+	}
+
+	for _, id := range f.InjectionPoints {
+		idx, err := system.IndexOf(kind, id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, campaignPoint{idx: idx, id: id})
+	}
+
+	if len(f.Apps) > 0 {
+		for idx, id := range ids {
+			target, err := resolveTarget(system, f.Type, pointField, idx)
+			if err != nil {
+				return nil, err
+			}
+			if slices.Contains(f.Apps, target.App) {
+				result = append(result, campaignPoint{idx: idx, id: id})
+			}
+		}
+
+		if len(result) == 0 {
+			return nil, fmt.Errorf("no %s of the apps %v in namespace prefix %s", kind, f.Apps, prefix)
+		}
+	}
+
+	if len(f.Points) == 0 && len(f.InjectionPoints) == 0 && len(f.Apps) == 0 {
+		for idx, id := range ids {
+			result = append(result, campaignPoint{idx: idx, id: id})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b campaignPoint) int { return a.idx - b.idx })
+	return slices.CompactFunc(result, func(a, b campaignPoint) bool { return a.idx == b.idx }), nil
+}
+
+// conf builds the injection of the fault at the injection point index
+func (f CampaignFault) conf(prefix string, pointIdx int) (*InjectionConf, error) {
+	entry, ok := chaosTypeByName(f.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown chaos type: %s", f.Type)
+	}
+
+	conf := &InjectionConf{}
+	activeField := entry.newSpec()
+	conf.setSpec(entry, activeField)
+
+	values := map[string]int{
+		"Duration":   f.Duration,
+		KeyNamespace: slices.Index(NamespacePrefixs, prefix),
+	}
+	if pointField, ok := injectionPointField(activeField.Type().Elem()); ok {
+		values[pointField] = pointIdx
+	}
+	maps.Copy(values, f.Params)
+
+	for name, value := range values {
+		if !activeField.Elem().FieldByName(name).IsValid() {
+			return nil, fmt.Errorf("%s has no field %s", f.Type, name)
+		}
+		if err := setIntValue(activeField, name, value); err != nil {
+			return nil, fmt.Errorf("field '%s': %w", name, err)
+		}
+	}
+
+	return conf, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func parseCooldown(values ...string) (time.Duration, error) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		cooldown, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cooldown %q: %w", value, err)
+		}
+		return cooldown, nil
+	}
+
+	return 0, nil
+}
+
+// ReadCampaignLog reads the records of a run log, a missing log has no records
+func ReadCampaignLog(filename string) ([]CampaignRecord, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open run log %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var records []CampaignRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record CampaignRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// the last line may be cut off by a crash
+			break
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run log %s: %w", filename, err)
+	}
+
+	return records, nil
+}
+
+// RunCampaign injects the campaign and appends a record per injection to the run log. Injections
+// already recorded as succeeded in the log are skipped, so a crashed run is resumed by running the
+// same plan against the same log. An injection whose creation was interrupted is looked up in its
+// namespace by CampaignInjectionLabelKey and only injected again if no chaos object was created.
+// Each target namespace gets one injection at a time, waiting for the fault duration and the
+// cool-down before the next one. Outside of dry runs every injection leases its target namespace for
+// the fault duration and the cool-down, waiting while another holder has it, see AcquireTargetLease.
+func RunCampaign(ctx context.Context, campaign *Campaign, logPath string, opts ...CampaignOption) ([]CampaignRecord, error) {
+	conf := CampaignConf{Sleep: sleepContext}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	injections, err := campaign.Expand()
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := ReadCampaignLog(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]bool, len(previous))
+	pending := make(map[string]CampaignRecord)
+	busyUntil := make(map[string]time.Time)
+	markDone := func(record CampaignRecord) {
+		done[record.ID] = true
+		cooldown, _ := time.ParseDuration(record.Cooldown)
+		if until := record.RecoversAt.Add(cooldown); until.After(busyUntil[record.Namespace]) {
+			busyUntil[record.Namespace] = until
+		}
+	}
+	for _, record := range previous {
+		if record.DryRun != conf.DryRun {
+			continue
+		}
+		if record.Pending {
+			pending[record.ID] = record
+			continue
+		}
+
+		delete(pending, record.ID)
+		if record.Succeeded() {
+			markDone(record)
+		}
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run log %s: %w", logPath, err)
+	}
+	defer logFile.Close()
+
+	run := &campaignRun{
+		campaign: campaign,
+		conf:     conf,
+		log:      logFile,
+	}
+
+	confs := make(map[string]*InjectionConf, len(injections))
+	for _, injection := range injections {
+		confs[injection.ID] = injection.Conf
+	}
+	for _, id := range sortedKeys(pending) {
+		record, err := run.reconcile(ctx, pending[id], confs[id])
+		if err != nil {
+			return run.records, err
+		}
+		if record.Succeeded() {
+			markDone(record)
+		}
+	}
+
+	queues := make(map[string][]CampaignInjection)
+	for _, injection := range injections {
+		if !done[injection.ID] {
+			queues[injection.Prefix] = append(queues[injection.Prefix], injection)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, prefix := range sortedKeys(queues) {
+		targets := campaign.Targets
+		if len(targets) == 0 {
+			for idx := DefaultStartIndex; idx < DefaultStartIndex+NamespaceTargetMap[prefix]; idx++ {
+				targets = append(targets, idx)
+			}
+		}
+		if len(targets) == 0 {
+			run.fail(fmt.Errorf("namespace prefix %s has no target", prefix))
+			continue
+		}
+
+		if !campaign.Parallel {
+			for i, injection := range queues[prefix] {
+				target := targets[i%len(targets)]
+				if err := run.wait(ctx, GetTargetNamespace(slices.Index(NamespacePrefixs, prefix), target), busyUntil); err != nil {
+					run.fail(err)
+					break
+				}
+				if err := run.inject(ctx, injection, target, busyUntil); err != nil {
+					run.fail(err)
+					break
+				}
+			}
+			continue
+		}
+
+		queue := make(chan CampaignInjection, len(queues[prefix]))
+		for _, injection := range queues[prefix] {
+			queue <- injection
+		}
+		close(queue)
+
+		for _, target := range targets {
+			namespace := GetTargetNamespace(slices.Index(NamespacePrefixs, prefix), target)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for injection := range queue {
+					if err := run.wait(ctx, namespace, busyUntil); err != nil {
+						run.fail(err)
+						return
+					}
+					if err := run.inject(ctx, injection, target, busyUntil); err != nil {
+						run.fail(err)
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if run.err != nil {
+		return run.records, run.err
+	}
+
+	var failed []error
+	for _, record := range run.records {
+		if record.Error != "" {
+			failed = append(failed, fmt.Errorf("%s: %s", record.ID, record.Error))
+		}
+	}
+
+	return run.records, errors.Join(failed...)
+}
+
+type campaignRun struct {
+	campaign *Campaign
+	conf     CampaignConf
+
+	mu      sync.Mutex
+	log     *os.File
+	records []CampaignRecord
+	err     error
+}
+
+// wait sleeps until the fault and cool-down of the last injection into the namespace are over
+func (r *campaignRun) wait(ctx context.Context, namespace string, busyUntil map[string]time.Time) error {
+	r.mu.Lock()
+	until := busyUntil[namespace]
+	r.mu.Unlock()
+
+	if d := time.Until(until); d > 0 {
+		return r.conf.Sleep(ctx, d)
+	}
+	return ctx.Err()
+}
+
+// injectionLabel returns the CampaignInjectionLabelKey value of an injection, injection IDs can be
+// longer than a label value may be
+func (r *campaignRun) injectionLabel(id string) string {
+	sum := sha256.Sum256([]byte(r.campaign.Name + "\x00" + id))
+	return hex.EncodeToString(sum[:])[:32]
+}
+
+// reconcile settles an injection whose run stopped between its pending record and its outcome. If a
+// chaos object of the injection exists the injection is recorded as created, otherwise the record
+// stays pending and the injection is injected again.
+func (r *campaignRun) reconcile(ctx context.Context, record CampaignRecord, conf *InjectionConf) (CampaignRecord, error) {
+	opts := []CleanupOption{
+		WithCleanupNamespaces(record.Namespace),
+		WithLabelSelector(map[string]string{CampaignInjectionLabelKey: r.injectionLabel(record.ID)}),
+	}
+	if r.conf.Client != nil {
+		opts = append(opts, WithCleanupClient(r.conf.Client))
+	}
+
+	objects, err := ListChaos(ctx, opts...)
+	if err != nil {
+		return record, fmt.Errorf("failed to look up interrupted injection %s: %w", record.ID, err)
+	}
+
+	if len(objects) == 0 {
+		return record, nil
+	}
+
+	record.Pending = false
+	record.Name = objects[0].Name
+	if conf != nil {
+		if err := setCampaignTarget(conf, record.Namespace); err != nil {
+			record.GroundtruthError = err.Error()
+		} else {
+			record.Groundtruth, record.GroundtruthError = campaignGroundtruth(conf)
+		}
+	}
+
+	return record, r.append(record)
+}
+
+// inject creates one injection and records it, a failed injection is recorded and the run goes on
+func (r *campaignRun) inject(ctx context.Context, injection CampaignInjection, target int, busyUntil map[string]time.Time) error {
+	labels := map[string]string{ExperimentIDLabelKey: r.campaign.Name}
+	maps.Copy(labels, r.campaign.Labels)
+	labels[CampaignInjectionLabelKey] = r.injectionLabel(injection.ID)
+
+	namespace := GetTargetNamespace(slices.Index(NamespacePrefixs, injection.Prefix), target)
+	record := CampaignRecord{
+		ID:         injection.ID,
+		ChaosType:  r.campaign.Faults[injection.Fault].Type,
+		Namespace:  namespace,
+		InjectedAt: time.Now(),
+		Cooldown:   injection.Cooldown.String(),
+		DryRun:     r.conf.DryRun,
+	}
+
+	var leaseOpts []LeaseOption
+	if r.conf.Client != nil {
+		leaseOpts = append(leaseOpts, WithLeaseClient(r.conf.Client))
+	}
+
+	var lease *Lease
+	if !r.conf.DryRun {
+		var err error
+		lease, err = AcquireTargetLease(ctx, injection.Prefix, target, injection.Duration,
+			append(leaseOpts, WithLeaseCooldown(injection.Cooldown), WithLeaseWait(campaignLeaseWait))...)
+		if err != nil {
+			return fmt.Errorf("failed to lease namespace %s for %s: %w", namespace, injection.ID, err)
+		}
+	}
+
+	// the pending record goes first, so a crash during Create is found again on resume
+	intent := record
+	intent.Pending = true
+	intent.RecoversAt = record.InjectedAt.Add(injection.Duration)
+	if err := r.write(intent); err != nil {
+		return err
+	}
+
+	var name string
+	var err error
+	if r.conf.Client != nil {
+		name, err = injection.Conf.CreateWithClient(ctx, r.conf.Client, target, r.campaign.Annotations, labels)
+	} else {
+		name, err = injection.Conf.Create(ctx, target, r.campaign.Annotations, labels)
+	}
+	if err != nil {
+		record.Error = err.Error()
+		if lease != nil {
+			if releaseErr := ReleaseLease(ctx, lease, append(leaseOpts, WithLeaseCooldown(0))...); releaseErr != nil {
+				record.Error = errors.Join(err, releaseErr).Error()
+			}
+		}
+	} else {
+		record.Name = name
+		record.RecoversAt = record.InjectedAt.Add(injection.Duration)
+		record.Groundtruth, record.GroundtruthError = campaignGroundtruth(injection.Conf)
+	}
+
+	if err := r.append(record); err != nil {
+		return err
+	}
+	if !record.Succeeded() {
+		return nil
+	}
+
+	r.mu.Lock()
+	busyUntil[namespace] = record.RecoversAt.Add(injection.Cooldown)
+	r.mu.Unlock()
+
+	return r.conf.Sleep(ctx, injection.Duration+injection.Cooldown)
+}
+
+// campaignGroundtruth resolves the groundtruth of a created injection for its record
+func campaignGroundtruth(conf *InjectionConf) (*Groundtruth, string) {
+	groundtruth, err := conf.GetGroundtruth()
+	if err != nil {
+		return nil, err.Error()
+	}
+	return &groundtruth, ""
+}
+
+// setCampaignTarget sets the target index of the namespace on the conf, which the interrupted run
+// had set when it created the chaos
+func setCampaignTarget(conf *InjectionConf, namespace string) error {
+	activeField, err := conf.getActiveField()
+	if err != nil {
+		return err
+	}
+
+	prefix := NamespacePrefixs[int(activeField.Elem().FieldByName(KeyNamespace).Int())]
+	target, err := strconv.Atoi(strings.TrimPrefix(namespace, prefix))
+	if err != nil || !strings.HasPrefix(namespace, prefix) {
+		return fmt.Errorf("namespace %s is not a target namespace of %s", namespace, prefix)
+	}
+
+	return setIntValue(activeField, KeyNamespaceTarget, target)
+}
+
+// append writes the outcome of an injection to the run log and keeps it for the result of the run
+func (r *campaignRun) append(record CampaignRecord) error {
+	if err := r.write(record); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, record)
+	return nil
+}
+
+// write appends the record to the run log and syncs it before returning
+func (r *campaignRun) write(record CampaignRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record %s: %w", record.ID, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.log.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write run log: %w", err)
+	}
+	if err := r.log.Sync(); err != nil {
+		return fmt.Errorf("failed to sync run log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *campaignRun) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testCampaign = `
+name: nightly
+namespaces:
+  campsys: 2
+parallel: true
+cooldown: 2m
+repetitions: 2
+faults:
+  - type: PodFailure
+    duration: 5
+    apps: [frontend]
+  - type: CPUStress
+    duration: 3
+    points: [0]
+    params:
+      CPULoad: 80
+      CPUWorker: 2
+    repetitions: 1
+`
+
+func TestRunCampaign(t *testing.T) {
+	useTestSystem(t, "campsys", 2, newTestClient(t, testPod("campsys0", "cartservice"), testPod("campsys0", "frontend")))
+
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "nightly.yaml")
+	if err := os.WriteFile(planPath, []byte(testCampaign), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	campaign, err := LoadCampaignFile(planPath)
+	if err != nil {
+		t.Fatalf("LoadCampaignFile() error = %v", err)
+	}
+
+	injections, err := campaign.Expand()
+	if err != nil {
+		t.Fatalf("Expand() error = %v", err)
+	}
+	if len(injections) != 3 || injections[0].Repetition != 0 || injections[2].Repetition != 1 {
+		t.Fatalf("Expand() = %d injections, want 2 repetitions of PodFailure and 1 of CPUStress", len(injections))
+	}
+	if injections[0].Conf.PodFailure.AppIdx != 1 {
+		t.Errorf("PodFailure AppIdx = %d, want the index of frontend", injections[0].Conf.PodFailure.AppIdx)
+	}
+	if injections[1].Conf.CPUStress.CPULoad != 80 || injections[1].Cooldown != 2*time.Minute {
+		t.Errorf("CPUStress injection = %+v, want the params and the campaign cooldown", injections[1])
+	}
+
+	sleep := func(ctx context.Context, d time.Duration) error { return nil }
+
+	dryRun, err := client.NewDryRunClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logPath := filepath.Join(dir, "nightly.jsonl")
+
+	// a dry run into the log does not count as done for the real run
+	rendered, err := RunCampaign(context.Background(), campaign, logPath, WithCampaignClient(dryRun), WithCampaignSleep(sleep), WithCampaignDryRun())
+	if err != nil {
+		t.Fatalf("RunCampaign() dry run error = %v", err)
+	}
+	if len(rendered) != 3 || !rendered[0].DryRun {
+		t.Fatalf("dry run = %+v, want 3 dry-run records", rendered)
+	}
+
+	// the real run leases its target namespaces
+	namespaces := []cli.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "campsys0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "campsys1"}},
+	}
+	dryRun, err = client.NewDryRunClient(client.WithInitObjects(namespaces...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := RunCampaign(context.Background(), campaign, logPath, WithCampaignClient(dryRun), WithCampaignSleep(sleep))
+	if err != nil {
+		t.Fatalf("RunCampaign() error = %v", err)
+	}
+	if len(records) != 3 || len(dryRun.Objects()) != 3 {
+		t.Fatalf("RunCampaign() = %d records and %d objects, want 3", len(records), len(dryRun.Objects()))
+	}
+
+	leases, err := ListLeases(context.Background(), "campsys", WithLeaseClient(dryRun))
+	if err != nil {
+		t.Fatal(err)
+	}
+	leased := make(map[string]bool)
+	for _, lease := range leases {
+		leased[lease.Namespace] = true
+	}
+	for _, record := range records {
+		if !leased[record.Namespace] {
+			t.Errorf("namespace %s of %s is not leased, leases = %v", record.Namespace, record.ID, leases)
+		}
+	}
+
+	for _, obj := range dryRun.Objects() {
+		if obj.GetLabels()[ExperimentIDLabelKey] != "nightly" {
+			t.Errorf("%s labels = %v, want the campaign name as experiment ID", obj.GetName(), obj.GetLabels())
+		}
+	}
+	for _, record := range records {
+		if !record.Succeeded() || record.Groundtruth == nil || record.RecoversAt.IsZero() {
+			t.Errorf("record = %+v, want a succeeded injection with groundtruth", record)
+		}
+	}
+
+	// every injection has a pending record before its outcome, after the six of the dry run
+	logged, err := ReadCampaignLog(logPath)
+	if err != nil || len(logged) != 12 || !logged[6].Pending || logged[7].Pending {
+		t.Fatalf("ReadCampaignLog() = %d records, error = %v", len(logged), err)
+	}
+
+	// a rerun resumes the campaign, everything is already injected
+	resumed, err := RunCampaign(context.Background(), campaign, logPath, WithCampaignClient(dryRun), WithCampaignSleep(sleep))
+	if err != nil {
+		t.Fatalf("RunCampaign() resume error = %v", err)
+	}
+	if len(resumed) != 0 || len(dryRun.Objects()) != 3 {
+		t.Errorf("resumed run injected %d more, want none", len(resumed))
+	}
+
+	// a run that crashed while creating finds what it created and injects the rest again
+	crashLog := filepath.Join(dir, "crashed.jsonl")
+	var lines []byte
+	for _, injection := range injections[:2] {
+		data, _ := json.Marshal(CampaignRecord{ID: injection.ID, ChaosType: campaign.Faults[injection.Fault].Type, Namespace: "campsys0", Pending: true})
+		lines = append(append(lines, data...), '\n')
+	}
+	if err := os.WriteFile(crashLog, lines, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	created := &chaosmeshv1alpha1.PodChaos{ObjectMeta: metav1.ObjectMeta{
+		Name:      "created-before-crash",
+		Namespace: "campsys0",
+		Labels:    map[string]string{CampaignInjectionLabelKey: (&campaignRun{campaign: campaign}).injectionLabel(injections[0].ID)},
+	}}
+	crashed, err := client.NewDryRunClient(client.WithInitObjects(append(namespaces, created)...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err = RunCampaign(context.Background(), campaign, crashLog, WithCampaignClient(crashed), WithCampaignSleep(sleep))
+	if err != nil {
+		t.Fatalf("RunCampaign() after a crash error = %v", err)
+	}
+	if len(records) != 3 || records[0].Name != "created-before-crash" || len(crashed.Objects()) != 2 {
+		t.Errorf("RunCampaign() after a crash = %+v with %d objects, want the created injection kept and 2 created", records, len(crashed.Objects()))
+	}
+	if records[0].Groundtruth == nil || records[0].GroundtruthError != "" {
+		t.Errorf("reconciled record = %+v, want the groundtruth of the created injection", records[0])
+	}
+}
+
+const testParallelCampaign = `
+name: parallel
+namespaces:
+  parsys: 2
+parallel: true
+repetitions: 6
+faults:
+  - type: PodFailure
+    duration: 1
+    apps: [frontend]
+`
+
+// TestRunCampaignParallelRepetitions runs the repetitions of one injection point in parallel, run it
+// with -race to catch repetitions sharing their conf
+func TestRunCampaignParallelRepetitions(t *testing.T) {
+	// the pods of the two target namespaces have different names, so the groundtruth tells them apart
+	other := testPod("parsys1", "frontend")
+	other.Name = "frontend-1"
+	useTestSystem(t, "parsys", 2, newTestClient(t, testPod("parsys0", "frontend"), other))
+
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "parallel.yaml")
+	if err := os.WriteFile(planPath, []byte(testParallelCampaign), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	campaign, err := LoadCampaignFile(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	injections, err := campaign.Expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(injections); i++ {
+		if injections[i].Conf == injections[0].Conf {
+			t.Fatalf("repetitions %d and 0 share their conf", i)
+		}
+	}
+
+	dryRun, err := client.NewDryRunClient(client.WithInitObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "parsys0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "parsys1"}},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sleep := func(ctx context.Context, d time.Duration) error { return nil }
+	records, err := RunCampaign(context.Background(), campaign, filepath.Join(dir, "parallel.jsonl"), WithCampaignClient(dryRun), WithCampaignSleep(sleep))
+	if err != nil {
+		t.Fatalf("RunCampaign() error = %v", err)
+	}
+	if len(records) != 6 {
+		t.Fatalf("RunCampaign() = %d records, want 6", len(records))
+	}
+
+	wantPods := map[string]string{"parsys0": "frontend-0", "parsys1": "frontend-1"}
+	for _, record := range records {
+		if record.Groundtruth == nil || !reflect.DeepEqual(record.Groundtruth.Pod, []string{wantPods[record.Namespace]}) {
+			t.Errorf("record %s in %s has groundtruth %+v, want the pod %s", record.ID, record.Namespace, record.Groundtruth, wantPods[record.Namespace])
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// ManagedByLabelKey marks every chaos object and workflow created through this library, the
+	// panic button only touches objects carrying it
+	ManagedByLabelKey   = chaos.ManagedByLabelKey
+	ManagedByLabelValue = chaos.ManagedByLabelValue
+
+	// ExperimentIDLabelKey is the label callers put the ID of their run under, so the run can be
+	// cleaned up with WithExperimentID
+	ExperimentIDLabelKey = "chaos-experiment/experiment-id"
+
+	// Chaos Mesh drops the finalizers of an object carrying this annotation, so deletion does not
+	// hang when the daemon cannot recover the target
+	cleanFinalizerAnnotationKey    = "chaos-mesh.chaos-mesh.org/cleanFinalizer"
+	cleanFinalizerAnnotationForced = "forced"
+
+	// DefaultCleanupTimeout is how long PanicButton waits for Chaos Mesh to recover the targets
+	// before it forces the cleanup of what is left
+	DefaultCleanupTimeout = time.Minute
+	cleanupPollInterval   = time.Second
+
+	workflowKind = "Workflow"
+)
+
+// ChaosObject is a chaos object of any kind matched by a cleanup selection
+type ChaosObject struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Paused    bool              `json:"paused"`
+	// Active is set until Chaos Mesh reports every target recovered, or a workflow accomplished
+	Active bool `json:"active"`
+	// Forced is set when the finalizers were dropped because the recovery did not finish in time
+	Forced bool `json:"forced,omitempty"`
+
+	object cli.Object
+}
+
+type CleanupConf struct {
+	Client     cli.Client
+	Namespaces []string
+	Selector   map[string]string
+	Force      bool
+	Timeout    time.Duration
+}
+
+type CleanupOption func(*CleanupConf)
+
+// WithCleanupClient uses the client for every namespace instead of the registered providers
+func WithCleanupClient(k8sClient cli.Client) CleanupOption {
+	return func(c *CleanupConf) {
+		c.Client = k8sClient
+	}
+}
+
+// WithCleanupNamespaces restricts the selection to the namespaces, by default every target
+// namespace of every configured prefix is searched
+func WithCleanupNamespaces(namespaces ...string) CleanupOption {
+	return func(c *CleanupConf) {
+		c.Namespaces = append(c.Namespaces, namespaces...)
+	}
+}
+
+// WithLabelSelector only selects objects carrying all the labels
+func WithLabelSelector(selector map[string]string) CleanupOption {
+	return func(c *CleanupConf) {
+		maps.Copy(c.Selector, selector)
+	}
+}
+
+// WithExperimentID only selects objects labeled with the experiment ID, see ExperimentIDLabelKey
+func WithExperimentID(id string) CleanupOption {
+	return func(c *CleanupConf) {
+		c.Selector[ExperimentIDLabelKey] = id
+	}
+}
+
+// WithForceCleanup lets Chaos Mesh drop the finalizers before deleting, for objects whose
+// recovery is stuck. The injected fault may be left behind on the target.
+func WithForceCleanup() CleanupOption {
+	return func(c *CleanupConf) {
+		c.Force = true
+	}
+}
+
+// WithCleanupTimeout sets how long PanicButton waits for the deleted objects to go away before
+// forcing their cleanup, defaults to DefaultCleanupTimeout
+func WithCleanupTimeout(timeout time.Duration) CleanupOption {
+	return func(c *CleanupConf) {
+		c.Timeout = timeout
+	}
+}
+
+func newCleanupConf(opts []CleanupOption) CleanupConf {
+	conf := CleanupConf{Selector: make(map[string]string), Timeout: DefaultCleanupTimeout}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	if len(conf.Namespaces) == 0 {
+		for _, prefix := range NamespacePrefixs {
+			for idx := DefaultStartIndex; idx < DefaultStartIndex+NamespaceTargetMap[prefix]; idx++ {
+				conf.Namespaces = append(conf.Namespaces, fmt.Sprintf("%s%d", prefix, idx))
+			}
+		}
+	}
+
+	return conf
+}
+
+// ListChaos returns the chaos objects of every kind in client.GetCRDMapping and the workflows that
+// match the selection. Kinds whose CRD is not installed are skipped.
+func ListChaos(ctx context.Context, opts ...CleanupOption) ([]ChaosObject, error) {
+	conf := newCleanupConf(opts)
+	if len(conf.Namespaces) == 0 {
+		return nil, fmt.Errorf("no namespace to search, configure the target namespaces or use WithCleanupNamespaces")
+	}
+
+	var result []ChaosObject
+	for _, namespace := range conf.Namespaces {
+		k8sClient, err := cleanupClient(conf, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		objects, err := listChaosInNamespace(ctx, k8sClient, namespace, conf.Selector)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, objects...)
+	}
+
+	return result, nil
+}
+
+func listChaosInNamespace(ctx context.Context, k8sClient cli.Client, namespace string, selector map[string]string) ([]ChaosObject, error) {
+	kinds := []cli.Object{&chaosmeshv1alpha1.Workflow{}}
+	for _, obj := range client.GetCRDMapping() {
+		kinds = append(kinds, obj)
+	}
+
+	var result []ChaosObject
+	for _, obj := range kinds {
+		gvk, err := apiutil.GVKForObject(obj, k8sClient.Scheme())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the kind of %T: %w", obj, err)
+		}
+
+		listObj, err := k8sClient.Scheme().New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the list of %s: %w", gvk.Kind, err)
+		}
+		list := listObj.(cli.ObjectList)
+
+		err = k8sClient.List(ctx, list, cli.InNamespace(namespace), cli.MatchingLabelsSelector{Selector: labels.SelectorFromSet(selector)})
+		if err != nil {
+			if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s in namespace %s: %w", gvk.Kind, namespace, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", gvk.Kind, err)
+		}
+
+		for _, item := range items {
+			object, ok := item.(cli.Object)
+			if !ok {
+				continue
+			}
+
+			paused := false
+			if inner, ok := object.(chaosmeshv1alpha1.InnerObject); ok {
+				paused = inner.IsPaused()
+			}
+
+			active := true
+			switch object := object.(type) {
+			case chaosmeshv1alpha1.StatefulObject:
+				active = !allRecovered(object.GetStatus())
+			case *chaosmeshv1alpha1.Workflow:
+				active = !accomplished(object)
+			}
+
+			result = append(result, ChaosObject{
+				Kind:      gvk.Kind,
+				Namespace: object.GetNamespace(),
+				Name:      object.GetName(),
+				Labels:    object.GetLabels(),
+				Paused:    paused,
+				Active:    active,
+				object:    object,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// PauseChaos pauses every matching chaos object with the Chaos Mesh pause annotation. Chaos Mesh
+// cannot pause workflows, they are left running; delete them to stop their chaos.
+func PauseChaos(ctx context.Context, opts ...CleanupOption) ([]ChaosObject, error) {
+	return updateChaos(ctx, opts, func(obj *ChaosObject) bool {
+		if obj.Paused || obj.Kind == workflowKind {
+			return false
+		}
+
+		obj.Paused = true
+		setAnnotation(obj.object, chaosmeshv1alpha1.PauseAnnotationKey, "true")
+		return true
+	})
+}
+
+// ResumeChaos removes the pause annotation from every matching chaos object
+func ResumeChaos(ctx context.Context, opts ...CleanupOption) ([]ChaosObject, error) {
+	return updateChaos(ctx, opts, func(obj *ChaosObject) bool {
+		if !obj.Paused || obj.Kind == workflowKind {
+			return false
+		}
+
+		obj.Paused = false
+		setAnnotation(obj.object, chaosmeshv1alpha1.PauseAnnotationKey, "")
+		return true
+	})
+}
+
+// DeleteChaos deletes every matching chaos object and returns the deleted ones. Deleting a workflow
+// deletes the chaos it created.
+func DeleteChaos(ctx context.Context, opts ...CleanupOption) ([]ChaosObject, error) {
+	conf := newCleanupConf(opts)
+
+	objects, err := ListChaos(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []ChaosObject
+	for _, obj := range objects {
+		k8sClient, err := cleanupClient(conf, obj.Namespace)
+		if err != nil {
+			return deleted, err
+		}
+
+		if conf.Force {
+			if err := forceCleanup(ctx, k8sClient, &obj); err != nil {
+				return deleted, err
+			}
+		}
+
+		if err := k8sClient.Delete(ctx, obj.object); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return deleted, fmt.Errorf("failed to delete %s %s/%s: %w", obj.Kind, obj.Namespace, obj.Name, err)
+		}
+
+		deleted = append(deleted, obj)
+	}
+
+	return deleted, nil
+}
+
+// PanicButton deletes every chaos object and workflow this library created in the namespace,
+// regardless of experiment ID. Chaos Mesh gets the cleanup timeout to recover the targets, the
+// objects still there after it are force-cleaned and returned with Forced set.
+func PanicButton(ctx context.Context, namespace string, opts ...CleanupOption) ([]ChaosObject, error) {
+	opts = append(opts,
+		WithCleanupNamespaces(namespace),
+		WithLabelSelector(map[string]string{ManagedByLabelKey: ManagedByLabelValue}),
+	)
+	conf := newCleanupConf(opts)
+
+	deleted, err := DeleteChaos(ctx, opts...)
+	if err != nil {
+		return deleted, err
+	}
+
+	timer := time.NewTimer(conf.Timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(cleanupPollInterval)
+	defer ticker.Stop()
+
+	for {
+		remaining, err := ListChaos(ctx, opts...)
+		if err != nil {
+			return deleted, err
+		}
+		if len(remaining) == 0 {
+			return deleted, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return deleted, fmt.Errorf("stopped waiting for %d chaos objects to be deleted: %w", len(remaining), ctx.Err())
+		case <-ticker.C:
+			continue
+		case <-timer.C:
+		}
+
+		// the recovery is stuck, let Chaos Mesh drop the finalizers of what is left
+		for _, obj := range remaining {
+			k8sClient, err := cleanupClient(conf, obj.Namespace)
+			if err != nil {
+				return deleted, err
+			}
+
+			if err := forceCleanup(ctx, k8sClient, &obj); err != nil {
+				return deleted, err
+			}
+
+			for i := range deleted {
+				if deleted[i].Kind == obj.Kind && deleted[i].Name == obj.Name {
+					deleted[i].Forced = true
+				}
+			}
+		}
+
+		return deleted, nil
+	}
+}
+
+// forceCleanup sets the Chaos Mesh annotation that drops the finalizers of the object
+func forceCleanup(ctx context.Context, k8sClient cli.Client, obj *ChaosObject) error {
+	base := obj.object.DeepCopyObject().(cli.Object)
+	setAnnotation(obj.object, cleanFinalizerAnnotationKey, cleanFinalizerAnnotationForced)
+	if err := k8sClient.Patch(ctx, obj.object, cli.MergeFrom(base)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to force cleanup of %s %s/%s: %w", obj.Kind, obj.Namespace, obj.Name, err)
+	}
+
+	obj.Forced = true
+	return nil
+}
+
+// updateChaos patches every matching object the update function changed and returns those
+func updateChaos(ctx context.Context, opts []CleanupOption, update func(obj *ChaosObject) bool) ([]ChaosObject, error) {
+	conf := newCleanupConf(opts)
+
+	objects, err := ListChaos(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []ChaosObject
+	for _, obj := range objects {
+		k8sClient, err := cleanupClient(conf, obj.Namespace)
+		if err != nil {
+			return updated, err
+		}
+
+		base := obj.object.DeepCopyObject().(cli.Object)
+		if !update(&obj) {
+			continue
+		}
+
+		if err := k8sClient.Patch(ctx, obj.object, cli.MergeFrom(base)); err != nil {
+			return updated, fmt.Errorf("failed to patch %s %s/%s: %w", obj.Kind, obj.Namespace, obj.Name, err)
+		}
+
+		updated = append(updated, obj)
+	}
+
+	return updated, nil
+}
+
+func allRecovered(status *chaosmeshv1alpha1.ChaosStatus) bool {
+	for _, condition := range status.Conditions {
+		if condition.Type == chaosmeshv1alpha1.ConditionAllRecovered {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func accomplished(workflow *chaosmeshv1alpha1.Workflow) bool {
+	for _, condition := range workflow.Status.Conditions {
+		if condition.Type == chaosmeshv1alpha1.WorkflowConditionAccomplished {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func cleanupClient(conf CleanupConf, namespace string) (cli.Client, error) {
+	if conf.Client != nil {
+		return conf.Client, nil
+	}
+	return client.ProviderFor(namespace).Client()
+}
+
+// setAnnotation sets the annotation on the object, an empty value removes it
+func setAnnotation(obj cli.Object, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	if value == "" {
+		delete(annotations, key)
+	} else {
+		annotations[key] = value
+	}
+
+	obj.SetAnnotations(annotations)
+}
+
+// withManagedByLabel returns a copy of the labels carrying ManagedByLabelKey
+func withManagedByLabel(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	maps.Copy(result, labels)
+	result[ManagedByLabelKey] = ManagedByLabelValue
+	return result
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/controllers"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCleanupChaos(t *testing.T) {
+	scheme, err := client.NewScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := func(name, namespace, experimentID string) metav1.ObjectMeta {
+		labels := map[string]string{ManagedByLabelKey: ManagedByLabelValue}
+		if experimentID != "" {
+			labels[ExperimentIDLabelKey] = experimentID
+		}
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&chaosmeshv1alpha1.PodChaos{ObjectMeta: meta("pod-kill", "cleanup0", "run-1")},
+		&chaosmeshv1alpha1.HTTPChaos{ObjectMeta: meta("http-abort", "cleanup0", "run-1")},
+		&chaosmeshv1alpha1.NetworkChaos{ObjectMeta: meta("network-delay", "cleanup0", "run-2")},
+		&chaosmeshv1alpha1.StressChaos{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "cleanup0"}},
+	).Build()
+
+	// Workflows and chaos created by the controllers carry the label as well
+	workflowName, err := controllers.CreateWorkflow(fakeClient, controllers.NewWorkflowSpec("cleanup0"), "cleanup0")
+	if err != nil {
+		t.Fatalf("CreateWorkflow() error = %v", err)
+	}
+	stuckName, err := controllers.CreatePodChaos(fakeClient, context.Background(), "cleanup0", "cart", chaosmeshv1alpha1.PodFailureAction, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePodChaos() error = %v", err)
+	}
+
+	ctx := context.Background()
+	opts := []CleanupOption{WithCleanupClient(fakeClient), WithCleanupNamespaces("cleanup0")}
+
+	objects, err := ListChaos(ctx, append(opts, WithExperimentID("run-1"))...)
+	if err != nil {
+		t.Fatalf("ListChaos() error = %v", err)
+	}
+	if len(objects) != 2 || objects[0].Kind != "HTTPChaos" || objects[1].Kind != "PodChaos" {
+		t.Fatalf("ListChaos() = %+v, want the HTTPChaos and PodChaos of run-1", objects)
+	}
+
+	if paused, err := PauseChaos(ctx, append(opts, WithExperimentID("run-1"))...); err != nil || len(paused) != 2 {
+		t.Fatalf("PauseChaos() = %d objects, error = %v", len(paused), err)
+	}
+
+	podChaos := &chaosmeshv1alpha1.PodChaos{}
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "cleanup0", Name: "pod-kill"}, podChaos); err != nil {
+		t.Fatal(err)
+	}
+	if !podChaos.IsPaused() {
+		t.Errorf("pod-kill is not paused")
+	}
+
+	if resumed, err := ResumeChaos(ctx, append(opts, WithExperimentID("run-1"))...); err != nil || len(resumed) != 2 {
+		t.Fatalf("ResumeChaos() = %d objects, error = %v", len(resumed), err)
+	}
+
+	managed, err := ListChaos(ctx, append(opts, WithLabelSelector(map[string]string{ManagedByLabelKey: ManagedByLabelValue}))...)
+	if err != nil || len(managed) != 5 {
+		t.Fatalf("ListChaos() of the library's objects = %+v, error = %v, want 5", managed, err)
+	}
+	if paused, err := PauseChaos(ctx, opts...); err != nil || len(paused) != 5 {
+		t.Errorf("PauseChaos() = %d objects, error = %v, want every object but the workflow", len(paused), err)
+	}
+
+	// Chaos Mesh cannot recover the target of the stuck chaos, its finalizer stays
+	stuck := &chaosmeshv1alpha1.PodChaos{}
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "cleanup0", Name: stuckName}, stuck); err != nil {
+		t.Fatal(err)
+	}
+	stuck.Finalizers = []string{"chaos-mesh/records"}
+	if err := fakeClient.Update(ctx, stuck); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := PanicButton(ctx, "cleanup0", WithCleanupClient(fakeClient), WithCleanupTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("PanicButton() error = %v", err)
+	}
+	if len(deleted) != 5 {
+		t.Errorf("PanicButton() deleted %d objects, want 5", len(deleted))
+	}
+	for _, obj := range deleted {
+		if obj.Forced != (obj.Name == stuckName) {
+			t.Errorf("PanicButton() forced = %v for %s %s, want only the stuck chaos forced", obj.Forced, obj.Kind, obj.Name)
+		}
+		if obj.Kind == "Workflow" && obj.Name != workflowName {
+			t.Errorf("PanicButton() deleted workflow %s, want %s", obj.Name, workflowName)
+		}
+	}
+
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "cleanup0", Name: stuckName}, stuck); err != nil {
+		t.Fatal(err)
+	}
+	if stuck.Annotations[cleanFinalizerAnnotationKey] != cleanFinalizerAnnotationForced {
+		t.Errorf("stuck chaos annotations = %v, want the forced cleanup", stuck.Annotations)
+	}
+	stuck.Finalizers = nil
+	if err := fakeClient.Update(ctx, stuck); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, err := ListChaos(ctx, opts...)
+	if err != nil {
+		t.Fatalf("ListChaos() error = %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Name != "manual" {
+		t.Errorf("remaining = %+v, want only the chaos not created by the library", remaining)
+	}
+}
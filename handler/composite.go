@@ -0,0 +1,387 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Child keys of a composite member node
+	compositeKeyInjection = "0"
+	compositeKeyOffset    = "1"
+
+	compositeMaxOffset = 3600
+
+	// CompositeIDLabelKey carries the ID shared by the members of one composite injection
+	CompositeIDLabelKey = "chaos-experiment/composite-id"
+)
+
+// CompositeMember is one fault of a composite injection together with its start offset
+type CompositeMember struct {
+	Injection *InjectionConf
+	// Offset is the delay in seconds after the composite starts before this member is created
+	Offset int
+}
+
+// CompositeInjection describes a compound failure made of several faults in one experiment.
+//
+// Node layout:
+//
+//	root   {children: {"0": member, "1": member, ...}}
+//	member {children: {"0": InjectionConf node, "1": {value: offset seconds}}}
+type CompositeInjection struct {
+	Members []CompositeMember
+}
+
+// StructToCompositeNode builds the action space node of a composite with memberCount faults
+func StructToCompositeNode(namespacePrefix string, memberCount int) (*Node, error) {
+	if memberCount < 1 {
+		return nil, fmt.Errorf("a composite injection needs at least one member, got %d", memberCount)
+	}
+
+	root := &Node{
+		Name:     "CompositeInjection",
+		Range:    []int{0, memberCount - 1},
+		Children: make(map[string]*Node, memberCount),
+	}
+
+	for i := range memberCount {
+		injectionNode, err := StructToNode[InjectionConf](namespacePrefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build node of member %d: %w", i, err)
+		}
+
+		root.Children[strconv.Itoa(i)] = &Node{
+			Name:  "CompositeMember",
+			Range: []int{0, 1},
+			Children: map[string]*Node{
+				compositeKeyInjection: injectionNode,
+				compositeKeyOffset: {
+					Name:        "Offset",
+					Range:       []int{0, compositeMaxOffset},
+					Description: "Start offset in seconds",
+					Value:       ValueNotSet,
+				},
+			},
+		}
+	}
+
+	return root, nil
+}
+
+// NodeToComposite converts a composite node into a CompositeInjection
+func NodeToComposite(n *Node) (*CompositeInjection, error) {
+	if n == nil {
+		return nil, fmt.Errorf("NodeToComposite: input node is nil")
+	}
+
+	if len(n.Children) == 0 {
+		return nil, fmt.Errorf("composite injection must have at least one member")
+	}
+
+	keys := make([]int, 0, len(n.Children))
+	for key := range n.Children {
+		intKey, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("composite injection contains non-integer member key '%s'", key)
+		}
+		keys = append(keys, intKey)
+	}
+	sort.Ints(keys)
+
+	composite := &CompositeInjection{Members: make([]CompositeMember, 0, len(keys))}
+	for _, key := range keys {
+		memberNode := n.Children[strconv.Itoa(key)]
+		if memberNode == nil || memberNode.Children == nil {
+			return nil, fmt.Errorf("member %d has no children", key)
+		}
+
+		injectionNode, exists := memberNode.Children[compositeKeyInjection]
+		if !exists {
+			return nil, fmt.Errorf("member %d is missing its injection at key '%s'", key, compositeKeyInjection)
+		}
+
+		conf, err := NodeToStruct[InjectionConf](injectionNode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert member %d: %w", key, err)
+		}
+
+		offset := 0
+		if offsetNode, exists := memberNode.Children[compositeKeyOffset]; exists && offsetNode.Value != ValueNotSet {
+			offset = offsetNode.Value
+		}
+
+		if offset < 0 || offset > compositeMaxOffset {
+			return nil, fmt.Errorf("member %d: offset %d is out of valid range [0, %d]", key, offset, compositeMaxOffset)
+		}
+
+		composite.Members = append(composite.Members, CompositeMember{
+			Injection: conf,
+			Offset:    offset,
+		})
+	}
+
+	return composite, nil
+}
+
+// Create injects every member into the same target namespace. Members without an offset are created
+// immediately and the others once their offset has elapsed, so Create returns after the last member
+// has been created. The returned names follow the member order.
+//
+// With AutoNamespaceTarget one free target namespace is leased for the whole composite, until the
+// last member's offset and duration plus the cool-down, and every member is created there. The
+// members must then share one namespace prefix.
+//
+// The members share a CompositeIDLabelKey label. When a member fails, the members still waiting for
+// their offset are cancelled and the ones already created are deleted, so a composite is either
+// injected completely or not at all, and a lease taken for it is released. The error joins the
+// failure of every member and of the rollback.
+func (c *CompositeInjection) Create(ctx context.Context, namespaceTargetIndex int, annotations map[string]string, labels map[string]string) ([]string, error) {
+	return c.CreateWithClient(ctx, nil, namespaceTargetIndex, annotations, labels)
+}
+
+// CreateWithClient is Create through the given client, e.g. a client.DryRunClient. A nil client
+// creates every member through the client registered for its namespace.
+func (c *CompositeInjection) CreateWithClient(ctx context.Context, k8sClient cli.Client, namespaceTargetIndex int, annotations map[string]string, labels map[string]string) ([]string, error) {
+	if len(c.Members) == 0 {
+		return nil, fmt.Errorf("composite injection has no members")
+	}
+
+	nsIdxs := make([]int, len(c.Members))
+	var duration time.Duration
+	for i, member := range c.Members {
+		if member.Injection == nil {
+			return nil, fmt.Errorf("member %d has no injection", i)
+		}
+
+		activeField, err := member.Injection.getActiveField()
+		if err != nil {
+			return nil, fmt.Errorf("member %d: %w", i, err)
+		}
+
+		nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+		if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+			return nil, fmt.Errorf("member %d: namespace index %d exceeds available namespaces count %d", i, nsIdx, len(NamespacePrefixs))
+		}
+		nsIdxs[i] = nsIdx
+
+		if end := time.Duration(member.Offset)*time.Second + chaosDuration(activeField); end > duration {
+			duration = end
+		}
+	}
+
+	// one lease covers the whole composite, so every member lands in the same target namespace
+	var lease *Lease
+	var leaseOpts []LeaseOption
+	if namespaceTargetIndex == AutoNamespaceTarget {
+		for i, nsIdx := range nsIdxs {
+			if nsIdx != nsIdxs[0] {
+				return nil, fmt.Errorf("member %d: leasing a target namespace needs every member in namespace prefix %s, got %s", i, NamespacePrefixs[nsIdxs[0]], NamespacePrefixs[nsIdx])
+			}
+		}
+
+		if k8sClient != nil {
+			leaseOpts = append(leaseOpts, WithLeaseClient(k8sClient))
+		}
+
+		var err error
+		if lease, err = AcquireLease(ctx, NamespacePrefixs[nsIdxs[0]], duration, leaseOpts...); err != nil {
+			return nil, err
+		}
+		namespaceTargetIndex = lease.Target
+	}
+
+	namespaces := make([]string, 0, len(c.Members))
+	for _, nsIdx := range nsIdxs {
+		if namespace := GetTargetNamespace(nsIdx, namespaceTargetIndex); !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	compositeID := rand.String(8)
+	memberLabels := make(map[string]string, len(labels)+1)
+	maps.Copy(memberLabels, labels)
+	memberLabels[CompositeIDLabelKey] = compositeID
+
+	create := func(ctx context.Context, injection *InjectionConf) (string, error) {
+		switch {
+		case lease != nil:
+			// the composite holds the lease, so the members are created without checking it
+			memberClient := k8sClient
+			if memberClient == nil {
+				var err error
+				if memberClient, err = injection.client(); err != nil {
+					return "", err
+				}
+			}
+			return injection.createWithClient(ctx, memberClient, lease.Target, annotations, memberLabels)
+		case k8sClient == nil:
+			return injection.Create(ctx, namespaceTargetIndex, annotations, memberLabels)
+		default:
+			return injection.CreateWithClient(ctx, k8sClient, namespaceTargetIndex, annotations, memberLabels)
+		}
+	}
+
+	memberCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	names := make([]string, len(c.Members))
+	errChan := make(chan error, len(c.Members))
+
+	var wg sync.WaitGroup
+	wg.Add(len(c.Members))
+
+	for i, member := range c.Members {
+		go func() {
+			defer wg.Done()
+
+			if member.Offset > 0 {
+				timer := time.NewTimer(time.Duration(member.Offset) * time.Second)
+				defer timer.Stop()
+
+				select {
+				case <-memberCtx.Done():
+					// a sibling failed, its error is the one reported
+					if ctx.Err() != nil {
+						errChan <- fmt.Errorf("member %d was cancelled before its offset: %w", i, ctx.Err())
+					}
+					return
+				case <-timer.C:
+				}
+			}
+
+			name, err := create(memberCtx, member.Injection)
+			if err != nil {
+				// cancelled because a sibling failed, its error is the one reported
+				if errors.Is(err, context.Canceled) && ctx.Err() == nil {
+					return
+				}
+				errChan <- fmt.Errorf("failed to create member %d: %w", i, err)
+				cancel()
+				return
+			}
+
+			names[i] = name
+		}()
+	}
+
+	wg.Wait()
+	close(errChan)
+
+	var errs []error
+	for err := range errChan {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return names, nil
+	}
+
+	opts := []CleanupOption{
+		WithCleanupNamespaces(namespaces...),
+		WithLabelSelector(map[string]string{CompositeIDLabelKey: compositeID}),
+	}
+	if k8sClient != nil {
+		opts = append(opts, WithCleanupClient(k8sClient))
+	}
+	if _, err := DeleteChaos(context.WithoutCancel(ctx), opts...); err != nil {
+		errs = append(errs, fmt.Errorf("failed to roll back the created members of composite %s: %w", compositeID, err))
+		return names, fmt.Errorf("composite injection %s partially created %v: %w", compositeID, names, errors.Join(errs...))
+	}
+	if lease != nil {
+		if err := ReleaseLease(context.WithoutCancel(ctx), lease, append(leaseOpts, WithLeaseCooldown(0))...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return nil, fmt.Errorf("composite injection %s rolled back: %w", compositeID, errors.Join(errs...))
+}
+
+// GetDisplayConfig returns the display config of every member with its offset
+func (c *CompositeInjection) GetDisplayConfig() ([]map[string]any, error) {
+	result := make([]map[string]any, 0, len(c.Members))
+	for i, member := range c.Members {
+		config, err := member.Injection.GetDisplayConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get display config of member %d: %w", i, err)
+		}
+
+		config["offset"] = member.Offset
+		result = append(result, config)
+	}
+
+	return result, nil
+}
+
+// GetGroundtruth returns the union of the groundtruth of every member
+func (c *CompositeInjection) GetGroundtruth() (Groundtruth, error) {
+	gts := make([]Groundtruth, 0, len(c.Members))
+	for i, member := range c.Members {
+		gt, err := member.Injection.GetGroundtruth()
+		if err != nil {
+			return Groundtruth{}, fmt.Errorf("failed to get groundtruth of member %d: %w", i, err)
+		}
+
+		gts = append(gts, gt)
+	}
+
+	return MergeGroundtruth(gts...), nil
+}
+
+// MergeGroundtruth unions several groundtruths, keeping the first occurrence order of every value
+func MergeGroundtruth(gts ...Groundtruth) Groundtruth {
+	merged := Groundtruth{}
+	for _, gt := range gts {
+		merged.Service = appendUnique(merged.Service, gt.Service...)
+		merged.Pod = appendUnique(merged.Pod, gt.Pod...)
+		merged.Container = appendUnique(merged.Container, gt.Container...)
+		merged.Metric = appendUnique(merged.Metric, gt.Metric...)
+		merged.Function = appendUnique(merged.Function, gt.Function...)
+		merged.Span = appendUnique(merged.Span, gt.Span...)
+		merged.RootCause = appendUnique(merged.RootCause, gt.RootCause...)
+		merged.Propagated = mergePropagated(merged.Propagated, gt.Propagated...)
+	}
+
+	// a service that is the root cause of one member is not a propagated one of another
+	propagated := merged.Propagated[:0]
+	for _, service := range merged.Propagated {
+		if !slices.Contains(merged.RootCause, service.Service) {
+			propagated = append(propagated, service)
+		}
+	}
+	if len(propagated) > 0 {
+		merged.Propagated = propagated
+	} else {
+		merged.Propagated = nil
+	}
+
+	return merged
+}
+
+func appendUnique(dst []string, values ...string) []string {
+	for _, value := range values {
+		exists := false
+		for _, existing := range dst {
+			if existing == value {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			dst = append(dst, value)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMergeGroundtruth(t *testing.T) {
+	cpu := Groundtruth{
+		Service:   []string{"ts-order-service"},
+		Pod:       []string{"ts-order-service-0"},
+		Container: []string{"ts-order-service"},
+		Metric:    []string{string(MetricCPU)},
+	}
+	delay := Groundtruth{
+		Service:   []string{"ts-order-service", "ts-travel-service"},
+		Pod:       []string{"ts-order-service-0", "ts-travel-service-0"},
+		Container: []string{"ts-order-service", "ts-travel-service"},
+		Metric:    []string{string(MetricNetworkLatency)},
+		Span:      []string{"ts-order-service", "ts-travel-service"},
+	}
+
+	want := Groundtruth{
+		Service:   []string{"ts-order-service", "ts-travel-service"},
+		Pod:       []string{"ts-order-service-0", "ts-travel-service-0"},
+		Container: []string{"ts-order-service", "ts-travel-service"},
+		Metric:    []string{string(MetricCPU), string(MetricNetworkLatency)},
+		Span:      []string{"ts-order-service", "ts-travel-service"},
+	}
+
+	got := MergeGroundtruth(cpu, delay)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeGroundtruth() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNodeToCompositeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		node *Node
+	}{
+		{name: "nil node", node: nil},
+		{name: "no members", node: &Node{}},
+		{name: "non-integer key", node: &Node{Children: map[string]*Node{"a": {}}}},
+		{name: "missing injection", node: &Node{Children: map[string]*Node{"0": {Children: map[string]*Node{"1": {Value: 0}}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NodeToComposite(tt.node); err == nil {
+				t.Errorf("NodeToComposite() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCompositeCreateWithClient(t *testing.T) {
+	fakeClient := newTestClient(t, testPod("compsys0", "cart"), testPod("compsys0", "frontend"))
+	useTestSystem(t, "compsys", 1, fakeClient)
+	ctx := context.Background()
+
+	member := func(appIdx, offset int) CompositeMember {
+		return CompositeMember{Injection: &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1, AppIdx: appIdx}}, Offset: offset}
+	}
+	podChaos := func() []chaosmeshv1alpha1.PodChaos {
+		list := &chaosmeshv1alpha1.PodChaosList{}
+		if err := fakeClient.List(ctx, list, cli.InNamespace("compsys0")); err != nil {
+			t.Fatal(err)
+		}
+		return list.Items
+	}
+
+	composite := &CompositeInjection{Members: []CompositeMember{member(0, 0), member(1, 0)}}
+	names, err := composite.CreateWithClient(ctx, fakeClient, 0, nil, map[string]string{"run": "composite"})
+	if err != nil {
+		t.Fatalf("CreateWithClient() error = %v", err)
+	}
+
+	created := podChaos()
+	if len(names) != 2 || len(created) != 2 {
+		t.Fatalf("CreateWithClient() = %v, created %d objects, want 2", names, len(created))
+	}
+	compositeID := created[0].Labels[CompositeIDLabelKey]
+	for _, obj := range created {
+		if compositeID == "" || obj.Labels[CompositeIDLabelKey] != compositeID || obj.Labels["run"] != "composite" {
+			t.Errorf("member %s labels = %v, want the caller's labels and one composite ID", obj.Name, obj.Labels)
+		}
+		if err := fakeClient.Delete(ctx, &obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		members []CompositeMember
+		wantErr []string
+	}{
+		{
+			name:    "created members are rolled back",
+			members: []CompositeMember{member(0, 0), member(1, 0), member(9, 0)},
+			wantErr: []string{"rolled back", "member 2"},
+		},
+		{
+			name:    "pending members are cancelled",
+			members: []CompositeMember{member(0, 0), member(1, compositeMaxOffset), member(9, 0)},
+			wantErr: []string{"rolled back", "member 2"},
+		},
+		{
+			name:    "every failure is reported",
+			members: []CompositeMember{member(0, 0), member(8, 0), member(9, 0)},
+			wantErr: []string{"member 1", "member 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := (&CompositeInjection{Members: tt.members}).CreateWithClient(ctx, fakeClient, 0, nil, nil)
+			if err == nil {
+				t.Fatalf("CreateWithClient() = %v, want an error", names)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("CreateWithClient() error = %v, want it to contain %q", err, want)
+				}
+			}
+			if strings.Contains(err.Error(), "cancelled") {
+				t.Errorf("CreateWithClient() error = %v, want only the failed member", err)
+			}
+			if names != nil {
+				t.Errorf("CreateWithClient() = %v after a rollback, want no names", names)
+			}
+			if left := podChaos(); len(left) != 0 {
+				t.Errorf("%d members left after the rollback", len(left))
+			}
+		})
+	}
+}
+
+func TestCompositeCreateWithLease(t *testing.T) {
+	fakeClient := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "complease0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "complease1"}},
+		testPod("complease0", "cart"), testPod("complease0", "frontend"),
+		testPod("complease1", "cart"), testPod("complease1", "frontend"),
+	)
+	useTestSystem(t, "complease", 2, fakeClient)
+	ctx := context.Background()
+
+	members := make([]CompositeMember, 0, 4)
+	for i := 0; i < 4; i++ {
+		members = append(members, CompositeMember{Injection: &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1, AppIdx: i % 2}}})
+	}
+	composite := &CompositeInjection{Members: members}
+
+	// another holder has the first target, so the composite must go to the second one as a whole
+	if _, err := AcquireTargetLease(ctx, "complease", 0, time.Minute, WithLeaseClient(fakeClient), WithLeaseHolder("other")); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := composite.CreateWithClient(ctx, fakeClient, AutoNamespaceTarget, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWithClient() error = %v", err)
+	}
+
+	all, err := ListLeases(ctx, "complease", WithLeaseClient(fakeClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var leases []Lease
+	for _, lease := range all {
+		if lease.Holder != "other" {
+			leases = append(leases, lease)
+		}
+	}
+	if len(leases) != 1 || leases[0].Namespace != "complease1" {
+		t.Fatalf("ListLeases() = %v, want one lease of complease1 for the whole composite", all)
+	}
+
+	list := &chaosmeshv1alpha1.PodChaosList{}
+	if err := fakeClient.List(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != len(members) || len(list.Items) != len(members) {
+		t.Fatalf("CreateWithClient() = %v, created %d objects, want %d", names, len(list.Items), len(members))
+	}
+	for _, obj := range list.Items {
+		if obj.Namespace != leases[0].Namespace {
+			t.Errorf("member %s created in %s, want the leased namespace %s", obj.Name, obj.Namespace, leases[0].Namespace)
+		}
+	}
+	for i, member := range members {
+		if member.Injection.PodFailure.NamespaceTarget != leases[0].Target {
+			t.Errorf("member %d NamespaceTarget = %d, want %d", i, member.Injection.PodFailure.NamespaceTarget, leases[0].Target)
+		}
+	}
+
+	// a failing member releases the lease of the rolled back composite, which had the first target
+	if err := ReleaseLease(ctx, &all[0], WithLeaseClient(fakeClient), WithLeaseCooldown(0)); err != nil {
+		t.Fatal(err)
+	}
+	failing := &CompositeInjection{Members: []CompositeMember{
+		{Injection: &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1, AppIdx: 0}}},
+		{Injection: &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1, AppIdx: 9}}},
+	}}
+	if _, err := failing.CreateWithClient(ctx, fakeClient, AutoNamespaceTarget, nil, nil); err == nil {
+		t.Fatal("CreateWithClient() expected error, got nil")
+	}
+	if after, err := ListLeases(ctx, "complease", WithLeaseClient(fakeClient)); err != nil || len(after) != 1 || after[0].Namespace != "complease1" {
+		t.Errorf("ListLeases() = %v, %v, want only the first composite's lease", after, err)
+	}
+}
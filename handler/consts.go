@@ -19,6 +19,9 @@ const (
 	KeyContainer       = "ContainerIdx"
 	KeyDNSEndpoint     = "DNSEndpointIdx"
 	KeyDatabase        = "DatabaseIdx"
+	KeyVolume          = "VolumeIdx"
+	KeyBlockVolume     = "BlockVolumeIdx"
+	KeyMachine         = "MachineIdx"
 )
 
 const (
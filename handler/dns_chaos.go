@@ -18,6 +18,8 @@ type DNSErrorSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	DNSEndpointIdx  int `range:"0-0" dynamic:"true" description:"DNS Endpoint Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *DNSErrorSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -46,7 +48,7 @@ func (s *DNSErrorSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllDNSEndpoints()
+	endpoints, err := resourcelookup.GetAllDNSEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get DNS endpoints: %w", err)
 	}
@@ -61,7 +63,12 @@ func (s *DNSErrorSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
 	action := chaosmeshv1alpha1.ErrorAction
 
-	return controllers.CreateDnsChaos(cli, ctx, ns, serviceName, action, []string{endpointPair.Domain}, duration, annotations, labels)
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreateDnsChaos(cli, ctx, ns, serviceName, action, []string{endpointPair.Domain}, duration, annotations, labels, selector...)
 }
 
 // DNSRandomSpec defines the DNS random chaos injection parameters
@@ -70,6 +77,8 @@ type DNSRandomSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	DNSEndpointIdx  int `range:"0-0" dynamic:"true" description:"DNS Endpoint Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *DNSRandomSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -98,7 +107,7 @@ func (s *DNSRandomSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllDNSEndpoints()
+	endpoints, err := resourcelookup.GetAllDNSEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get DNS endpoints: %w", err)
 	}
@@ -113,5 +122,10 @@ func (s *DNSRandomSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
 	action := chaosmeshv1alpha1.RandomAction
 
-	return controllers.CreateDnsChaos(cli, ctx, ns, serviceName, action, []string{endpointPair.Domain}, duration, annotations, labels)
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreateDnsChaos(cli, ctx, ns, serviceName, action, []string{endpointPair.Domain}, duration, annotations, labels, selector...)
 }
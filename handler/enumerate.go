@@ -0,0 +1,401 @@
+package handler
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+// CoverageLevel is the granularity of the strata of Enumerator.Stratified
+type CoverageLevel int
+
+const (
+	// CoverServices covers every chaos type × service the chaos type can target
+	CoverServices CoverageLevel = iota
+	// CoverInjectionPoints covers every chaos type × injection point
+	CoverInjectionPoints
+)
+
+// Enumerator iterates over the distinct actions of an InjectionConf action space built by
+// StructToNode, i.e. the cartesian product of the field ranges of every chaos type
+type Enumerator struct {
+	prefix     string
+	chaosTypes []*enumChaosType
+}
+
+type enumChaosType struct {
+	name   string
+	index  int
+	fields []enumField
+	// point is the position of the injection point in fields, -1 without one
+	point  int
+	points []enumPoint
+}
+
+type enumField struct {
+	key      string
+	name     string
+	min, max int
+}
+
+type enumPoint struct {
+	value   int
+	id      string
+	service string
+}
+
+// ChaosTypeCoverage is how much of one chaos type a history of injections covered
+type ChaosTypeCoverage struct {
+	ChaosType         string   `json:"chaos_type"`
+	Injections        int      `json:"injections"`
+	Points            int      `json:"points"`
+	CoveredPoints     int      `json:"covered_points"`
+	UncoveredPoints   []string `json:"uncovered_points,omitempty"`
+	UncoveredServices []string `json:"uncovered_services,omitempty"`
+}
+
+// CoverageReport lists the services and injection points of an action space that a history of
+// injections never targeted. Injection points are given by their stable identifiers.
+type CoverageReport struct {
+	Injections        int                 `json:"injections"`
+	Services          []string            `json:"services"`
+	UncoveredServices []string            `json:"uncovered_services,omitempty"`
+	ChaosTypes        []ChaosTypeCoverage `json:"chaos_types"`
+}
+
+// NewEnumerator prepares the enumeration of the space, resolving every injection point to its
+// stable identifier and service against the current catalogs
+func NewEnumerator(space *Node) (*Enumerator, error) {
+	if space == nil || len(space.Children) == 0 {
+		return nil, fmt.Errorf("empty action space")
+	}
+
+	e := &Enumerator{}
+	for _, key := range sortedChildKeys(space) {
+		idx, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chaos type key '%s' in action space", key)
+		}
+
+		entry, ok := chaosTypeByID(idx)
+		if !ok {
+			return nil, fmt.Errorf("invalid chaos type key '%s' in action space", key)
+		}
+
+		chaosType, err := newEnumChaosType(entry, space.Children[key])
+		if err != nil {
+			return nil, err
+		}
+		e.chaosTypes = append(e.chaosTypes, chaosType)
+	}
+
+	prefix, err := e.namespacePrefix()
+	if err != nil {
+		return nil, err
+	}
+	e.prefix = prefix
+
+	system, err := currentSystem(prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, chaosType := range e.chaosTypes {
+		if err := chaosType.resolvePoints(system); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func newEnumChaosType(entry *chaosTypeEntry, spec *Node) (*enumChaosType, error) {
+	specType := entry.specType
+	pointField, hasPoint := injectionPointField(specType)
+
+	chaosType := &enumChaosType{name: entry.Name, index: int(entry.id), point: -1}
+	for _, key := range sortedChildKeys(spec) {
+		fieldIdx, err := strconv.Atoi(key)
+		if err != nil || fieldIdx < 0 || fieldIdx >= specType.NumField() {
+			return nil, fmt.Errorf("invalid child key '%s' for %s", key, entry.Name)
+		}
+
+		child := spec.Children[key]
+		field := enumField{key: key, name: specType.Field(fieldIdx).Name}
+		switch {
+		case child.Value != ValueNotSet:
+			field.min, field.max = child.Value, child.Value
+		case len(child.Range) == 2 && child.Range[0] <= child.Range[1]:
+			field.min, field.max = child.Range[0], child.Range[1]
+		default:
+			return nil, fmt.Errorf("field %s of %s has no value and an invalid range %v", field.name, entry.Name, child.Range)
+		}
+
+		if hasPoint && field.name == pointField {
+			chaosType.point = len(chaosType.fields)
+		}
+		chaosType.fields = append(chaosType.fields, field)
+	}
+
+	return chaosType, nil
+}
+
+// namespacePrefix returns the system of the space from the preset Namespace values
+func (e *Enumerator) namespacePrefix() (string, error) {
+	prefix := ""
+	for _, chaosType := range e.chaosTypes {
+		for _, field := range chaosType.fields {
+			if field.name != KeyNamespace {
+				continue
+			}
+			if field.min != field.max || field.min < 0 || field.min >= len(NamespacePrefixs) {
+				return "", fmt.Errorf("%s has no single namespace, build the space with StructToNode", chaosType.name)
+			}
+			if prefix != "" && prefix != NamespacePrefixs[field.min] {
+				return "", fmt.Errorf("action space mixes the systems %s and %s", prefix, NamespacePrefixs[field.min])
+			}
+			prefix = NamespacePrefixs[field.min]
+		}
+	}
+
+	if prefix == "" {
+		return "", fmt.Errorf("action space has no %s field", KeyNamespace)
+	}
+	return prefix, nil
+}
+
+func (c *enumChaosType) resolvePoints(system *resourcelookup.SystemSnapshot) error {
+	if c.point < 0 {
+		return nil
+	}
+
+	field := c.fields[c.point]
+	for value := field.min; value <= field.max; value++ {
+		target, err := resolveTarget(system, c.name, field.name, value)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s %d of %s: %w", field.name, value, c.name, err)
+		}
+		id, err := system.IDAt(injectionPointKinds[field.name], value)
+		if err != nil {
+			return err
+		}
+
+		c.points = append(c.points, enumPoint{value: value, id: id, service: target.App})
+	}
+	return nil
+}
+
+func (c *enumChaosType) count() *big.Int {
+	count := big.NewInt(1)
+	for _, field := range c.fields {
+		count.Mul(count, big.NewInt(int64(field.max-field.min+1)))
+	}
+	return count
+}
+
+// action builds the value node of the chaos type with the field values
+func (c *enumChaosType) action(values []int) *Node {
+	spec := &Node{Name: c.name, Children: make(map[string]*Node, len(c.fields))}
+	for i, field := range c.fields {
+		spec.Children[field.key] = &Node{Name: field.name, Value: values[i]}
+	}
+
+	key := strconv.Itoa(c.index)
+	return &Node{Name: "InjectionConf", Value: c.index, Children: map[string]*Node{key: spec}}
+}
+
+// Count returns the number of distinct actions of the space
+func (e *Enumerator) Count() *big.Int {
+	total := new(big.Int)
+	for _, chaosType := range e.chaosTypes {
+		total.Add(total, chaosType.count())
+	}
+	return total
+}
+
+// CountByChaosType returns the number of distinct actions of every chaos type
+func (e *Enumerator) CountByChaosType() map[string]*big.Int {
+	counts := make(map[string]*big.Int, len(e.chaosTypes))
+	for _, chaosType := range e.chaosTypes {
+		counts[chaosType.name] = chaosType.count()
+	}
+	return counts
+}
+
+// Each calls fn with every distinct action of the space, chaos types in field order and the values
+// of the last field changing fastest, until fn returns false
+func (e *Enumerator) Each(fn func(action *Node) bool) {
+	for _, chaosType := range e.chaosTypes {
+		values := make([]int, len(chaosType.fields))
+		for i, field := range chaosType.fields {
+			values[i] = field.min
+		}
+
+		for {
+			if !fn(chaosType.action(values)) {
+				return
+			}
+
+			i := len(values) - 1
+			for ; i >= 0; i-- {
+				if values[i] < chaosType.fields[i].max {
+					values[i]++
+					break
+				}
+				values[i] = chaosType.fields[i].min
+			}
+			if i < 0 {
+				break
+			}
+		}
+	}
+}
+
+// Stratified draws perStratum distinct actions from every stratum of the level, so every chaos type
+// × service, or every chaos type × injection point, is covered. Chaos types without an injection
+// point form one stratum. The other fields are drawn uniformly from the sampler's random source,
+// a stratum with fewer distinct actions than perStratum yields all of them.
+func (e *Enumerator) Stratified(level CoverageLevel, perStratum int, sampler *Sampler) ([]*Node, error) {
+	if perStratum <= 0 {
+		return nil, fmt.Errorf("perStratum must be positive, got %d", perStratum)
+	}
+	if sampler == nil {
+		sampler = NewSampler()
+	}
+
+	sampler.mu.Lock()
+	defer sampler.mu.Unlock()
+
+	var actions []*Node
+	for _, chaosType := range e.chaosTypes {
+		for _, points := range chaosType.strata(level) {
+			seen := make(map[string]bool)
+			for attempts := 0; len(seen) < perStratum && attempts < sampler.conf.Attempts; attempts++ {
+				values := make([]int, len(chaosType.fields))
+				for i, field := range chaosType.fields {
+					values[i] = field.min + sampler.rand.Intn(field.max-field.min+1)
+				}
+				if chaosType.point >= 0 {
+					values[chaosType.point] = points[sampler.rand.Intn(len(points))]
+				}
+
+				action := chaosType.action(values)
+				if id := actionKey(action); !seen[id] {
+					seen[id] = true
+					actions = append(actions, action)
+				}
+			}
+		}
+	}
+
+	return actions, nil
+}
+
+// strata groups the injection point values of the chaos type by the level, in a stable order
+func (c *enumChaosType) strata(level CoverageLevel) [][]int {
+	if c.point < 0 {
+		return [][]int{nil}
+	}
+
+	if level == CoverInjectionPoints {
+		strata := make([][]int, 0, len(c.points))
+		for _, point := range c.points {
+			strata = append(strata, []int{point.value})
+		}
+		return strata
+	}
+
+	byService := make(map[string][]int)
+	for _, point := range c.points {
+		byService[point.service] = append(byService[point.service], point.value)
+	}
+	services := make([]string, 0, len(byService))
+	for service := range byService {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	strata := make([][]int, 0, len(services))
+	for _, service := range services {
+		strata = append(strata, byService[service])
+	}
+	return strata
+}
+
+// Coverage reports which services and injection points of the space the history never targeted.
+// The history is matched by the stable identifiers of its injection points, so injections converted
+// with InjectionConf.ToStable when they ran still count after the catalogs are regenerated. Injections
+// of chaos types outside of the space or of another system are ignored, as are points that are gone.
+func (e *Enumerator) Coverage(history []*StableInjection) (*CoverageReport, error) {
+	covered := make(map[string]map[string]bool, len(e.chaosTypes))
+	injections := make(map[string]int, len(e.chaosTypes))
+	for _, chaosType := range e.chaosTypes {
+		covered[chaosType.name] = make(map[string]bool)
+	}
+
+	report := &CoverageReport{}
+	for _, injection := range history {
+		if injection == nil {
+			return nil, fmt.Errorf("history has a nil injection")
+		}
+
+		name := injection.ChaosType
+		points, ok := covered[name]
+		if !ok || injection.Namespace != e.prefix {
+			continue
+		}
+
+		entry, ok := chaosTypeByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown chaos type: %s", name)
+		}
+
+		report.Injections++
+		injections[name]++
+		if pointField, ok := injectionPointField(entry.specType); ok {
+			points[injection.InjectionPoints[pointField]] = true
+		}
+	}
+
+	var allServices, coveredServices []string
+	for _, chaosType := range e.chaosTypes {
+		coverage := ChaosTypeCoverage{
+			ChaosType:  chaosType.name,
+			Injections: injections[chaosType.name],
+			Points:     len(chaosType.points),
+		}
+
+		serviceCovered := make(map[string]bool)
+		for _, point := range chaosType.points {
+			allServices = appendUnique(allServices, point.service)
+			if covered[chaosType.name][point.id] {
+				coverage.CoveredPoints++
+				serviceCovered[point.service] = true
+				coveredServices = appendUnique(coveredServices, point.service)
+			} else {
+				coverage.UncoveredPoints = append(coverage.UncoveredPoints, point.id)
+			}
+		}
+		for _, point := range chaosType.points {
+			if !serviceCovered[point.service] {
+				coverage.UncoveredServices = appendUnique(coverage.UncoveredServices, point.service)
+			}
+		}
+		sort.Strings(coverage.UncoveredServices)
+
+		report.ChaosTypes = append(report.ChaosTypes, coverage)
+	}
+
+	sort.Strings(allServices)
+	report.Services = allServices
+	for _, service := range allServices {
+		if !slices.Contains(coveredServices, service) {
+			report.UncoveredServices = append(report.UncoveredServices, service)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+func TestEnumerator(t *testing.T) {
+	useTestSystem(t, "enumsys", 1, newTestClient(t, testPod("enumsys0", "cart"), testPod("enumsys0", "frontend")))
+	resourcelookup.RegisterCatalog("enumsys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cart", ServerPort: "7070"},
+			{AppName: "frontend", Method: "GET", Route: "/product", ServerAddress: "product", ServerPort: "3550"},
+		},
+	})
+
+	full, err := StructToNode[InjectionConf]("enumsys")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	confType := reflect.TypeOf(InjectionConf{})
+	space := &Node{Name: full.Name, Children: map[string]*Node{}}
+	for _, name := range []string{"PodKill", "HTTPRequestAbort"} {
+		field, _ := confType.FieldByName(name)
+		spec := full.Children[fmt.Sprint(field.Index[0])]
+		// a preset value keeps its single value, here the share of the pods of the percent modes
+		for _, child := range spec.Children {
+			if child.Name == "ModeValue" {
+				child.Value = 50
+			}
+		}
+		space.Children[fmt.Sprint(field.Index[0])] = spec
+	}
+
+	enumerator, err := NewEnumerator(space)
+	if err != nil {
+		t.Fatalf("NewEnumerator() error = %v", err)
+	}
+
+	// 60 durations × 2 apps × 5 pod modes for PodKill and 60 durations × 2 endpoints × 5 pod modes
+	// for HTTPRequestAbort
+	if got := enumerator.Count().Int64(); got != 1200 {
+		t.Errorf("Count() = %d, want 1200", got)
+	}
+	if got := enumerator.CountByChaosType()["PodKill"].Int64(); got != 600 {
+		t.Errorf("CountByChaosType()[PodKill] = %d, want 600", got)
+	}
+
+	seen := make(map[string]bool)
+	enumerator.Each(func(action *Node) bool {
+		if _, err := NodeToStruct[InjectionConf](action); err != nil {
+			t.Fatalf("Each() yielded invalid action %+v: %v", NodeToMap(action, true), err)
+		}
+		seen[actionKey(action)] = true
+		return true
+	})
+	if len(seen) != 1200 {
+		t.Errorf("Each() yielded %d distinct actions, want 1200", len(seen))
+	}
+
+	stratified, err := enumerator.Stratified(CoverServices, 1, NewSampler(WithSamplerSeed(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	covered := make(map[string]bool)
+	for _, action := range stratified {
+		conf, err := NodeToStruct[InjectionConf](action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		target, err := conf.ResolveTarget(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		covered[target.ChaosType+"/"+target.App] = true
+	}
+	want := map[string]bool{"PodKill/cart": true, "PodKill/frontend": true, "HTTPRequestAbort/frontend": true}
+	if len(stratified) != 3 || !reflect.DeepEqual(covered, want) {
+		t.Errorf("Stratified(CoverServices) covered %v with %d actions, want %v", covered, len(stratified), want)
+	}
+
+	points, err := enumerator.Stratified(CoverInjectionPoints, 2, NewSampler(WithSamplerSeed(1)))
+	if err != nil || len(points) != 8 {
+		t.Errorf("Stratified(CoverInjectionPoints, 2) = %d actions, error = %v, want 8", len(points), err)
+	}
+
+	var history []*StableInjection
+	for _, conf := range []*InjectionConf{
+		{PodKill: &PodKillSpec{Duration: 1, AppIdx: 0}},
+		{PodKill: &PodKillSpec{Duration: 2, AppIdx: 0}},
+		{PodFailure: &PodFailureSpec{Duration: 1, AppIdx: 1}},
+	} {
+		stable, err := conf.ToStable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		history = append(history, stable)
+	}
+	// An injection into an app that has since been removed counts, but covers no point
+	history = append(history, &StableInjection{
+		ChaosType:       "PodKill",
+		Namespace:       "enumsys",
+		InjectionPoints: map[string]string{KeyApp: resourcelookup.AppID("ads")},
+	})
+
+	report, err := enumerator.Coverage(history)
+	if err != nil {
+		t.Fatalf("Coverage() error = %v", err)
+	}
+	if report.Injections != 3 || !reflect.DeepEqual(report.UncoveredServices, []string{"frontend"}) {
+		t.Errorf("Coverage() = %+v, want 3 injections and frontend uncovered", report)
+	}
+	podKill := report.ChaosTypes[0]
+	if report.ChaosTypes[1].ChaosType == "PodKill" {
+		podKill = report.ChaosTypes[1]
+	}
+	if podKill.CoveredPoints != 1 || !reflect.DeepEqual(podKill.UncoveredPoints, []string{resourcelookup.AppID("frontend")}) {
+		t.Errorf("PodKill coverage = %+v", podKill)
+	}
+
+	// The endpoint injected before the catalog was regenerated stays covered at its new index
+	product, err := (&InjectionConf{HTTPRequestAbort: &HTTPRequestAbortSpec{Duration: 1, EndpointIdx: 1}}).ToStable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resourcelookup.RegisterCatalog("enumsys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/ads", ServerAddress: "ads", ServerPort: "9555"},
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cart", ServerPort: "7070"},
+			{AppName: "frontend", Method: "GET", Route: "/product", ServerAddress: "product", ServerPort: "3550"},
+		},
+	})
+	full, err = StructToNode[InjectionConf]("enumsys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, _ := confType.FieldByName("HTTPRequestAbort")
+	key := fmt.Sprint(field.Index[0])
+	enumerator, err = NewEnumerator(&Node{Name: full.Name, Children: map[string]*Node{key: full.Children[key]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err = enumerator.Coverage([]*StableInjection{product})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.ChaosTypes[0]; got.Points != 3 || got.CoveredPoints != 1 || slices.Contains(got.UncoveredPoints, product.InjectionPoints[KeyEndpoint]) {
+		t.Errorf("HTTPRequestAbort coverage after regenerating the catalog = %+v, want %s covered", got, product.InjectionPoints[KeyEndpoint])
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrInvalidAction is returned by Environment.Step for an action outside of the action space
+var ErrInvalidAction = errors.New("invalid action")
+
+// StepResult is the outcome of injecting one action of an Environment
+type StepResult struct {
+	Name        string         `json:"name"`
+	Namespace   string         `json:"namespace"`
+	ChaosType   string         `json:"chaos_type"`
+	Config      map[string]any `json:"config"`
+	Groundtruth Groundtruth    `json:"groundtruth"`
+}
+
+type EnvironmentConf struct {
+	Client         cli.Client
+	ExperimentID   string
+	Annotations    map[string]string
+	SamplerOptions []SamplerOption
+}
+
+type EnvironmentOption func(*EnvironmentConf)
+
+// WithEnvironmentClient injects and cleans up through the client instead of the registered providers
+func WithEnvironmentClient(k8sClient cli.Client) EnvironmentOption {
+	return func(c *EnvironmentConf) {
+		c.Client = k8sClient
+	}
+}
+
+// WithEnvironmentExperimentID labels the injections with the ID, Reset only deletes chaos carrying it
+func WithEnvironmentExperimentID(id string) EnvironmentOption {
+	return func(c *EnvironmentConf) {
+		c.ExperimentID = id
+	}
+}
+
+// WithEnvironmentSeed makes Sample draw the same actions for the same seed
+func WithEnvironmentSeed(seed int64) EnvironmentOption {
+	return WithEnvironmentSampler(WithSamplerSeed(seed))
+}
+
+// WithEnvironmentSampler configures the Sampler behind Sample, e.g. with chaos type weights
+func WithEnvironmentSampler(opts ...SamplerOption) EnvironmentOption {
+	return func(c *EnvironmentConf) {
+		c.SamplerOptions = append(c.SamplerOptions, opts...)
+	}
+}
+
+// WithEnvironmentAnnotations adds the annotations to every injection
+func WithEnvironmentAnnotations(annotations map[string]string) EnvironmentOption {
+	return func(c *EnvironmentConf) {
+		c.Annotations = annotations
+	}
+}
+
+// Environment exposes the InjectionConf action space of one system like a reinforcement learning
+// environment: the action space is the Node tree of StructToNode, Step injects an action and
+// Reset deletes everything the environment injected
+type Environment struct {
+	prefix  string
+	conf    EnvironmentConf
+	sampler *Sampler
+}
+
+// NewEnvironment returns the environment of the system configured under the namespace prefix
+func NewEnvironment(namespacePrefix string, opts ...EnvironmentOption) (*Environment, error) {
+	if !slices.Contains(NamespacePrefixs, namespacePrefix) {
+		return nil, fmt.Errorf("namespace prefix %s is not configured, see InitTargetConfig", namespacePrefix)
+	}
+
+	conf := EnvironmentConf{ExperimentID: namespacePrefix + "-env"}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return &Environment{
+		prefix:  namespacePrefix,
+		conf:    conf,
+		sampler: NewSampler(conf.SamplerOptions...),
+	}, nil
+}
+
+// ActionSpace returns the Node tree of the system with the dynamic ranges resolved against the
+// current catalogs and cluster
+func (e *Environment) ActionSpace() (*Node, error) {
+	return StructToNode[InjectionConf](e.prefix)
+}
+
+// Schema returns the JSON Schema of the actions of the system, see InjectionSchema
+func (e *Environment) Schema() (*Schema, error) {
+	return InjectionSchema(e.prefix)
+}
+
+// Sample returns a random valid action drawn by the environment's Sampler
+func (e *Environment) Sample() (*Node, error) {
+	space, err := e.ActionSpace()
+	if err != nil {
+		return nil, err
+	}
+
+	return e.sampler.Sample(space)
+}
+
+// Step injects the action into the target namespace of the index, AutoNamespaceTarget leases a free
+// one, and returns the created chaos with its groundtruth
+func (e *Environment) Step(ctx context.Context, action *Node, namespaceTargetIndex int) (*StepResult, error) {
+	conf, err := NodeToStruct[InjectionConf](action)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAction, err)
+	}
+
+	entry, err := conf.activeChaosType()
+	if err != nil {
+		return nil, err
+	}
+	activeField, err := conf.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+	if NamespacePrefixs[nsIdx] != e.prefix {
+		return nil, fmt.Errorf("%w: namespace %s is not the environment's system %s", ErrInvalidAction, NamespacePrefixs[nsIdx], e.prefix)
+	}
+
+	config, err := conf.GetDisplayConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{ExperimentIDLabelKey: e.conf.ExperimentID}
+	result := &StepResult{
+		ChaosType: entry.Name,
+		Config:    config,
+	}
+
+	if namespaceTargetIndex == AutoNamespaceTarget {
+		var leaseOpts []LeaseOption
+		if e.conf.Client != nil {
+			leaseOpts = append(leaseOpts, WithLeaseClient(e.conf.Client))
+		}
+
+		name, lease, err := conf.CreateWithLease(ctx, e.conf.Annotations, labels, leaseOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Name, result.Namespace = name, lease.Namespace
+	} else {
+		k8sClient := e.conf.Client
+		if k8sClient == nil {
+			if k8sClient, err = conf.client(); err != nil {
+				return nil, err
+			}
+		}
+
+		name, err := conf.CreateWithClient(ctx, k8sClient, namespaceTargetIndex, e.conf.Annotations, labels)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Name, result.Namespace = name, GetTargetNamespace(nsIdx, namespaceTargetIndex)
+	}
+
+	// the groundtruth is resolved in the namespace the chaos was created in, which the create sets on
+	// the conf. A failed lookup still returns the chaos, Reset removes it with the experiment's others.
+	if result.Groundtruth, err = conf.GetGroundtruth(); err != nil {
+		return result, fmt.Errorf("failed to get the groundtruth of %s/%s: %w", result.Namespace, result.Name, err)
+	}
+
+	return result, nil
+}
+
+// Reset deletes the chaos the environment injected in every target namespace of its system
+func (e *Environment) Reset(ctx context.Context) ([]ChaosObject, error) {
+	namespaces := make([]string, 0, NamespaceTargetMap[e.prefix])
+	for idx := DefaultStartIndex; idx < DefaultStartIndex+NamespaceTargetMap[e.prefix]; idx++ {
+		namespaces = append(namespaces, fmt.Sprintf("%s%d", e.prefix, idx))
+	}
+
+	opts := []CleanupOption{WithCleanupNamespaces(namespaces...), WithExperimentID(e.conf.ExperimentID)}
+	if e.conf.Client != nil {
+		opts = append(opts, WithCleanupClient(e.conf.Client))
+	}
+
+	return DeleteChaos(ctx, opts...)
+}
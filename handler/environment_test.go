@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnvironment(t *testing.T) {
+	// the pods of the two target namespaces have different names, so the groundtruth tells them apart
+	other := testPod("gymsys1", "frontend")
+	other.Name = "frontend-1"
+	fakeClient := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "gymsys0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "gymsys1"}},
+		testPod("gymsys0", "frontend"),
+		other,
+	)
+	useTestSystem(t, "gymsys", 2, fakeClient)
+	resourcelookup.RegisterCatalog("gymsys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cartservice", ServerPort: "7070"},
+		},
+	})
+
+	if _, err := NewEnvironment("othersys"); err == nil {
+		t.Error("NewEnvironment() with an unconfigured prefix succeeded")
+	}
+
+	env, err := NewEnvironment("gymsys", WithEnvironmentClient(fakeClient), WithEnvironmentSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, err := NewEnvironment("gymsys", WithEnvironmentClient(fakeClient), WithEnvironmentSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 5 {
+		action, err := env.Sample()
+		if err != nil {
+			t.Fatalf("Sample() error = %v", err)
+		}
+		if _, err := NodeToStruct[InjectionConf](action); err != nil {
+			t.Errorf("Sample() = %+v is not a valid action: %v", NodeToMap(action, true), err)
+		}
+
+		again, err := replay.Sample()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(NodeToMap(action, true), NodeToMap(again, true)) {
+			t.Errorf("Sample() with the same seed = %v, want %v", NodeToMap(again, true), NodeToMap(action, true))
+		}
+	}
+
+	action, err := InjectionConfToNode(&InjectionConf{PodFailure: &PodFailureSpec{Duration: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	result, err := env.Step(ctx, action, 0)
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if result.Name == "" || result.Namespace != "gymsys0" || result.ChaosType != "PodFailure" {
+		t.Errorf("Step() = %+v", result)
+	}
+	if !reflect.DeepEqual(result.Groundtruth.Service, []string{"frontend"}) || !reflect.DeepEqual(result.Groundtruth.Pod, []string{"frontend-0"}) {
+		t.Errorf("Step() groundtruth = %+v, want the frontend-0 pod of frontend", result.Groundtruth)
+	}
+
+	// the groundtruth follows the target namespace the chaos was created in
+	result, err = env.Step(ctx, action, 1)
+	if err != nil {
+		t.Fatalf("Step() into target 1 error = %v", err)
+	}
+	if result.Namespace != "gymsys1" || !reflect.DeepEqual(result.Groundtruth.Pod, []string{"frontend-1"}) {
+		t.Errorf("Step() into target 1 = %+v, want the frontend-1 pod of gymsys1", result)
+	}
+
+	if _, err := AcquireTargetLease(ctx, "gymsys", 0, time.Minute, WithLeaseClient(fakeClient), WithLeaseHolder("other")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = env.Step(ctx, action, AutoNamespaceTarget)
+	if err != nil {
+		t.Fatalf("Step() with AutoNamespaceTarget error = %v", err)
+	}
+	if result.Namespace != "gymsys1" || !reflect.DeepEqual(result.Groundtruth.Pod, []string{"frontend-1"}) {
+		t.Errorf("Step() with AutoNamespaceTarget = %+v, want the frontend-1 pod of the leased gymsys1", result)
+	}
+
+	deleted, err := env.Reset(ctx)
+	if err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if len(deleted) != 3 {
+		t.Errorf("Reset() deleted %+v, want the 3 injected chaos", deleted)
+	}
+	if objects, err := ListChaos(ctx, WithCleanupClient(fakeClient), WithCleanupNamespaces("gymsys0", "gymsys1")); err != nil || len(objects) != 0 {
+		t.Errorf("ListChaos() after Reset() = %+v, error = %v", objects, err)
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// useTestSystem points the handler at the namespace prefix with the number of targets, served by the
+// client unless it is nil. When the test ends the previous configuration is restored, and the
+// provider, any catalog registered for the prefix and the lookups cached for it are dropped.
+func useTestSystem(t *testing.T, prefix string, targets int, k8sClient cli.Client) {
+	t.Helper()
+
+	prevPrefixs, prevTargets, prevLabelKey := NamespacePrefixs, NamespaceTargetMap, TargetLabelKey
+	t.Cleanup(func() {
+		NamespacePrefixs, NamespaceTargetMap, TargetLabelKey = prevPrefixs, prevTargets, prevLabelKey
+		client.UnregisterProvider(prefix)
+		resourcelookup.UnregisterCatalog(prefix)
+	})
+
+	NamespacePrefixs = []string{prefix}
+	NamespaceTargetMap = map[string]int{prefix: targets}
+	TargetLabelKey = "app"
+
+	resourcelookup.InvalidatePrefix(prefix)
+	if k8sClient != nil {
+		client.RegisterProvider(prefix, client.NewStaticProvider(k8sClient))
+	}
+}
+
+// testScheme returns the scheme of the clients of the client package
+func testScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+
+	scheme, err := client.NewScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+// newTestClient returns a fake client holding the objects
+func newTestClient(t *testing.T, objects ...cli.Object) cli.WithWatch {
+	t.Helper()
+
+	return fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(objects...).Build()
+}
+
+// testPod returns a running pod of the app with a single container named after the app
+func testPod(namespace, app string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: app + "-0", Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: app}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
 )
@@ -26,6 +27,30 @@ type Groundtruth struct {
 	Metric    []string `json:"metric,omitempty"`
 	Function  []string `json:"function,omitempty"`
 	Span      []string `json:"span,omitempty"`
+
+	// RootCause lists the services the fault is injected into and Propagated the callers the fault
+	// is expected to reach, see InjectionConf.GetGroundtruthWithBlastRadius
+	RootCause  []string            `json:"root_cause,omitempty"`
+	Propagated []PropagatedService `json:"propagated,omitempty"`
+}
+
+// WithAffectedPods keeps the pods of the groundtruth that the chaos affected, such as the
+// AffectedPods of an InjectionResult. A partial-pod injection lists every pod its mode picks from
+// until Chaos Mesh reports the picked ones, see PodMode. Without affected pods it is unchanged.
+func (gt Groundtruth) WithAffectedPods(affected []string) Groundtruth {
+	if len(affected) == 0 {
+		return gt
+	}
+
+	pods := make([]string, 0, len(gt.Pod))
+	for _, pod := range gt.Pod {
+		if slices.Contains(affected, pod) {
+			pods = append(pods, pod)
+		}
+	}
+	gt.Pod = pods
+
+	return gt
 }
 
 // GetGroundtruthFromAppIdx returns a Groundtruth object for a given app index
@@ -87,7 +112,7 @@ func GetGroundtruthFromContainerIdx(namespace string, containerIdx int) (Groundt
 
 // GetGroundtruthFromDNSEndpointIdx returns a Groundtruth object for a given DNS endpoint index
 func GetGroundtruthFromDNSEndpointIdx(namespace string, endpointIdx int) (Groundtruth, error) {
-	endpoints, err := resourcelookup.GetAllDNSEndpoints()
+	endpoints, err := resourcelookup.GetAllDNSEndpoints(namespace)
 	if err != nil {
 		return Groundtruth{}, fmt.Errorf("failed to get DNS endpoints: %w", err)
 	}
@@ -120,7 +145,7 @@ func GetGroundtruthFromDNSEndpointIdx(namespace string, endpointIdx int) (Ground
 
 // getHTTPGroundtruth is a helper function that gets groundtruth information for HTTP chaos
 func getHTTPGroundtruth(namespace string, endpointIdx int) (Groundtruth, error) {
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(namespace)
 	if err != nil {
 		return Groundtruth{}, fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -153,7 +178,7 @@ func getHTTPGroundtruth(namespace string, endpointIdx int) (Groundtruth, error)
 
 // GetGroundtruthFromNetworkPairIdx returns a Groundtruth object for a given network pair index
 func GetGroundtruthFromNetworkPairIdx(namespace string, networkPairIdx int) (Groundtruth, error) {
-	networkPairs, err := resourcelookup.GetAllNetworkPairs()
+	networkPairs, err := resourcelookup.GetAllNetworkPairs(namespace)
 	if err != nil {
 		return Groundtruth{}, fmt.Errorf("failed to get network pairs: %w", err)
 	}
@@ -162,11 +187,28 @@ func GetGroundtruthFromNetworkPairIdx(namespace string, networkPairIdx int) (Gro
 		return Groundtruth{}, fmt.Errorf("network pair index out of range: %d (max: %d)", networkPairIdx, len(networkPairs)-1)
 	}
 
-	// Get the source and target services
 	pair := networkPairs[networkPairIdx]
-	sourceService := pair.SourceService
-	targetService := pair.TargetService
+	return getGroundtruthFromServicePair(namespace, pair.SourceService, pair.TargetService)
+}
+
+// getNetworkGroundtruth returns the groundtruth of a network chaos on the pair with the direction
+// code, its services in the source and target order the chaos is created with
+func getNetworkGroundtruth(namespace string, networkPairIdx int, directionCode int) (Groundtruth, error) {
+	pair, err := getNetworkPairByIndex(namespace, networkPairIdx)
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	sourceService, targetService, _, err := networkPairTarget(namespace, pair, directionCode)
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	return getGroundtruthFromServicePair(namespace, sourceService, targetService)
+}
 
+// getGroundtruthFromServicePair returns the groundtruth of a chaos between the source and target services
+func getGroundtruthFromServicePair(namespace string, sourceService, targetService string) (Groundtruth, error) {
 	// Get containers and pods for both services
 	containers, pods, err := resourcelookup.GetContainersAndPodsByServices(namespace, []string{sourceService, targetService})
 	if err != nil {
@@ -186,7 +228,7 @@ func GetGroundtruthFromNetworkPairIdx(namespace string, networkPairIdx int) (Gro
 
 // GetGroundtruthFromMethodIdx returns a Groundtruth object for a given JVM method index
 func GetGroundtruthFromMethodIdx(namespace string, methodIdx int) (Groundtruth, error) {
-	methods, err := resourcelookup.GetAllJVMMethods()
+	methods, err := resourcelookup.GetAllJVMMethods(namespace)
 	if err != nil {
 		return Groundtruth{}, fmt.Errorf("failed to get JVM methods: %w", err)
 	}
@@ -227,7 +269,7 @@ func GetGroundtruthFromMethodIdx(namespace string, methodIdx int) (Groundtruth,
 
 // GetGroundtruthFromDatabaseIdx returns a Groundtruth object for a given database operation index
 func GetGroundtruthFromDatabaseIdx(namespace string, dbOpIdx int) (Groundtruth, error) {
-	dbOps, err := resourcelookup.GetAllDatabaseOperations()
+	dbOps, err := resourcelookup.GetAllDatabaseOperations(namespace)
 	if err != nil {
 		return Groundtruth{}, fmt.Errorf("failed to get database operations: %w", err)
 	}
@@ -278,212 +320,337 @@ func GetGroundtruthFromDatabaseIdx(namespace string, dbOpIdx int) (Groundtruth,
 	return gt, nil
 }
 
+// GetGroundtruthFromVolumeIdx returns a Groundtruth object for a given container volume index
+func GetGroundtruthFromVolumeIdx(namespace string, volumeIdx int) (Groundtruth, error) {
+	volumes, err := resourcelookup.GetAllVolumes(namespace)
+	if err != nil {
+		return Groundtruth{}, fmt.Errorf("failed to get volumes: %w", err)
+	}
+
+	if volumeIdx < 0 || volumeIdx >= len(volumes) {
+		return Groundtruth{}, fmt.Errorf("volume index out of range: %d (max: %d)", volumeIdx, len(volumes)-1)
+	}
+
+	return getGroundtruthFromVolume(namespace, volumes[volumeIdx])
+}
+
+// GetGroundtruthFromBlockVolumeIdx returns a Groundtruth object for a given claim-backed volume index
+func GetGroundtruthFromBlockVolumeIdx(namespace string, volumeIdx int) (Groundtruth, error) {
+	volumes, err := resourcelookup.GetAllBlockVolumes(namespace)
+	if err != nil {
+		return Groundtruth{}, fmt.Errorf("failed to get block volumes: %w", err)
+	}
+
+	if volumeIdx < 0 || volumeIdx >= len(volumes) {
+		return Groundtruth{}, fmt.Errorf("block volume index out of range: %d (max: %d)", volumeIdx, len(volumes)-1)
+	}
+
+	return getGroundtruthFromVolume(namespace, volumes[volumeIdx])
+}
+
+func getGroundtruthFromVolume(namespace string, volume resourcelookup.VolumeInfo) (Groundtruth, error) {
+	// IO chaos is injected into every pod of the app, not only the one the mount was found on
+	pods, err := resourcelookup.GetPodsByService(namespace, volume.AppLabel)
+	if err != nil {
+		return Groundtruth{}, fmt.Errorf("failed to get pods: %w", err)
+	}
+
+	// Create and populate the groundtruth
+	gt := Groundtruth{
+		Service:   []string{volume.AppLabel},
+		Pod:       pods,
+		Container: []string{volume.ContainerName},
+		Metric:    []string{string(MetricDisk)},
+	}
+
+	return gt, nil
+}
+
+// GetGroundtruthFromMachineIdx returns a Groundtruth object for a given physical machine index, the
+// fault lands on the host and not on a pod so only the service is known
+func GetGroundtruthFromMachineIdx(namespace string, machineIdx int) (Groundtruth, error) {
+	machines, err := resourcelookup.GetAllMachineLabels(namespace)
+	if err != nil {
+		return Groundtruth{}, fmt.Errorf("failed to get physical machines: %w", err)
+	}
+
+	if machineIdx < 0 || machineIdx >= len(machines) {
+		return Groundtruth{}, fmt.Errorf("machine index out of range: %d (max: %d)", machineIdx, len(machines)-1)
+	}
+
+	return Groundtruth{Service: []string{machines[machineIdx]}}, nil
+}
+
 func (s *PodFailureSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromAppIdx(namespace, s.AppIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromAppIdx(namespace, s.AppIdx))
 }
 
 func (s *PodKillSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromAppIdx(namespace, s.AppIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromAppIdx(namespace, s.AppIdx))
 }
 
 func (s *ContainerKillSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx))
 }
 
 func (s *MemoryStressChaosSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricMemory))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *CPUStressChaosSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricCPU))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *TimeSkewSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx))
 }
 
 func (s *DNSErrorSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromDNSEndpointIdx(namespace, s.DNSEndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromDNSEndpointIdx(namespace, s.DNSEndpointIdx))
 }
 
 func (s *DNSRandomSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromDNSEndpointIdx(namespace, s.DNSEndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromDNSEndpointIdx(namespace, s.DNSEndpointIdx))
 }
 
 func (s *HTTPRequestAbortSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *HTTPResponseAbortSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *HTTPRequestDelaySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := getHTTPGroundtruth(namespace, s.EndpointIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricHTTPLatency))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *HTTPResponseDelaySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := getHTTPGroundtruth(namespace, s.EndpointIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricHTTPLatency))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *HTTPResponseReplaceBodySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *HTTPResponsePatchBodySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *HTTPRequestReplacePathSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *HTTPRequestReplaceMethodSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *HTTPResponseReplaceCodeSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return getHTTPGroundtruth(namespace, s.EndpointIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getHTTPGroundtruth(namespace, s.EndpointIdx))
 }
 
 func (s *NetworkDelaySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	gt, err := GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	gt, err := getNetworkGroundtruth(namespace, s.NetworkPairIdx, s.Direction)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricNetworkLatency))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *NetworkLossSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx))
 }
 
 func (s *NetworkDuplicateSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx))
 }
 
 func (s *NetworkCorruptSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx))
 }
 
 func (s *NetworkBandwidthSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx))
 }
 
 func (s *NetworkPartitionSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromNetworkPairIdx(namespace, s.NetworkPairIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(getNetworkGroundtruth(namespace, s.NetworkPairIdx, s.Direction))
 }
 
 // JVM chaos GetGroundtruth implementations
 func (s *JVMLatencySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := GetGroundtruthFromMethodIdx(namespace, s.MethodIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricNetworkLatency))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *JVMReturnSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromMethodIdx(namespace, s.MethodIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromMethodIdx(namespace, s.MethodIdx))
 }
 
 func (s *JVMExceptionSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromMethodIdx(namespace, s.MethodIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromMethodIdx(namespace, s.MethodIdx))
 }
 
 func (s *JVMGCSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromAppIdx(namespace, s.AppIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromAppIdx(namespace, s.AppIdx))
 }
 
 func (s *JVMCPUStressSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := GetGroundtruthFromMethodIdx(namespace, s.MethodIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricCPU))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *JVMMemoryStressSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := GetGroundtruthFromMethodIdx(namespace, s.MethodIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricMemory))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *JVMMySQLLatencySpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
 	gt, err := GetGroundtruthFromDatabaseIdx(namespace, s.DatabaseIdx)
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
 	gt.Metric = append(gt.Metric, string(MetricSQLLatency))
-	return gt, nil
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(gt, nil)
 }
 
 func (s *JVMMySQLExceptionSpec) GetGroundtruth() (Groundtruth, error) {
-	namespace := fmt.Sprintf("%s%d", NamespacePrefixs[s.Namespace], DefaultStartIndex)
-	return GetGroundtruthFromDatabaseIdx(namespace, s.DatabaseIdx)
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromDatabaseIdx(namespace, s.DatabaseIdx))
+}
+
+// IO chaos GetGroundtruth implementations
+func (s *IODelaySpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromVolumeIdx(namespace, s.VolumeIdx))
+}
+
+func (s *IOErrorSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromVolumeIdx(namespace, s.VolumeIdx))
+}
+
+func (s *IOMistakeSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromVolumeIdx(namespace, s.VolumeIdx))
+}
+
+func (s *IOAttrOverrideSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromVolumeIdx(namespace, s.VolumeIdx))
+}
+
+func (s *KernelFaultSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromContainerIdx(namespace, s.ContainerIdx))
+}
+
+func (s *BlockDelaySpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	return podSelection{PodMode(s.Mode), s.ModeValue}.groundtruth(GetGroundtruthFromBlockVolumeIdx(namespace, s.BlockVolumeIdx))
+}
+
+func (s *PhysicalMachineCPUStressSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	gt, err := GetGroundtruthFromMachineIdx(namespace, s.MachineIdx)
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	gt.Metric = append(gt.Metric, string(MetricCPU))
+	return gt, nil
+}
+
+func (s *PhysicalMachineMemoryStressSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	gt, err := GetGroundtruthFromMachineIdx(namespace, s.MachineIdx)
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	gt.Metric = append(gt.Metric, string(MetricMemory))
+	return gt, nil
+}
+
+func (s *PhysicalMachineDiskFillSpec) GetGroundtruth() (Groundtruth, error) {
+	namespace := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	gt, err := GetGroundtruthFromMachineIdx(namespace, s.MachineIdx)
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	gt.Metric = append(gt.Metric, string(MetricDisk))
+	return gt, nil
 }
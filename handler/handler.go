@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"sort"
 
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
 	"github.com/LGU-SE-Internal/chaos-experiment/client"
 	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
 	"github.com/LGU-SE-Internal/chaos-experiment/utils"
 	"github.com/k0kubun/pp/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cli "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -28,38 +32,55 @@ var (
 )
 
 func InitTargetConfig(namespaceTargetMap map[string]int, targetLabelKey string) error {
-	NamespaceTargetMap = namespaceTargetMap
-	TargetLabelKey = targetLabelKey
+	return InitTargetConfigWithProvider(client.DefaultProvider(), namespaceTargetMap, targetLabelKey)
+}
 
-	allNamespaces, err := client.ListNamespaces()
-	if err != nil {
-		return err
-	}
+// InitTargetConfigWithProvider is InitTargetConfig against the cluster of the provider. The provider is
+// registered for every namespace prefix that has none yet, so lookups and injections of these systems go
+// to that cluster; prefixes registered earlier keep their provider. The prefixes are added to the ones
+// configured before, a prefix configured again takes the new number of targets. Namespace indices follow
+// the sorted prefixes, so adding a prefix can shift the index of the ones after it. When
+// chaos.DefaultAppSelector names the apps after their workloads, the workloads of the first namespace
+// of every prefix are resolved into it, see client.ResolveWorkloads.
+func InitTargetConfigWithProvider(provider client.Provider, namespaceTargetMap map[string]int, targetLabelKey string) error {
+	var newPrefixs []string
+	for ns, count := range namespaceTargetMap {
+		nsProvider, registered := client.RegisteredProvider(ns)
+		if !registered {
+			nsProvider = provider
+			newPrefixs = append(newPrefixs, ns)
+		}
 
-	allNamespaceMap := make(map[string]struct{}, len(allNamespaces))
-	for _, ns := range allNamespaces {
-		allNamespaceMap[ns] = struct{}{}
-	}
+		k8sClient, err := nsProvider.Client()
+		if err != nil {
+			return err
+		}
+
+		allNamespaces, err := client.ListNamespacesWithClient(context.Background(), k8sClient)
+		if err != nil {
+			return err
+		}
 
-	TargetLabelKey = targetLabelKey
-	namespacePrefixs := make([]string, 0, len(namespaceTargetMap))
-	for ns, count := range namespaceTargetMap {
 		for i := DefaultStartIndex; i < count; i++ {
 			namespace := fmt.Sprintf("%s%d", ns, i)
-			_, exists := allNamespaceMap[namespace]
-			if !exists {
+			if !slices.Contains(allNamespaces, namespace) {
 				return fmt.Errorf("namespace %s does not exist in the cluster", namespace)
 			}
 		}
+	}
 
-		namespacePrefixs = append(namespacePrefixs, ns)
+	for _, ns := range newPrefixs {
+		client.RegisterProvider(ns, provider)
 	}
 
-	sort.Strings(namespacePrefixs)
-	NamespacePrefixs = namespacePrefixs
+	setTargetConfig(namespaceTargetMap, targetLabelKey)
+	if err := resolveWorkloads(namespaceTargetMap); err != nil {
+		return err
+	}
+
+	for ns := range namespaceTargetMap {
+		resourcelookup.InvalidatePrefix(ns)
 
-	resourcelookup.InitCaches()
-	for _, ns := range namespacePrefixs {
 		namespace := fmt.Sprintf("%s%d", ns, DefaultStartIndex)
 		if err := resourcelookup.PreloadCaches(namespace, targetLabelKey); err != nil {
 			return fmt.Errorf("failed to preload caches of namespace: %v", err)
@@ -69,6 +90,75 @@ func InitTargetConfig(namespaceTargetMap map[string]int, targetLabelKey string)
 	return nil
 }
 
+// resolveWorkloads adds the workloads of the first namespace of the prefixes to the workloads of
+// chaos.DefaultAppSelector, if it names the apps after them. A workload of the same name in several
+// systems takes the selector of the last prefix in sorted order.
+func resolveWorkloads(namespaceTargetMap map[string]int) error {
+	if chaos.DefaultAppSelector.Workload == "" {
+		return nil
+	}
+
+	workloads := maps.Clone(chaos.DefaultAppSelector.Workloads)
+	if workloads == nil {
+		workloads = make(map[string]metav1.LabelSelector)
+	}
+	for _, ns := range sortedKeys(namespaceTargetMap) {
+		selector := chaos.DefaultAppSelector
+		namespace := fmt.Sprintf("%s%d", ns, DefaultStartIndex)
+		if err := client.ResolveWorkloads(context.Background(), namespace, &selector); err != nil {
+			return fmt.Errorf("failed to resolve the workloads of namespace %s: %w", namespace, err)
+		}
+		maps.Copy(workloads, selector.Workloads)
+	}
+
+	chaos.DefaultAppSelector.Workloads = workloads
+	return nil
+}
+
+// InitTargetConfigFromSnapshot is InitTargetConfig without a cluster: the lookups of every namespace
+// prefix are served from its system in the snapshot, see TakeCatalogSnapshot. Injections can then be
+// rendered or built, but creating them still needs a provider registered for the prefix.
+func InitTargetConfigFromSnapshot(snapshot *resourcelookup.CatalogSnapshot, namespaceTargetMap map[string]int, targetLabelKey string) error {
+	if snapshot == nil {
+		return fmt.Errorf("catalog snapshot is nil")
+	}
+
+	for ns := range namespaceTargetMap {
+		if snapshot.Systems[ns] == nil {
+			return fmt.Errorf("catalog snapshot has no system for namespace prefix %s", ns)
+		}
+	}
+
+	setTargetConfig(namespaceTargetMap, targetLabelKey)
+	for ns := range namespaceTargetMap {
+		resourcelookup.LoadSystemSnapshot(ns, snapshot.Systems[ns])
+	}
+
+	return nil
+}
+
+// setTargetConfig adds the namespace prefixes to the target systems. The maps are replaced rather than
+// changed in place, so callers holding the previous configuration keep it.
+func setTargetConfig(namespaceTargetMap map[string]int, targetLabelKey string) {
+	targets := maps.Clone(NamespaceTargetMap)
+	if targets == nil {
+		targets = make(map[string]int, len(namespaceTargetMap))
+	}
+	maps.Copy(targets, namespaceTargetMap)
+
+	namespacePrefixs := make([]string, 0, len(targets))
+	for ns := range targets {
+		namespacePrefixs = append(namespacePrefixs, ns)
+	}
+	sort.Strings(namespacePrefixs)
+
+	NamespaceTargetMap = targets
+	NamespacePrefixs = namespacePrefixs
+	TargetLabelKey = targetLabelKey
+	// The generators and the container lookup name the apps after the same label
+	chaos.DefaultAppSelector.LabelKey = targetLabelKey
+}
+
 // GetTargetNamespace generates a namespace name from an index (1-based)
 func GetTargetNamespace(namespaceIndex, targetIndex int) string {
 	prefix := NamespacePrefixs[namespaceIndex]
@@ -83,87 +173,76 @@ func GetTargetNamespace(namespaceIndex, targetIndex int) string {
 	return fmt.Sprintf("%s%d", prefix, targetIndex)
 }
 
+// The values of the built-in chaos types are stored in datasets as action node keys, so they are
+// fixed: never renumber them, a new built-in takes the next free value, is appended to
+// InjectionConf and is added to builtinChaosTypes. Faults of other packages register their own
+// values from FirstCustomChaosType with RegisterChaosType.
 const (
 	// PodChaos
-	PodKill ChaosType = iota
-	PodFailure
-	ContainerKill
+	PodKill       ChaosType = 0
+	PodFailure    ChaosType = 1
+	ContainerKill ChaosType = 2
 
 	// StressChaos
-	MemoryStress
-	CPUStress
+	MemoryStress ChaosType = 3
+	CPUStress    ChaosType = 4
 
 	// HTTPChaos
-	HTTPRequestAbort
-	HTTPResponseAbort
-	HTTPRequestDelay
-	HTTPResponseDelay
-	HTTPResponseReplaceBody
-	HTTPResponsePatchBody
-	HTTPRequestReplacePath
-	HTTPRequestReplaceMethod
-	HTTPResponseReplaceCode
+	HTTPRequestAbort         ChaosType = 5
+	HTTPResponseAbort        ChaosType = 6
+	HTTPRequestDelay         ChaosType = 7
+	HTTPResponseDelay        ChaosType = 8
+	HTTPResponseReplaceBody  ChaosType = 9
+	HTTPResponsePatchBody    ChaosType = 10
+	HTTPRequestReplacePath   ChaosType = 11
+	HTTPRequestReplaceMethod ChaosType = 12
+	HTTPResponseReplaceCode  ChaosType = 13
 
 	// DNSChaos
-	DNSError
-	DNSRandom
+	DNSError  ChaosType = 14
+	DNSRandom ChaosType = 15
 
 	// TimeChaos
-	TimeSkew
+	TimeSkew ChaosType = 16
 
 	// NetworkChaos
-	NetworkDelay
-	NetworkLoss
-	NetworkDuplicate
-	NetworkCorrupt
-	NetworkBandwidth
-	NetworkPartition
+	NetworkDelay     ChaosType = 17
+	NetworkLoss      ChaosType = 18
+	NetworkDuplicate ChaosType = 19
+	NetworkCorrupt   ChaosType = 20
+	NetworkBandwidth ChaosType = 21
+	NetworkPartition ChaosType = 22
 
 	// JVMChaos
-	JVMLatency
-	JVMReturn
-	JVMException
-	JVMGarbageCollector
-	JVMCPUStress
-	JVMMemoryStress
-	JVMMySQLLatency
-	JVMMySQLException
+	JVMLatency          ChaosType = 23
+	JVMReturn           ChaosType = 24
+	JVMException        ChaosType = 25
+	JVMGarbageCollector ChaosType = 26
+	JVMCPUStress        ChaosType = 27
+	JVMMemoryStress     ChaosType = 28
+	JVMMySQLLatency     ChaosType = 29
+	JVMMySQLException   ChaosType = 30
+
+	// IOChaos
+	IODelay        ChaosType = 31
+	IOError        ChaosType = 32
+	IOMistake      ChaosType = 33
+	IOAttrOverride ChaosType = 34
+
+	// KernelChaos
+	KernelFault ChaosType = 35
+
+	// BlockChaos
+	BlockDelay ChaosType = 36
+
+	// PhysicalMachineChaos
+	PhysicalMachineCPUStress    ChaosType = 37
+	PhysicalMachineMemoryStress ChaosType = 38
+	PhysicalMachineDiskFill     ChaosType = 39
 )
 
-// Define ChaosType to name mapping
-var ChaosTypeMap = map[ChaosType]string{
-	PodKill:                  "PodKill",
-	PodFailure:               "PodFailure",
-	ContainerKill:            "ContainerKill",
-	MemoryStress:             "MemoryStress",
-	CPUStress:                "CPUStress",
-	HTTPRequestAbort:         "HTTPRequestAbort",
-	HTTPResponseAbort:        "HTTPResponseAbort",
-	HTTPRequestDelay:         "HTTPRequestDelay",
-	HTTPResponseDelay:        "HTTPResponseDelay",
-	HTTPResponseReplaceBody:  "HTTPResponseReplaceBody",
-	HTTPResponsePatchBody:    "HTTPResponsePatchBody",
-	HTTPRequestReplacePath:   "HTTPRequestReplacePath",
-	HTTPRequestReplaceMethod: "HTTPRequestReplaceMethod",
-	HTTPResponseReplaceCode:  "HTTPResponseReplaceCode",
-	DNSError:                 "DNSError",
-	DNSRandom:                "DNSRandom",
-	TimeSkew:                 "TimeSkew",
-	NetworkDelay:             "NetworkDelay",
-	NetworkLoss:              "NetworkLoss",
-	NetworkDuplicate:         "NetworkDuplicate",
-	NetworkCorrupt:           "NetworkCorrupt",
-	NetworkBandwidth:         "NetworkBandwidth",
-	NetworkPartition:         "NetworkPartition",
-	JVMLatency:               "JVMLatency",
-	JVMReturn:                "JVMReturn",
-	JVMException:             "JVMException",
-	JVMGarbageCollector:      "JVMGarbageCollector",
-	JVMCPUStress:             "JVMCPUStress",
-	JVMMemoryStress:          "JVMMemoryStress",
-	JVMMySQLLatency:          "JVMMySQLLatency",
-	JVMMySQLException:        "JVMMySQLException",
-}
+// ChaosTypeMap maps every registered chaos type to its name, see RegisterChaosType
+var ChaosTypeMap = map[ChaosType]string{}
 
 // GetChaosTypeName 根据 ChaosType 获取名称
 func GetChaosTypeName(c ChaosType) string {
@@ -212,107 +291,116 @@ type GroundtruthProvider interface {
 	GetGroundtruth() (Groundtruth, error)
 }
 
-var SpecMap = map[ChaosType]any{
-
-	CPUStress:                CPUStressChaosSpec{},
-	MemoryStress:             MemoryStressChaosSpec{},
-	HTTPRequestAbort:         HTTPRequestAbortSpec{},
-	HTTPResponseAbort:        HTTPResponseAbortSpec{},
-	HTTPRequestDelay:         HTTPRequestDelaySpec{},
-	HTTPResponseDelay:        HTTPResponseDelaySpec{},
-	HTTPResponseReplaceBody:  HTTPResponseReplaceBodySpec{},
-	HTTPResponsePatchBody:    HTTPResponsePatchBodySpec{},
-	HTTPRequestReplacePath:   HTTPRequestReplacePathSpec{},
-	HTTPRequestReplaceMethod: HTTPRequestReplaceMethodSpec{},
-	HTTPResponseReplaceCode:  HTTPResponseReplaceCodeSpec{},
-	DNSError:                 DNSErrorSpec{},
-	DNSRandom:                DNSRandomSpec{},
-	TimeSkew:                 TimeSkewSpec{},
-	NetworkDelay:             NetworkDelaySpec{},
-	NetworkLoss:              NetworkLossSpec{},
-	NetworkDuplicate:         NetworkDuplicateSpec{},
-	NetworkCorrupt:           NetworkCorruptSpec{},
-	NetworkBandwidth:         NetworkBandwidthSpec{},
-	NetworkPartition:         NetworkPartitionSpec{},
-	JVMLatency:               JVMLatencySpec{},
-	JVMReturn:                JVMReturnSpec{},
-	JVMException:             JVMExceptionSpec{},
-	JVMGarbageCollector:      JVMGCSpec{},
-	JVMCPUStress:             JVMCPUStressSpec{},
-	JVMMemoryStress:          JVMMemoryStressSpec{},
-	JVMMySQLLatency:          JVMMySQLLatencySpec{},
-	JVMMySQLException:        JVMMySQLExceptionSpec{},
-}
+// SpecMap maps every registered chaos type to the zero value of its spec
+var SpecMap = map[ChaosType]any{}
 
-var ChaosHandlers = map[ChaosType]Injection{
-	PodKill:                  &PodKillSpec{},
-	PodFailure:               &PodFailureSpec{},
-	ContainerKill:            &ContainerKillSpec{},
-	MemoryStress:             &MemoryStressChaosSpec{},
-	CPUStress:                &CPUStressChaosSpec{},
-	HTTPRequestAbort:         &HTTPRequestAbortSpec{},
-	HTTPResponseAbort:        &HTTPResponseAbortSpec{},
-	HTTPRequestDelay:         &HTTPRequestDelaySpec{},
-	HTTPResponseDelay:        &HTTPResponseDelaySpec{},
-	HTTPResponseReplaceBody:  &HTTPResponseReplaceBodySpec{},
-	HTTPResponsePatchBody:    &HTTPResponsePatchBodySpec{},
-	HTTPRequestReplacePath:   &HTTPRequestReplacePathSpec{},
-	HTTPRequestReplaceMethod: &HTTPRequestReplaceMethodSpec{},
-	HTTPResponseReplaceCode:  &HTTPResponseReplaceCodeSpec{},
-	DNSError:                 &DNSErrorSpec{},
-	DNSRandom:                &DNSRandomSpec{},
-	TimeSkew:                 &TimeSkewSpec{},
-	NetworkDelay:             &NetworkDelaySpec{},
-	NetworkLoss:              &NetworkLossSpec{},
-	NetworkDuplicate:         &NetworkDuplicateSpec{},
-	NetworkCorrupt:           &NetworkCorruptSpec{},
-	NetworkBandwidth:         &NetworkBandwidthSpec{},
-	NetworkPartition:         &NetworkPartitionSpec{},
-	JVMLatency:               &JVMLatencySpec{},
-	JVMReturn:                &JVMReturnSpec{},
-	JVMException:             &JVMExceptionSpec{},
-	JVMGarbageCollector:      &JVMGCSpec{},
-	JVMCPUStress:             &JVMCPUStressSpec{},
-	JVMMemoryStress:          &JVMMemoryStressSpec{},
-	JVMMySQLLatency:          &JVMMySQLLatencySpec{},
-	JVMMySQLException:        &JVMMySQLExceptionSpec{},
-}
+// ChaosHandlers maps every registered chaos type to a zero spec creating its chaos
+var ChaosHandlers = map[ChaosType]Injection{}
 
 type InjectionConf struct {
-	PodKill                  *PodKillSpec                  `range:"0-2"`
-	PodFailure               *PodFailureSpec               `range:"0-2"`
-	ContainerKill            *ContainerKillSpec            `range:"0-2"`
-	MemoryStress             *MemoryStressChaosSpec        `range:"0-4"`
-	CPUStress                *CPUStressChaosSpec           `range:"0-4"`
-	HTTPRequestAbort         *HTTPRequestAbortSpec         `range:"0-2"`
-	HTTPResponseAbort        *HTTPResponseAbortSpec        `range:"0-2"`
-	HTTPRequestDelay         *HTTPRequestDelaySpec         `range:"0-3"`
-	HTTPResponseDelay        *HTTPResponseDelaySpec        `range:"0-3"`
-	HTTPResponseReplaceBody  *HTTPResponseReplaceBodySpec  `range:"0-3"`
-	HTTPResponsePatchBody    *HTTPResponsePatchBodySpec    `range:"0-2"`
-	HTTPRequestReplacePath   *HTTPRequestReplacePathSpec   `range:"0-2"`
-	HTTPRequestReplaceMethod *HTTPRequestReplaceMethodSpec `range:"0-3"`
-	HTTPResponseReplaceCode  *HTTPResponseReplaceCodeSpec  `range:"0-3"`
-	DNSError                 *DNSErrorSpec                 `range:"0-2"`
-	DNSRandom                *DNSRandomSpec                `range:"0-2"`
-	TimeSkew                 *TimeSkewSpec                 `range:"0-3"`
-	NetworkDelay             *NetworkDelaySpec             `range:"0-6"`
-	NetworkLoss              *NetworkLossSpec              `range:"0-5"`
-	NetworkDuplicate         *NetworkDuplicateSpec         `range:"0-5"`
-	NetworkCorrupt           *NetworkCorruptSpec           `range:"0-5"`
-	NetworkBandwidth         *NetworkBandwidthSpec         `range:"0-6"`
-	NetworkPartition         *NetworkPartitionSpec         `range:"0-3"`
-	JVMLatency               *JVMLatencySpec               `range:"0-3"`
-	JVMReturn                *JVMReturnSpec                `range:"0-4"`
-	JVMException             *JVMExceptionSpec             `range:"0-3"`
-	JVMGarbageCollector      *JVMGCSpec                    `range:"0-2"`
-	JVMCPUStress             *JVMCPUStressSpec             `range:"0-3"`
-	JVMMemoryStress          *JVMMemoryStressSpec          `range:"0-3"`
-	JVMMySQLLatency          *JVMMySQLLatencySpec          `range:"0-3"`
-	JVMMySQLException        *JVMMySQLExceptionSpec        `range:"0-2"`
+	PodKill                     *PodKillSpec                     `range:"0-5"`
+	PodFailure                  *PodFailureSpec                  `range:"0-5"`
+	ContainerKill               *ContainerKillSpec               `range:"0-5"`
+	MemoryStress                *MemoryStressChaosSpec           `range:"0-7"`
+	CPUStress                   *CPUStressChaosSpec              `range:"0-7"`
+	HTTPRequestAbort            *HTTPRequestAbortSpec            `range:"0-5"`
+	HTTPResponseAbort           *HTTPResponseAbortSpec           `range:"0-5"`
+	HTTPRequestDelay            *HTTPRequestDelaySpec            `range:"0-6"`
+	HTTPResponseDelay           *HTTPResponseDelaySpec           `range:"0-6"`
+	HTTPResponseReplaceBody     *HTTPResponseReplaceBodySpec     `range:"0-6"`
+	HTTPResponsePatchBody       *HTTPResponsePatchBodySpec       `range:"0-5"`
+	HTTPRequestReplacePath      *HTTPRequestReplacePathSpec      `range:"0-5"`
+	HTTPRequestReplaceMethod    *HTTPRequestReplaceMethodSpec    `range:"0-6"`
+	HTTPResponseReplaceCode     *HTTPResponseReplaceCodeSpec     `range:"0-6"`
+	DNSError                    *DNSErrorSpec                    `range:"0-5"`
+	DNSRandom                   *DNSRandomSpec                   `range:"0-5"`
+	TimeSkew                    *TimeSkewSpec                    `range:"0-6"`
+	NetworkDelay                *NetworkDelaySpec                `range:"0-9"`
+	NetworkLoss                 *NetworkLossSpec                 `range:"0-8"`
+	NetworkDuplicate            *NetworkDuplicateSpec            `range:"0-8"`
+	NetworkCorrupt              *NetworkCorruptSpec              `range:"0-8"`
+	NetworkBandwidth            *NetworkBandwidthSpec            `range:"0-9"`
+	NetworkPartition            *NetworkPartitionSpec            `range:"0-6"`
+	JVMLatency                  *JVMLatencySpec                  `range:"0-6"`
+	JVMReturn                   *JVMReturnSpec                   `range:"0-7"`
+	JVMException                *JVMExceptionSpec                `range:"0-6"`
+	JVMGarbageCollector         *JVMGCSpec                       `range:"0-5"`
+	JVMCPUStress                *JVMCPUStressSpec                `range:"0-6"`
+	JVMMemoryStress             *JVMMemoryStressSpec             `range:"0-6"`
+	JVMMySQLLatency             *JVMMySQLLatencySpec             `range:"0-6"`
+	JVMMySQLException           *JVMMySQLExceptionSpec           `range:"0-5"`
+	IODelay                     *IODelaySpec                     `range:"0-7"`
+	IOError                     *IOErrorSpec                     `range:"0-7"`
+	IOMistake                   *IOMistakeSpec                   `range:"0-9"`
+	IOAttrOverride              *IOAttrOverrideSpec              `range:"0-7"`
+	KernelFault                 *KernelFaultSpec                 `range:"0-7"`
+	BlockDelay                  *BlockDelaySpec                  `range:"0-8"`
+	PhysicalMachineCPUStress    *PhysicalMachineCPUStressSpec    `range:"0-4"`
+	PhysicalMachineMemoryStress *PhysicalMachineMemoryStressSpec `range:"0-3"`
+	PhysicalMachineDiskFill     *PhysicalMachineDiskFillSpec     `range:"0-3"`
+
+	// custom is the spec of a chaos type registered by another package, see NewInjectionConf
+	custom Injection
 }
 
+// Create creates the injection in the target namespace of the index. With AutoNamespaceTarget a free
+// target namespace is leased for the chaos duration plus DefaultLeaseCooldown, see CreateWithLease.
 func (ic *InjectionConf) Create(ctx context.Context, namespaceTargetIndex int, annotations map[string]string, labels map[string]string) (string, error) {
+	if namespaceTargetIndex == AutoNamespaceTarget {
+		name, _, err := ic.CreateWithLease(ctx, annotations, labels)
+		return name, err
+	}
+
+	k8sClient, err := ic.client()
+	if err != nil {
+		return "", err
+	}
+
+	return ic.CreateWithClient(ctx, k8sClient, namespaceTargetIndex, annotations, labels)
+}
+
+// client returns the client of the cluster the injection's system was registered with
+func (ic *InjectionConf) client() (cli.Client, error) {
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return nil, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+
+	return client.ProviderFor(NamespacePrefixs[nsIdx]).Client()
+}
+
+// CreateWithClient creates the injection through the given client, e.g. a client.DryRunClient. A
+// target namespace leased by another holder is refused with ErrTargetLeased. With AutoNamespaceTarget
+// the target namespace is leased through the client, see CreateWithLease.
+func (ic *InjectionConf) CreateWithClient(ctx context.Context, k8sClient cli.Client, namespaceTargetIndex int, annotations map[string]string, labels map[string]string) (string, error) {
+	if namespaceTargetIndex == AutoNamespaceTarget {
+		name, _, err := ic.CreateWithLease(ctx, annotations, labels, WithLeaseClient(k8sClient))
+		return name, err
+	}
+
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return "", err
+	}
+
+	nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return "", fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+	if err := checkLease(ctx, k8sClient, GetTargetNamespace(nsIdx, namespaceTargetIndex)); err != nil {
+		return "", err
+	}
+
+	return ic.createWithClient(ctx, k8sClient, namespaceTargetIndex, annotations, labels)
+}
+
+// createWithClient creates the injection without looking at the leases, for callers holding one
+func (ic *InjectionConf) createWithClient(ctx context.Context, k8sClient cli.Client, namespaceTargetIndex int, annotations map[string]string, labels map[string]string) (string, error) {
 	activeField, err := ic.getActiveField()
 	if err != nil {
 		return "", err
@@ -322,10 +410,10 @@ func (ic *InjectionConf) Create(ctx context.Context, namespaceTargetIndex int, a
 
 	instance := activeField.Interface().(Injection)
 	name, err := instance.Create(
-		client.NewK8sClient(),
+		k8sClient,
 		WithAnnotations(annotations),
 		WithContext(ctx),
-		WithLabels(labels),
+		WithLabels(withManagedByLabel(labels)),
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to inject chaos for %T: %w", instance, err)
@@ -334,23 +422,63 @@ func (ic *InjectionConf) Create(ctx context.Context, namespaceTargetIndex int, a
 	return name, nil
 }
 
+// Render builds the chaos object of the injection through a client.DryRunClient and returns its
+// manifest, nothing is sent to a cluster. The injection point is still resolved through the lookups
+// of its system, so configure the targets with InitTargetConfigFromSnapshot to render without a cluster.
+func (ic *InjectionConf) Render(ctx context.Context, namespaceTargetIndex int, annotations map[string]string, labels map[string]string, format client.ManifestFormat) ([]byte, error) {
+	dryRun, err := client.NewDryRunClient(client.WithFormat(format))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := ic.CreateWithClient(ctx, dryRun, namespaceTargetIndex, annotations, labels); err != nil {
+		return nil, err
+	}
+
+	return dryRun.Render()
+}
+
+// BuildObject builds the chaos object of the injection without creating it, e.g. to embed it in a
+// workflow with controllers.WorkflowBuilder.Chaos. Its lookups are those of Render.
+func (ic *InjectionConf) BuildObject(ctx context.Context, namespaceTargetIndex int, annotations map[string]string, labels map[string]string) (cli.Object, error) {
+	dryRun, err := client.NewDryRunClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := ic.CreateWithClient(ctx, dryRun, namespaceTargetIndex, annotations, labels); err != nil {
+		return nil, err
+	}
+
+	objects := dryRun.Objects()
+	if len(objects) != 1 {
+		return nil, fmt.Errorf("injection built %d objects, want 1", len(objects))
+	}
+
+	return objects[0], nil
+}
+
+// getActiveField returns the pointer to the spec of the injection
 func (ic *InjectionConf) getActiveField() (reflect.Value, error) {
-	val := reflect.ValueOf(ic).Elem()
-
-	var activeField reflect.Value
-	for i := range val.NumField() {
-		field := val.Field(i)
-		if !field.IsNil() {
-			activeField = field
-			break
-		}
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return reflect.Value{}, err
 	}
 
-	if !activeField.IsValid() {
-		return reflect.Value{}, fmt.Errorf("failed to get the non-empty injection")
+	if !entry.builtin {
+		return reflect.ValueOf(ic.custom), nil
+	}
+	return reflect.ValueOf(ic).Elem().FieldByName(entry.Name), nil
+}
+
+// getActiveFieldIndex returns the chaos type of the injection, i.e. its key in the action space
+func (ic *InjectionConf) getActiveFieldIndex() (int, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return 0, err
 	}
 
-	return activeField, nil
+	return int(entry.id), nil
 }
 
 func (ic *InjectionConf) getActiveInjection() (Injection, error) {
@@ -363,11 +491,20 @@ func (ic *InjectionConf) getActiveInjection() (Injection, error) {
 }
 
 func (ic *InjectionConf) GetDisplayConfig() (map[string]any, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return nil, err
+	}
+
 	instance, err := ic.getActiveInjection()
 	if err != nil {
 		return nil, err
 	}
 
+	if entry.DisplayConfig != nil {
+		return entry.DisplayConfig(instance)
+	}
+
 	instanceValue := reflect.ValueOf(instance).Elem()
 	instanceType := instanceValue.Type()
 
@@ -411,21 +548,21 @@ func (ic *InjectionConf) GetDisplayConfig() (map[string]any, error) {
 
 				value = map[string]any{"app_name": labels[index]}
 			case KeyMethod:
-				methods, err := resourcelookup.GetAllJVMMethods()
+				methods, err := resourcelookup.GetAllJVMMethods(prefix)
 				if err != nil {
 					return nil, err
 				}
 
 				value = methods[index]
 			case KeyEndpoint:
-				endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+				endpoints, err := resourcelookup.GetAllHTTPEndpoints(prefix)
 				if err != nil {
 					return nil, err
 				}
 
 				value = endpoints[index]
 			case KeyNetworkPair:
-				networkpairs, err := resourcelookup.GetAllNetworkPairs()
+				networkpairs, err := resourcelookup.GetAllNetworkPairs(prefix)
 				if err != nil {
 					return nil, err
 				}
@@ -440,19 +577,43 @@ func (ic *InjectionConf) GetDisplayConfig() (map[string]any, error) {
 
 				value = containers[index]
 			case KeyDNSEndpoint:
-				endpoints, err := resourcelookup.GetAllDNSEndpoints()
+				endpoints, err := resourcelookup.GetAllDNSEndpoints(prefix)
 				if err != nil {
 					return nil, err
 				}
 
 				value = endpoints[index]
 			case KeyDatabase:
-				operations, err := resourcelookup.GetAllDatabaseOperations()
+				operations, err := resourcelookup.GetAllDatabaseOperations(prefix)
 				if err != nil {
 					return nil, err
 				}
 
 				value = operations[index]
+			case KeyVolume:
+				namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+				volumes, err := resourcelookup.GetAllVolumes(namespace)
+				if err != nil {
+					return nil, err
+				}
+
+				value = volumes[index]
+			case KeyBlockVolume:
+				namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+				volumes, err := resourcelookup.GetAllBlockVolumes(namespace)
+				if err != nil {
+					return nil, err
+				}
+
+				value = volumes[index]
+			case KeyMachine:
+				namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+				machines, err := resourcelookup.GetAllMachineLabels(namespace)
+				if err != nil {
+					return nil, err
+				}
+
+				value = map[string]any{"app_name": machines[index]}
 			}
 
 			jsonData, err := json.Marshal(value)
@@ -478,7 +639,7 @@ func (ic *InjectionConf) GetDisplayConfig() (map[string]any, error) {
 				result[key] = value
 
 				if key == "direction" {
-					result[key] = directionMap[int(value)]
+					result[key] = directionName(int(value))
 				}
 			}
 		}
@@ -487,18 +648,42 @@ func (ic *InjectionConf) GetDisplayConfig() (map[string]any, error) {
 	return result, nil
 }
 
+// GetGroundtruth returns the groundtruth of the injection with the callers up to DefaultBlastRadiusHops
 func (ic *InjectionConf) GetGroundtruth() (Groundtruth, error) {
+	return ic.GetGroundtruthWithBlastRadius(DefaultBlastRadiusHops)
+}
+
+// GetGroundtruthWithBlastRadius returns the groundtruth of the injection with the callers of its
+// services up to the number of hops as Propagated, 0 only labels the root causes
+func (ic *InjectionConf) GetGroundtruthWithBlastRadius(hops int) (Groundtruth, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
 	instance, err := ic.getActiveInjection()
 	if err != nil {
 		return Groundtruth{}, err
 	}
 
-	// Check if the injection supports GetGroundtruth
-	if provider, ok := instance.(GroundtruthProvider); ok {
-		return provider.GetGroundtruth()
+	gt, err := entry.groundtruth(instance)
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return Groundtruth{}, err
+	}
+
+	nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return Groundtruth{}, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
 	}
 
-	return Groundtruth{}, fmt.Errorf("injection does not support groundtruth calculation")
+	// the callers are looked up in the same target namespace as the base groundtruth
+	namespace := GetTargetNamespace(nsIdx, int(activeField.Elem().FieldByName(KeyNamespaceTarget).Int()))
+	return gt.withBlastRadius(namespace, hops), nil
 }
 
 func getIntValue(field reflect.Value) (int64, error) {
@@ -3,14 +3,25 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
 	"github.com/k0kubun/pp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // 测试获取配置
@@ -181,3 +192,158 @@ func genValue(m map[string]any) (map[string]any, error) {
 	m["value"] = rand.Intn(rangeI[1]-rangeI[0]+1) + rangeI[0]
 	return m, nil
 }
+
+// failingProvider stands in for a cluster that cannot be reached
+type failingProvider struct{}
+
+func (failingProvider) Client() (cli.Client, error) {
+	return nil, errors.New("no cluster")
+}
+
+func TestRenderWithoutCluster(t *testing.T) {
+	useTestSystem(t, "rendersys", 2, nil)
+	prevDefault := client.DefaultProvider()
+	client.SetDefaultProvider(failingProvider{})
+	t.Cleanup(func() { client.SetDefaultProvider(prevDefault) })
+
+	snapshot := &resourcelookup.CatalogSnapshot{Systems: map[string]*resourcelookup.SystemSnapshot{
+		"rendersys": {
+			AppLabels:  []string{"cart", "frontend"},
+			Methods:    []resourcelookup.AppMethodPair{{AppName: "cart", ClassName: "cart.CartService", MethodName: "add"}},
+			Containers: []resourcelookup.ContainerInfo{{PodName: "cart-0", AppLabel: "cart", ContainerName: "cart"}},
+		},
+	}}
+	if err := InitTargetConfigFromSnapshot(snapshot, map[string]int{"rendersys": 2}, "app"); err != nil {
+		t.Fatalf("InitTargetConfigFromSnapshot() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		conf *InjectionConf
+		want []string
+	}{
+		{
+			name: "pod",
+			conf: &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1, AppIdx: 1}},
+			want: []string{"kind: PodChaos", "namespace: rendersys1", "app: frontend"},
+		},
+		{
+			name: "container",
+			conf: &InjectionConf{ContainerKill: &ContainerKillSpec{Duration: 1, ContainerIdx: 0}},
+			want: []string{"kind: PodChaos", "- cart"},
+		},
+		{
+			name: "jvm",
+			conf: &InjectionConf{JVMLatency: &JVMLatencySpec{Duration: 1, MethodIdx: 0, LatencyDuration: 100}},
+			want: []string{"kind: JVMChaos", "class: cart.CartService", "method: add"},
+		},
+		{
+			name: "partial pod",
+			conf: &InjectionConf{PodKill: &PodKillSpec{Duration: 1, AppIdx: 0, Mode: int(FixedPercentPods), ModeValue: 50}},
+			want: []string{"kind: PodChaos", "mode: fixed-percent", `value: "50"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := tt.conf.Render(context.Background(), 1, nil, nil, client.FormatYAML)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(manifest), want) {
+					t.Errorf("manifest misses %q:\n%s", want, manifest)
+				}
+			}
+		})
+	}
+
+	// the pods of the groundtruth come from the snapshot as well
+	groundtruth, err := (&ContainerKillSpec{Duration: 1, ContainerIdx: 0, NamespaceTarget: 1}).GetGroundtruth()
+	if err != nil || !reflect.DeepEqual(groundtruth.Pod, []string{"cart-0"}) {
+		t.Errorf("GetGroundtruth() without a cluster = %+v, error = %v, want the pods of the snapshot", groundtruth, err)
+	}
+
+	if err := InitTargetConfigFromSnapshot(snapshot, map[string]int{"othersys": 1}, "app"); err == nil {
+		t.Error("InitTargetConfigFromSnapshot() accepted a prefix missing from the snapshot")
+	}
+}
+
+func TestInitTargetConfigWithProvider(t *testing.T) {
+	useTestSystem(t, "asys", 1, nil)
+	for _, prefix := range []string{"bsys", "csys"} {
+		t.Cleanup(func() {
+			client.UnregisterProvider(prefix)
+			resourcelookup.UnregisterCatalog(prefix)
+		})
+	}
+
+	system := func(namespaces ...string) client.Provider {
+		var objects []cli.Object
+		for _, namespace := range namespaces {
+			objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, testPod(namespace, "cart"))
+		}
+		return client.NewStaticProvider(newTestClient(t, objects...))
+	}
+	first := system("asys0", "csys0", "csys1")
+	second := system("bsys0")
+
+	// bsys lives in another cluster and keeps its provider
+	client.RegisterProvider("bsys", second)
+	if err := InitTargetConfigWithProvider(first, map[string]int{"asys": 1, "bsys": 1}, "app"); err != nil {
+		t.Fatalf("InitTargetConfigWithProvider() error = %v", err)
+	}
+	if client.ProviderFor("asys0") != first || client.ProviderFor("bsys0") != second {
+		t.Errorf("InitTargetConfigWithProvider() replaced the provider registered for bsys")
+	}
+
+	if err := InitTargetConfigWithProvider(first, map[string]int{"csys": 2}, "app"); err != nil {
+		t.Fatalf("InitTargetConfigWithProvider() error = %v", err)
+	}
+	if want := []string{"asys", "bsys", "csys"}; !reflect.DeepEqual(NamespacePrefixs, want) {
+		t.Errorf("NamespacePrefixs = %v, want %v", NamespacePrefixs, want)
+	}
+	if want := map[string]int{"asys": 1, "bsys": 1, "csys": 2}; !reflect.DeepEqual(NamespaceTargetMap, want) {
+		t.Errorf("NamespaceTargetMap = %v, want %v", NamespaceTargetMap, want)
+	}
+
+	if err := InitTargetConfigWithProvider(first, map[string]int{"dsys": 1}, "app"); err == nil {
+		t.Error("InitTargetConfigWithProvider() accepted a prefix without namespaces")
+	}
+	if _, registered := client.RegisteredProvider("dsys"); registered || slices.Contains(NamespacePrefixs, "dsys") {
+		t.Error("InitTargetConfigWithProvider() kept a prefix that failed validation")
+	}
+}
+
+func TestInitTargetConfigResolvesWorkloads(t *testing.T) {
+	useTestSystem(t, "wlsys", 1, nil)
+	t.Cleanup(func() { client.UnregisterProvider("wlsys") })
+
+	prevSelector := chaos.DefaultAppSelector
+	t.Cleanup(func() { chaos.DefaultAppSelector = prevSelector })
+	chaos.DefaultAppSelector = chaos.AppSelector{Workload: chaos.Deployment}
+
+	controller := true
+	pod := testPod("wlsys0", "order")
+	pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = "5d4f8"
+	pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "order-v2-5d4f8", Controller: &controller}}
+	selector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "order"}}
+	provider := client.NewStaticProvider(newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "wlsys0"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "order-v2", Namespace: "wlsys0"}, Spec: appsv1.DeploymentSpec{Selector: &selector}},
+		pod,
+	))
+
+	if err := InitTargetConfigWithProvider(provider, map[string]int{"wlsys": 1}, "app"); err != nil {
+		t.Fatalf("InitTargetConfigWithProvider() error = %v", err)
+	}
+	if got := chaos.DefaultAppSelector.Workloads["order-v2"]; !reflect.DeepEqual(got, selector) {
+		t.Errorf("workload order-v2 = %+v, want %+v", got, selector)
+	}
+
+	apps, err := resourcelookup.GetAllAppLabels("wlsys0", TargetLabelKey)
+	if err != nil || !reflect.DeepEqual(apps, []string{"order-v2"}) {
+		t.Errorf("GetAllAppLabels() = %v, %v, want the workload order-v2", apps, err)
+	}
+}
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	corev1 "k8s.io/api/core/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultHealthInterval = 10 * time.Second
+	DefaultRestartWindow  = 2 * time.Minute
+	// DefaultRecoveryTimeout is how long ConfirmRecovery waits for steady state without WithHealthTimeout,
+	// a namespace is rarely healthy again at the very moment its chaos recovers
+	DefaultRecoveryTimeout = 5 * time.Minute
+)
+
+// ErrUnhealthy is returned when the target namespace is not in steady state
+var ErrUnhealthy = errors.New("namespace is not in steady state")
+
+// HealthReport is the state of the pods of the apps of chaos.DefaultAppSelector and the chaos of a
+// target namespace
+type HealthReport struct {
+	Namespace      string        `json:"namespace"`
+	Healthy        bool          `json:"healthy"`
+	UnreadyPods    []string      `json:"unready_pods,omitempty"`
+	RestartingPods []string      `json:"restarting_pods,omitempty"`
+	ActiveChaos    []string      `json:"active_chaos,omitempty"`
+	CheckedAt      time.Time     `json:"checked_at"`
+	Waited         time.Duration `json:"waited"`
+}
+
+func (r *HealthReport) reason() string {
+	var reasons []string
+	if len(r.UnreadyPods) > 0 {
+		reasons = append(reasons, fmt.Sprintf("unready pods %v", r.UnreadyPods))
+	}
+	if len(r.RestartingPods) > 0 {
+		reasons = append(reasons, fmt.Sprintf("restarting pods %v", r.RestartingPods))
+	}
+	if len(r.ActiveChaos) > 0 {
+		reasons = append(reasons, fmt.Sprintf("active chaos %v", r.ActiveChaos))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// RecoveryReport is how the target namespace came back after an injection recovered
+type RecoveryReport struct {
+	Injection *InjectionResult `json:"injection"`
+	Health    *HealthReport    `json:"health"`
+	// TimeToSteadyState is the time from the chaos recovery until the namespace was healthy again
+	TimeToSteadyState time.Duration `json:"time_to_steady_state"`
+}
+
+type HealthConf struct {
+	Client        cli.Client
+	Timeout       time.Duration
+	Interval      time.Duration
+	RestartWindow time.Duration
+}
+
+type HealthOption func(*HealthConf)
+
+// WithHealthClient checks through the client instead of the registered providers
+func WithHealthClient(k8sClient cli.Client) HealthOption {
+	return func(c *HealthConf) {
+		c.Client = k8sClient
+	}
+}
+
+// WithHealthTimeout waits up to the timeout for the namespace to become healthy, without it the
+// namespace is checked once
+func WithHealthTimeout(timeout time.Duration) HealthOption {
+	return func(c *HealthConf) {
+		c.Timeout = timeout
+	}
+}
+
+// WithHealthInterval sets how often the namespace is checked while waiting
+func WithHealthInterval(interval time.Duration) HealthOption {
+	return func(c *HealthConf) {
+		c.Interval = interval
+	}
+}
+
+// WithRestartWindow sets how long ago the last container restart must be for a pod to count as stable
+func WithRestartWindow(window time.Duration) HealthOption {
+	return func(c *HealthConf) {
+		c.RestartWindow = window
+	}
+}
+
+func newHealthConf(opts []HealthOption) HealthConf {
+	conf := HealthConf{Interval: DefaultHealthInterval, RestartWindow: DefaultRestartWindow}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return conf
+}
+
+// CheckHealth checks once that every pod of an app of chaos.DefaultAppSelector in the namespace is
+// Ready, no container restarted within the restart window and no chaos object is active
+func CheckHealth(ctx context.Context, namespace string, opts ...HealthOption) (*HealthReport, error) {
+	conf := newHealthConf(opts)
+
+	k8sClient, err := healthClient(conf, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkHealth(ctx, k8sClient, namespace, conf, nil)
+}
+
+// WaitForHealthy checks the namespace until it is healthy or the timeout of WithHealthTimeout passed.
+// Restart counts must also be unchanged between two checks. An unhealthy namespace returns the last
+// report with an error wrapping ErrUnhealthy.
+func WaitForHealthy(ctx context.Context, namespace string, opts ...HealthOption) (*HealthReport, error) {
+	conf := newHealthConf(opts)
+
+	k8sClient, err := healthClient(conf, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	deadline := start.Add(conf.Timeout)
+	restarts := make(map[string]int32)
+
+	for {
+		report, err := checkHealth(ctx, k8sClient, namespace, conf, restarts)
+		if err != nil {
+			return nil, err
+		}
+		report.Waited = time.Since(start)
+
+		if report.Healthy {
+			return report, nil
+		}
+		if !time.Now().Add(conf.Interval).Before(deadline) {
+			return report, fmt.Errorf("%w: %s: %s", ErrUnhealthy, namespace, report.reason())
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, fmt.Errorf("stopped waiting for namespace %s: %w", namespace, ctx.Err())
+		case <-time.After(conf.Interval):
+		}
+	}
+}
+
+// checkHealth takes one report. Pods whose restart count differs from the one in restarts are
+// restarting, restarts is updated with the current counts.
+func checkHealth(ctx context.Context, k8sClient cli.Client, namespace string, conf HealthConf, restarts map[string]int32) (*HealthReport, error) {
+	report := &HealthReport{Namespace: namespace, CheckedAt: time.Now()}
+
+	var pods corev1.PodList
+	if err := k8sClient.List(ctx, &pods, cli.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	for _, pod := range pods.Items {
+		// only the pods the chaos can select gate it
+		if pod.Status.Phase == corev1.PodSucceeded || chaos.DefaultAppSelector.AppOf(&pod) == "" {
+			continue
+		}
+
+		if !podReady(&pod) {
+			report.UnreadyPods = append(report.UnreadyPods, pod.Name)
+		}
+
+		var count int32
+		restarting := false
+		for _, status := range pod.Status.ContainerStatuses {
+			count += status.RestartCount
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && report.CheckedAt.Sub(terminated.FinishedAt.Time) < conf.RestartWindow {
+				restarting = true
+			}
+		}
+		if restarts != nil {
+			if previous, ok := restarts[pod.Name]; ok && previous != count {
+				restarting = true
+			}
+			restarts[pod.Name] = count
+		}
+		if restarting {
+			report.RestartingPods = append(report.RestartingPods, pod.Name)
+		}
+	}
+
+	objects, err := listChaosInNamespace(ctx, k8sClient, namespace, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		if obj.Active {
+			report.ActiveChaos = append(report.ActiveChaos, fmt.Sprintf("%s/%s", obj.Kind, obj.Name))
+		}
+	}
+
+	sort.Strings(report.UnreadyPods)
+	sort.Strings(report.RestartingPods)
+	report.Healthy = len(report.UnreadyPods) == 0 && len(report.RestartingPods) == 0 && len(report.ActiveChaos) == 0
+
+	return report, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func healthClient(conf HealthConf, namespace string) (cli.Client, error) {
+	if conf.Client != nil {
+		return conf.Client, nil
+	}
+	return client.ProviderFor(namespace).Client()
+}
+
+// CreateWithHealthGate waits for the target namespace to be in steady state and refuses to create
+// the injection when it does not get there within the timeout. With AutoNamespaceTarget a free target
+// namespace is leased first, see CreateWithLease, and its health is waited for instead; the lease is
+// released when the injection is refused.
+func (ic *InjectionConf) CreateWithHealthGate(ctx context.Context, namespaceTargetIndex int, annotations map[string]string, labels map[string]string, opts ...HealthOption) (string, *HealthReport, error) {
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return "", nil, err
+	}
+
+	nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return "", nil, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+
+	conf := newHealthConf(opts)
+	k8sClient := conf.Client
+	if k8sClient == nil {
+		if k8sClient, err = ic.client(); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if namespaceTargetIndex != AutoNamespaceTarget {
+		namespace := GetTargetNamespace(nsIdx, namespaceTargetIndex)
+		report, err := WaitForHealthy(ctx, namespace, append(opts, WithHealthClient(k8sClient))...)
+		if err != nil {
+			return "", report, fmt.Errorf("refusing to inject %s: %w", namespace, err)
+		}
+
+		name, err := ic.CreateWithClient(ctx, k8sClient, namespaceTargetIndex, annotations, labels)
+		return name, report, err
+	}
+
+	leaseOpts := []LeaseOption{WithLeaseClient(k8sClient)}
+	lease, err := AcquireLease(ctx, NamespacePrefixs[nsIdx], chaosDuration(activeField), leaseOpts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	report, err := WaitForHealthy(ctx, lease.Namespace, append(opts, WithHealthClient(k8sClient))...)
+	if err != nil {
+		err = fmt.Errorf("refusing to inject %s: %w", lease.Namespace, err)
+	} else {
+		var name string
+		if name, err = ic.createWithClient(ctx, k8sClient, lease.Target, annotations, labels); err == nil {
+			return name, report, nil
+		}
+	}
+
+	if releaseErr := ReleaseLease(context.WithoutCancel(ctx), lease, append(leaseOpts, WithLeaseCooldown(0))...); releaseErr != nil {
+		err = errors.Join(err, releaseErr)
+	}
+	return "", report, err
+}
+
+// ConfirmRecovery waits for the chaos to recover and then for the namespace to return to steady
+// state, reporting how long that took after the recovery. Unlike WaitForHealthy it waits up to
+// DefaultRecoveryTimeout when no WithHealthTimeout is given.
+func ConfirmRecovery(ctx context.Context, namespace, name string, opts ...HealthOption) (*RecoveryReport, error) {
+	conf := newHealthConf(opts)
+	if conf.Timeout <= 0 {
+		opts = append(opts, WithHealthTimeout(DefaultRecoveryTimeout))
+	}
+
+	k8sClient, err := healthClient(conf, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	injection, err := WaitForInjection(ctx, k8sClient, namespace, name, WithWatchInterval(conf.Interval))
+	if err != nil {
+		return &RecoveryReport{Injection: injection}, err
+	}
+
+	health, err := WaitForHealthy(ctx, namespace, append(opts, WithHealthClient(k8sClient))...)
+	report := &RecoveryReport{Injection: injection, Health: health}
+	if err != nil {
+		return report, err
+	}
+
+	if !injection.RecoverTime.IsZero() {
+		report.TimeToSteadyState = max(health.CheckedAt.Sub(injection.RecoverTime), 0)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHealthGate(t *testing.T) {
+	pod := func(app string, ready bool, lastRestart time.Time) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+
+		containerStatus := corev1.ContainerStatus{Name: app}
+		if !lastRestart.IsZero() {
+			containerStatus.RestartCount = 1
+			containerStatus.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(lastRestart)}
+		}
+
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: app + "-0", Namespace: "healthsys0", Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: app}}},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+				ContainerStatuses: []corev1.ContainerStatus{containerStatus},
+			},
+		}
+	}
+	recovered := chaosmeshv1alpha1.ChaosStatus{
+		Conditions: []chaosmeshv1alpha1.ChaosCondition{{Type: chaosmeshv1alpha1.ConditionAllRecovered, Status: corev1.ConditionTrue}},
+	}
+
+	cartservice := pod("cartservice", false, time.Time{})
+	checkout := pod("checkout", true, time.Now())
+	previous := &chaosmeshv1alpha1.PodChaos{ObjectMeta: metav1.ObjectMeta{Name: "previous", Namespace: "healthsys0"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+		pod("frontend", true, time.Now().Add(-time.Hour)),
+		cartservice,
+		checkout,
+		previous,
+		&chaosmeshv1alpha1.PodChaos{ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "healthsys0"}, Status: chaosmeshv1alpha1.PodChaosStatus{ChaosStatus: recovered}},
+	).WithStatusSubresource(&corev1.Pod{}, &chaosmeshv1alpha1.PodChaos{}).Build()
+	useTestSystem(t, "healthsys", 1, fakeClient)
+
+	ctx := context.Background()
+	ic := &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1}}
+
+	_, report, err := ic.CreateWithHealthGate(ctx, 0, nil, nil)
+	if !errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("CreateWithHealthGate() error = %v, want ErrUnhealthy", err)
+	}
+	want := &HealthReport{
+		UnreadyPods:    []string{"cartservice-0"},
+		RestartingPods: []string{"checkout-0"},
+		ActiveChaos:    []string{"PodChaos/previous"},
+	}
+	if !reflect.DeepEqual(report.UnreadyPods, want.UnreadyPods) || !reflect.DeepEqual(report.RestartingPods, want.RestartingPods) || !reflect.DeepEqual(report.ActiveChaos, want.ActiveChaos) {
+		t.Errorf("report = %+v, want %+v", report, want)
+	}
+
+	cartservice.Status.Conditions[0].Status = corev1.ConditionTrue
+	checkout.Status.ContainerStatuses[0].LastTerminationState.Terminated.FinishedAt = metav1.NewTime(time.Now().Add(-time.Hour))
+	previous.Status.ChaosStatus = recovered
+	for _, pod := range []*corev1.Pod{cartservice, checkout} {
+		if err := fakeClient.Status().Update(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fakeClient.Status().Update(ctx, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	name, report, err := ic.CreateWithHealthGate(ctx, 0, nil, nil)
+	if err != nil || !report.Healthy {
+		t.Fatalf("CreateWithHealthGate() on a healthy namespace = %+v, error = %v", report, err)
+	}
+
+	// the new chaos is active until Chaos Mesh reports it recovered
+	if report, err := CheckHealth(ctx, "healthsys0"); err != nil || report.Healthy {
+		t.Errorf("CheckHealth() with active chaos = %+v, error = %v", report, err)
+	}
+
+	podChaos := &chaosmeshv1alpha1.PodChaos{}
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "healthsys0", Name: name}, podChaos); err != nil {
+		t.Fatal(err)
+	}
+	applied, recoveredAt := metav1.NewTime(time.Now().Add(-2*time.Minute)), metav1.NewTime(time.Now().Add(-time.Minute))
+	podChaos.Status.ChaosStatus = recovered
+	podChaos.Status.Experiment.Records = []*chaosmeshv1alpha1.Record{{
+		Id: "healthsys0/frontend-0",
+		Events: []chaosmeshv1alpha1.RecordEvent{
+			{Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Apply, Timestamp: &applied},
+			{Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Recover, Timestamp: &recoveredAt},
+		},
+	}}
+	if err := fakeClient.Status().Update(ctx, podChaos); err != nil {
+		t.Fatal(err)
+	}
+
+	// the namespace settles a moment after the recovery, ConfirmRecovery waits for it by default
+	cartservice.Status.Conditions[0].Status = corev1.ConditionFalse
+	if err := fakeClient.Status().Update(ctx, cartservice); err != nil {
+		t.Fatal(err)
+	}
+	settled := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cartservice.Status.Conditions[0].Status = corev1.ConditionTrue
+		settled <- fakeClient.Status().Update(ctx, cartservice)
+	}()
+
+	recovery, err := ConfirmRecovery(ctx, "healthsys0", name, WithHealthInterval(10*time.Millisecond))
+	if err := <-settled; err != nil {
+		t.Fatal(err)
+	}
+	if err != nil {
+		t.Fatalf("ConfirmRecovery() error = %v", err)
+	}
+	if !recovery.Health.Healthy || recovery.TimeToSteadyState < time.Minute {
+		t.Errorf("ConfirmRecovery() = %+v, want a healthy namespace a minute after the recovery", recovery)
+	}
+}
+
+func TestHealthGateLease(t *testing.T) {
+	pod := func(namespace string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend-0", Namespace: namespace, Labels: map[string]string{"app": "frontend"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "frontend"}}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "healthlease0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "healthlease1"}},
+		pod("healthlease0", false),
+		pod("healthlease1", true),
+	).Build()
+	useTestSystem(t, "healthlease", 2, fakeClient)
+
+	ctx := context.Background()
+	ic := &InjectionConf{PodFailure: &PodFailureSpec{Duration: 1}}
+
+	// the leased namespace is the one checked, and its lease is released when it is unhealthy
+	_, report, err := ic.CreateWithHealthGate(ctx, AutoNamespaceTarget, nil, nil)
+	if !errors.Is(err, ErrUnhealthy) || report.Namespace != "healthlease0" {
+		t.Fatalf("CreateWithHealthGate() = %+v, error = %v, want ErrUnhealthy for healthlease0", report, err)
+	}
+	if leases, err := ListLeases(ctx, "healthlease", WithLeaseClient(fakeClient)); err != nil || len(leases) != 0 {
+		t.Errorf("ListLeases() = %v, %v, want the refused lease released", leases, err)
+	}
+
+	if _, err := AcquireTargetLease(ctx, "healthlease", 0, time.Minute, WithLeaseClient(fakeClient), WithLeaseHolder("other")); err != nil {
+		t.Fatal(err)
+	}
+	name, report, err := ic.CreateWithHealthGate(ctx, AutoNamespaceTarget, nil, nil)
+	if err != nil || report.Namespace != "healthlease1" {
+		t.Fatalf("CreateWithHealthGate() = %+v, error = %v, want an injection into healthlease1", report, err)
+	}
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "healthlease1", Name: name}, &chaosmeshv1alpha1.PodChaos{}); err != nil {
+		t.Errorf("chaos %s not created in the leased namespace: %v", name, err)
+	}
+	if leases, err := ListLeases(ctx, "healthlease", WithLeaseClient(fakeClient)); err != nil || len(leases) != 2 {
+		t.Errorf("ListLeases() = %v, %v, want a lease on both target namespaces", leases, err)
+	}
+}
+
+func TestCheckHealthAppSelector(t *testing.T) {
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "healthselector0", Labels: labels},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(
+		pod("frontend-0", map[string]string{"app.kubernetes.io/name": "frontend", "release": "stable"}),
+		pod("frontend-canary-0", map[string]string{"app.kubernetes.io/name": "frontend", "release": "canary"}),
+		pod("cart-0", map[string]string{"app": "cart", "release": "stable"}),
+	).Build()
+	useTestSystem(t, "healthselector", 1, fakeClient)
+
+	prevSelector := chaos.DefaultAppSelector
+	t.Cleanup(func() { chaos.DefaultAppSelector = prevSelector })
+	chaos.DefaultAppSelector = chaos.AppSelector{LabelKey: "app.kubernetes.io/name", Labels: map[string]string{"release": "stable"}}
+
+	// only the pods the chaos can select are checked
+	report, err := CheckHealth(context.Background(), "healthselector0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"frontend-0"}; !reflect.DeepEqual(report.UnreadyPods, want) {
+		t.Errorf("UnreadyPods = %v, want %v", report.UnreadyPods, want)
+	}
+}
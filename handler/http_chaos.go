@@ -19,6 +19,8 @@ type HTTPRequestAbortSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	EndpointIdx     int `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPRequestAbortSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -47,7 +49,7 @@ func (s *HTTPRequestAbortSpec) Create(cli cli.Client, opts ...Option) (string, e
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -79,6 +81,12 @@ func (s *HTTPRequestAbortSpec) Create(cli cli.Client, opts ...Option) (string, e
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "request-abort", duration, annotations, labels, optss...)
 }
 
@@ -88,6 +96,8 @@ type HTTPResponseAbortSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	EndpointIdx     int `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPResponseAbortSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -116,7 +126,7 @@ func (s *HTTPResponseAbortSpec) Create(cli cli.Client, opts ...Option) (string,
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -148,6 +158,12 @@ func (s *HTTPResponseAbortSpec) Create(cli cli.Client, opts ...Option) (string,
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "response-abort", duration, annotations, labels, optss...)
 }
 
@@ -158,6 +174,8 @@ type HTTPRequestDelaySpec struct {
 	EndpointIdx     int `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	DelayDuration   int `range:"10-5000" description:"Delay in milliseconds"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPRequestDelaySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -186,7 +204,7 @@ func (s *HTTPRequestDelaySpec) Create(cli cli.Client, opts ...Option) (string, e
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -218,6 +236,12 @@ func (s *HTTPRequestDelaySpec) Create(cli cli.Client, opts ...Option) (string, e
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "request-delay", duration, annotations, labels, optss...)
 }
 
@@ -228,6 +252,8 @@ type HTTPResponseDelaySpec struct {
 	EndpointIdx     int `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	DelayDuration   int `range:"10-5000" description:"Delay in milliseconds"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPResponseDelaySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -256,7 +282,7 @@ func (s *HTTPResponseDelaySpec) Create(cli cli.Client, opts ...Option) (string,
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -288,6 +314,12 @@ func (s *HTTPResponseDelaySpec) Create(cli cli.Client, opts ...Option) (string,
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "response-delay", duration, annotations, labels, optss...)
 }
 
@@ -306,6 +338,8 @@ type HTTPResponseReplaceBodySpec struct {
 	EndpointIdx     int             `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	BodyType        ReplaceBodyType `range:"0-1" description:"Body Type (0=Empty, 1=Random)"`
 	NamespaceTarget int             `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int             `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int             `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPResponseReplaceBodySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -334,7 +368,7 @@ func (s *HTTPResponseReplaceBodySpec) Create(cli cli.Client, opts ...Option) (st
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -371,6 +405,12 @@ func (s *HTTPResponseReplaceBodySpec) Create(cli cli.Client, opts ...Option) (st
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "response-replace-body", duration, annotations, labels, optss...)
 }
 
@@ -380,6 +420,8 @@ type HTTPResponsePatchBodySpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	EndpointIdx     int `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPResponsePatchBodySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -408,7 +450,7 @@ func (s *HTTPResponsePatchBodySpec) Create(cli cli.Client, opts ...Option) (stri
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -439,6 +481,12 @@ func (s *HTTPResponsePatchBodySpec) Create(cli cli.Client, opts ...Option) (stri
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "response-patch-body", duration, annotations, labels, optss...)
 }
 
@@ -448,6 +496,8 @@ type HTTPRequestReplacePathSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	EndpointIdx     int `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPRequestReplacePathSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -476,7 +526,7 @@ func (s *HTTPRequestReplacePathSpec) Create(cli cli.Client, opts ...Option) (str
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -508,6 +558,12 @@ func (s *HTTPRequestReplacePathSpec) Create(cli cli.Client, opts ...Option) (str
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "request-replace-path", duration, annotations, labels, optss...)
 }
 
@@ -518,6 +574,8 @@ type HTTPRequestReplaceMethodSpec struct {
 	EndpointIdx     int        `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	ReplaceMethod   HTTPMethod `range:"0-6" description:"HTTP Method to replace with"`
 	NamespaceTarget int        `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int        `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int        `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPRequestReplaceMethodSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -546,7 +604,7 @@ func (s *HTTPRequestReplaceMethodSpec) Create(cli cli.Client, opts ...Option) (s
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -578,6 +636,12 @@ func (s *HTTPRequestReplaceMethodSpec) Create(cli cli.Client, opts ...Option) (s
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "request-replace-method", duration, annotations, labels, optss...)
 }
 
@@ -588,6 +652,8 @@ type HTTPResponseReplaceCodeSpec struct {
 	EndpointIdx     int            `range:"0-0" dynamic:"true" description:"Flattened HTTP Endpoint Index"`
 	StatusCode      HTTPStatusCode `range:"0-9" description:"HTTP Status Code to replace with"`
 	NamespaceTarget int            `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int            `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int            `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *HTTPResponseReplaceCodeSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -616,7 +682,7 @@ func (s *HTTPResponseReplaceCodeSpec) Create(cli cli.Client, opts ...Option) (st
 		ns = conf.Namespace
 	}
 
-	endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+	endpoints, err := resourcelookup.GetAllHTTPEndpoints(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get HTTP endpoints: %w", err)
 	}
@@ -648,5 +714,11 @@ func (s *HTTPResponseReplaceCodeSpec) Create(cli cli.Client, opts ...Option) (st
 	// Add common HTTP options (port, path and method)
 	optss = AddCommonHTTPOptions(endpoint, optss)
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(serviceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithHTTPPodSelector(selector...))
+
 	return controllers.CreateHTTPChaos(cli, ctx, ns, serviceName, "response-replace-code", duration, annotations, labels, optss...)
 }
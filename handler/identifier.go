@@ -0,0 +1,396 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+// Map injection point fields to the catalog they index into
+var injectionPointKinds = map[string]resourcelookup.PointKind{
+	KeyApp:         resourcelookup.PointApp,
+	KeyMethod:      resourcelookup.PointMethod,
+	KeyEndpoint:    resourcelookup.PointEndpoint,
+	KeyNetworkPair: resourcelookup.PointNetworkPair,
+	KeyContainer:   resourcelookup.PointContainer,
+	KeyDNSEndpoint: resourcelookup.PointDNSEndpoint,
+	KeyDatabase:    resourcelookup.PointDatabase,
+	KeyVolume:      resourcelookup.PointVolume,
+	KeyBlockVolume: resourcelookup.PointBlockVolume,
+	KeyMachine:     resourcelookup.PointMachine,
+}
+
+// StableInjection is the index-free form of an InjectionConf: the namespace is stored by prefix
+// and every injection point field by the content-addressed identifier of its point, so it keeps
+// pointing at the same target after the generated catalogs are regenerated
+type StableInjection struct {
+	ChaosType string `json:"chaos_type"`
+	Namespace string `json:"namespace"`
+	// InjectionPoints maps the injection point fields of the spec, e.g. MethodIdx, to their identifiers
+	InjectionPoints map[string]string `json:"injection_points,omitempty"`
+	Params          map[string]int    `json:"params,omitempty"`
+}
+
+// systemResolver returns the catalog of the system behind a namespace prefix. Only the injection
+// point lists of the kinds are needed, no kinds means all of them.
+type systemResolver func(prefix string, kinds ...resourcelookup.PointKind) (*resourcelookup.SystemSnapshot, error)
+
+// currentSystem resolves against the live lookups, looking up only the kinds asked for so that
+// kinds served by the catalogs resolve without a cluster
+func currentSystem(prefix string, kinds ...resourcelookup.PointKind) (*resourcelookup.SystemSnapshot, error) {
+	if len(kinds) == 0 {
+		kinds = resourcelookup.PointKinds
+	}
+
+	namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+	return resourcelookup.TakePointSnapshot(namespace, TargetLabelKey, kinds...)
+}
+
+// snapshotSystem resolves against a saved snapshot
+func snapshotSystem(snapshot *resourcelookup.CatalogSnapshot) systemResolver {
+	return func(prefix string, _ ...resourcelookup.PointKind) (*resourcelookup.SystemSnapshot, error) {
+		if snapshot == nil {
+			return nil, fmt.Errorf("catalog snapshot is nil")
+		}
+
+		system, ok := snapshot.Systems[prefix]
+		if !ok {
+			return nil, fmt.Errorf("catalog snapshot has no system for namespace prefix %s", prefix)
+		}
+
+		return system, nil
+	}
+}
+
+// TakeCatalogSnapshot snapshots the injection points of every configured namespace prefix
+func TakeCatalogSnapshot() (*resourcelookup.CatalogSnapshot, error) {
+	snapshot := &resourcelookup.CatalogSnapshot{
+		CreatedAt: time.Now(),
+		Systems:   make(map[string]*resourcelookup.SystemSnapshot, len(NamespacePrefixs)),
+	}
+
+	for _, prefix := range NamespacePrefixs {
+		system, err := currentSystem(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot namespace prefix %s: %w", prefix, err)
+		}
+
+		snapshot.Systems[prefix] = system
+	}
+
+	return snapshot, nil
+}
+
+// ToStable converts the injection into its index-free form using the current catalogs
+func (ic *InjectionConf) ToStable() (*StableInjection, error) {
+	return ic.toStable(currentSystem)
+}
+
+func (ic *InjectionConf) toStable(resolve systemResolver) (*StableInjection, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return nil, err
+	}
+
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	specVal := activeField.Elem()
+	specType := specVal.Type()
+
+	result := &StableInjection{
+		ChaosType:       entry.Name,
+		InjectionPoints: make(map[string]string),
+		Params:          make(map[string]int),
+	}
+
+	namespaceField := specVal.FieldByName(KeyNamespace)
+	if !namespaceField.IsValid() {
+		return nil, fmt.Errorf("%s has no %s field", result.ChaosType, KeyNamespace)
+	}
+
+	nsIdx := int(namespaceField.Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return nil, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+	result.Namespace = NamespacePrefixs[nsIdx]
+
+	for i := range specType.NumField() {
+		field := specType.Field(i)
+		if field.Name == KeyNamespace || field.Name == KeyNamespaceTarget {
+			continue
+		}
+
+		value, err := getIntValue(specVal.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		if value == 0 && field.Tag.Get("optional") == "true" {
+			continue
+		}
+
+		kind, isPoint := injectionPointKinds[field.Name]
+		if !isPoint {
+			result.Params[field.Name] = int(value)
+			continue
+		}
+
+		system, err := resolve(result.Namespace, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := system.IDAt(kind, int(value))
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		result.InjectionPoints[field.Name] = id
+	}
+
+	return result, nil
+}
+
+// StableToConf resolves an index-free injection against the current catalogs
+func StableToConf(s *StableInjection) (*InjectionConf, error) {
+	return stableToConf(s, currentSystem)
+}
+
+func stableToConf(s *StableInjection, resolve systemResolver) (*InjectionConf, error) {
+	if s == nil {
+		return nil, fmt.Errorf("stable injection is nil")
+	}
+
+	entry, ok := chaosTypeByName(s.ChaosType)
+	if !ok {
+		return nil, fmt.Errorf("unknown chaos type: %s", s.ChaosType)
+	}
+
+	conf := &InjectionConf{}
+	spec := entry.newSpec()
+	conf.setSpec(entry, spec)
+	specVal := spec.Elem()
+	specType := specVal.Type()
+
+	nsIdx := -1
+	for idx, prefix := range NamespacePrefixs {
+		if prefix == s.Namespace {
+			nsIdx = idx
+			break
+		}
+	}
+
+	if nsIdx < 0 {
+		return nil, fmt.Errorf("namespace prefix %s is not configured (available: %v)", s.Namespace, NamespacePrefixs)
+	}
+
+	for i := range specType.NumField() {
+		field := specType.Field(i)
+
+		var value int
+		switch field.Name {
+		case KeyNamespace:
+			value = nsIdx
+		case KeyNamespaceTarget:
+			continue
+		default:
+			kind, isPoint := injectionPointKinds[field.Name]
+			if !isPoint {
+				param, ok := s.Params[field.Name]
+				if !ok {
+					if field.Tag.Get("optional") == "true" {
+						continue
+					}
+
+					return nil, fmt.Errorf("%s: missing required param '%s'", s.ChaosType, field.Name)
+				}
+
+				value = param
+				break
+			}
+
+			id, ok := s.InjectionPoints[field.Name]
+			if !ok {
+				return nil, fmt.Errorf("%s: missing injection point '%s'", s.ChaosType, field.Name)
+			}
+
+			system, err := resolve(s.Namespace, kind)
+			if err != nil {
+				return nil, err
+			}
+
+			idx, err := system.IndexOf(kind, id)
+			if err != nil {
+				return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+			}
+			value = idx
+		}
+
+		if err := setValue(specVal.Field(i), value); err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+	}
+
+	return conf, nil
+}
+
+// InjectionConfToNode converts an injection into the value node NodeToStruct accepts
+func InjectionConfToNode(ic *InjectionConf) (*Node, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return nil, err
+	}
+
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	specVal := activeField.Elem()
+	specType := specVal.Type()
+	fieldIdx := int(entry.id)
+
+	specNode := &Node{
+		Name:     entry.Name,
+		Children: make(map[string]*Node, specType.NumField()),
+	}
+
+	for i := range specType.NumField() {
+		field := specType.Field(i)
+		if field.Name == KeyNamespaceTarget {
+			continue
+		}
+
+		value, err := getIntValue(specVal.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		if value == 0 && field.Tag.Get("optional") == "true" {
+			continue
+		}
+
+		specNode.Children[strconv.Itoa(i)] = &Node{
+			Name:  field.Name,
+			Value: int(value),
+		}
+	}
+
+	return &Node{
+		Name:     reflect.TypeOf(InjectionConf{}).Name(),
+		Value:    fieldIdx,
+		Children: map[string]*Node{strconv.Itoa(fieldIdx): specNode},
+	}, nil
+}
+
+// NodeToStable converts an InjectionConf node into its index-free form
+func NodeToStable(n *Node) (*StableInjection, error) {
+	conf, err := NodeToStruct[InjectionConf](n)
+	if err != nil {
+		return nil, err
+	}
+
+	return conf.ToStable()
+}
+
+// StableToNode resolves an index-free injection into an InjectionConf node
+func StableToNode(s *StableInjection) (*Node, error) {
+	conf, err := StableToConf(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return InjectionConfToNode(conf)
+}
+
+// MigrateInjectionConf re-resolves an index-based injection created against the snapshot
+// so that it points at the same target in the current catalogs
+func MigrateInjectionConf(ic *InjectionConf, snapshot *resourcelookup.CatalogSnapshot) (*InjectionConf, error) {
+	return migrateInjectionConf(ic, snapshotSystem(snapshot), currentSystem)
+}
+
+func migrateInjectionConf(ic *InjectionConf, from, to systemResolver) (*InjectionConf, error) {
+	stable, err := ic.toStable(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve injection against the snapshot: %w", err)
+	}
+
+	migrated, err := stableToConf(stable, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve injection against the current catalog: %w", err)
+	}
+
+	// Keep the target namespace the caller chose, it is not part of the catalog
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	migratedField, err := migrated.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	target := activeField.Elem().FieldByName(KeyNamespaceTarget)
+	if target.IsValid() {
+		if err := setIntValue(migratedField, KeyNamespaceTarget, int(target.Int())); err != nil {
+			return nil, err
+		}
+	}
+
+	return migrated, nil
+}
+
+// MigrateNode re-resolves an index-based InjectionConf node created against the snapshot
+func MigrateNode(n *Node, snapshot *resourcelookup.CatalogSnapshot) (*Node, error) {
+	// The old indexes are only meaningful in the snapshot, so they are not range-checked against the current catalogs here
+	conf, err := decodeInjectionNode(n)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated, err := MigrateInjectionConf(conf, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return InjectionConfToNode(migrated)
+}
+
+// decodeInjectionNode copies the values of an InjectionConf node into a struct without range checks
+func decodeInjectionNode(n *Node) (*InjectionConf, error) {
+	if n == nil {
+		return nil, fmt.Errorf("input node is nil")
+	}
+
+	entry, ok := chaosTypeByID(n.Value)
+	if !ok {
+		return nil, fmt.Errorf("invalid chaos type %d for InjectionConf (registered: %v)", n.Value, RegisteredChaosTypes())
+	}
+
+	specNode, exists := n.Children[strconv.Itoa(n.Value)]
+	if !exists || specNode == nil {
+		return nil, fmt.Errorf("expected child key '%d' not found in node children", n.Value)
+	}
+
+	conf := &InjectionConf{}
+	spec := entry.newSpec()
+	conf.setSpec(entry, spec)
+	specVal := spec.Elem()
+
+	for key, child := range specNode.Children {
+		idx, err := strconv.Atoi(key)
+		if err != nil || idx < 0 || idx >= specVal.NumField() {
+			return nil, fmt.Errorf("invalid child key '%s' for %s", key, specVal.Type().Name())
+		}
+
+		if err := setValue(specVal.Field(idx), child.Value); err != nil {
+			return nil, fmt.Errorf("field '%s': %w", specVal.Type().Field(idx).Name, err)
+		}
+	}
+
+	return conf, nil
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMigrateInjectionConf(t *testing.T) {
+	prevPrefixs := NamespacePrefixs
+	NamespacePrefixs = []string{"ts"}
+	defer func() { NamespacePrefixs = prevPrefixs }()
+
+	auth := resourcelookup.AppMethodPair{AppName: "ts-auth-service", ClassName: "auth.AuthController", MethodName: "login"}
+	order := resourcelookup.AppMethodPair{AppName: "ts-order-service", ClassName: "order.OrderController", MethodName: "create"}
+	travel := resourcelookup.AppMethodPair{AppName: "ts-travel-service", ClassName: "travel.TravelController", MethodName: "query"}
+
+	// The regenerated catalog gained a method that shifts every later index
+	oldSnapshot := &resourcelookup.CatalogSnapshot{Systems: map[string]*resourcelookup.SystemSnapshot{
+		"ts": {Methods: []resourcelookup.AppMethodPair{auth, order, travel}},
+	}}
+	newSnapshot := &resourcelookup.CatalogSnapshot{Systems: map[string]*resourcelookup.SystemSnapshot{
+		"ts": {Methods: []resourcelookup.AppMethodPair{
+			{AppName: "ts-admin-service", ClassName: "admin.AdminController", MethodName: "home"},
+			auth, order, travel,
+		}},
+	}}
+
+	conf := &InjectionConf{JVMLatency: &JVMLatencySpec{
+		Duration:        5,
+		Namespace:       0,
+		MethodIdx:       1,
+		LatencyDuration: 300,
+		NamespaceTarget: 2,
+	}}
+
+	migrated, err := migrateInjectionConf(conf, snapshotSystem(oldSnapshot), snapshotSystem(newSnapshot))
+	if err != nil {
+		t.Fatalf("migrateInjectionConf() error = %v", err)
+	}
+
+	got := migrated.JVMLatency
+	if got == nil {
+		t.Fatalf("migrateInjectionConf() lost the chaos type")
+	}
+
+	if got.MethodIdx != 2 {
+		t.Errorf("MethodIdx = %d, want 2", got.MethodIdx)
+	}
+
+	if got.Duration != 5 || got.LatencyDuration != 300 || got.NamespaceTarget != 2 {
+		t.Errorf("params were not preserved: %+v", got)
+	}
+
+	stable, err := migrated.toStable(snapshotSystem(newSnapshot))
+	if err != nil {
+		t.Fatalf("toStable() error = %v", err)
+	}
+
+	if stable.InjectionPoints[KeyMethod] != order.ID() {
+		t.Errorf("InjectionPoints = %v, want %s at %s", stable.InjectionPoints, order.ID(), KeyMethod)
+	}
+
+	// Methods come from the catalog, so migrating to the current catalog needs no cluster
+	useTestSystem(t, "migsys", 1, nil)
+	resourcelookup.RegisterCatalog("migsys", &resourcelookup.StaticCatalog{MethodList: newSnapshot.Systems["ts"].Methods})
+	oldSnapshot.Systems["migsys"] = oldSnapshot.Systems["ts"]
+
+	migrated, err = MigrateInjectionConf(conf, oldSnapshot)
+	if err != nil {
+		t.Fatalf("MigrateInjectionConf() without a cluster error = %v", err)
+	}
+	if migrated.JVMLatency.MethodIdx != 2 {
+		t.Errorf("MethodIdx = %d, want 2", migrated.JVMLatency.MethodIdx)
+	}
+}
+
+type twoPointTestSpec struct {
+	Duration        int `range:"1-5" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	AppIdx          int `range:"0-0" dynamic:"true" description:"App Index"`
+	MethodIdx       int `range:"0-0" dynamic:"true" description:"Method Index"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+}
+
+func (s *twoPointTestSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	return "two-point-test", nil
+}
+
+func TestStableInjectionPoints(t *testing.T) {
+	useTestSystem(t, "twosys", 1, nil)
+
+	chaosType, err := RegisterChaosType(ChaosTypeRegistration{Name: "TwoPointTest", ID: FirstCustomChaosType, Spec: &twoPointTestSpec{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterChaosType(chaosType) })
+
+	methods := []resourcelookup.AppMethodPair{
+		{AppName: "cart", ClassName: "cart.CartController", MethodName: "add"},
+		{AppName: "cart", ClassName: "cart.CartController", MethodName: "remove"},
+	}
+	resolve := snapshotSystem(&resourcelookup.CatalogSnapshot{Systems: map[string]*resourcelookup.SystemSnapshot{
+		"twosys": {AppLabels: []string{"cart", "frontend"}, Methods: methods},
+	}})
+
+	conf, err := NewInjectionConf(&twoPointTestSpec{Duration: 2, AppIdx: 1, MethodIdx: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stable, err := conf.toStable(resolve)
+	if err != nil {
+		t.Fatalf("toStable() error = %v", err)
+	}
+	want := map[string]string{KeyApp: resourcelookup.AppID("frontend"), KeyMethod: methods[1].ID()}
+	if !reflect.DeepEqual(stable.InjectionPoints, want) {
+		t.Errorf("InjectionPoints = %v, want %v", stable.InjectionPoints, want)
+	}
+
+	back, err := stableToConf(stable, resolve)
+	if err != nil {
+		t.Fatalf("stableToConf() error = %v", err)
+	}
+	if spec, ok := back.custom.(*twoPointTestSpec); !ok || spec.AppIdx != 1 || spec.MethodIdx != 1 {
+		t.Errorf("stableToConf() = %+v, want both injection points back", back.custom)
+	}
+}
+
+func TestInjectionConfToNode(t *testing.T) {
+	conf := &InjectionConf{PodKill: &PodKillSpec{Duration: 3, Namespace: 0, AppIdx: 4}}
+
+	node, err := InjectionConfToNode(conf)
+	if err != nil {
+		t.Fatalf("InjectionConfToNode() error = %v", err)
+	}
+
+	decoded, err := decodeInjectionNode(node)
+	if err != nil {
+		t.Fatalf("decodeInjectionNode() error = %v", err)
+	}
+
+	if decoded.PodKill == nil || *decoded.PodKill != *conf.PodKill {
+		t.Errorf("round trip = %+v, want %+v", decoded.PodKill, conf.PodKill)
+	}
+}
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	chaos "github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	controllers "github.com/LGU-SE-Internal/chaos-experiment/controllers"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	"k8s.io/utils/pointer"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Map for IO error code conversion from int to errno
+var ioErrnoMap = map[int]uint32{
+	1: 5,  // EIO
+	2: 2,  // ENOENT
+	3: 13, // EACCES
+	4: 28, // ENOSPC
+	5: 30, // EROFS
+}
+
+// Map for IO mistake filling conversion from int to chaos-mesh FillingType
+var ioFillingMap = map[int]chaosmeshv1alpha1.FillingType{
+	1: chaosmeshv1alpha1.Zero,
+	2: chaosmeshv1alpha1.Random,
+}
+
+// Convert int error code to errno, default to EIO
+func getIOErrno(errorCode int) uint32 {
+	if errno, ok := ioErrnoMap[errorCode]; ok {
+		return errno
+	}
+	return 5
+}
+
+// Convert int filling code to chaos-mesh FillingType
+func getIOFilling(fillingCode int) chaosmeshv1alpha1.FillingType {
+	if filling, ok := ioFillingMap[fillingCode]; ok {
+		return filling
+	}
+	return chaosmeshv1alpha1.Zero
+}
+
+// Helper function to validate and get volume info from index
+func getVolumeByIndex(namespace string, volumeIdx int) (*resourcelookup.VolumeInfo, error) {
+	volumes, err := resourcelookup.GetAllVolumes(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volumes: %w", err)
+	}
+
+	if volumeIdx < 0 || volumeIdx >= len(volumes) {
+		return nil, fmt.Errorf("volume index out of range: %d (max: %d)", volumeIdx, len(volumes)-1)
+	}
+
+	return &volumes[volumeIdx], nil
+}
+
+// IODelaySpec defines the IO latency chaos injection parameters
+type IODelaySpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	VolumeIdx       int `range:"0-0" dynamic:"true" description:"Flattened container volume index"`
+	Delay           int `range:"1-5000" description:"Delay in milliseconds"`
+	Percent         int `range:"1-100" description:"Percentage of IO operations affected"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
+}
+
+func (s *IODelaySpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	volume, err := getVolumeByIndex(ns, s.VolumeIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+
+	optss := []chaos.OptIOChaos{
+		chaos.WithIODelayAction(fmt.Sprintf("%dms", s.Delay)),
+		chaos.WithIOPercent(s.Percent),
+	}
+
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(volume.AppLabel)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithIOPodSelector(selector...))
+
+	return controllers.CreateIOChaosWithContainer(cli, ctx, ns, volume.AppLabel, volume.VolumePath,
+		"io-delay", duration, annotations, labels, []string{volume.ContainerName}, optss...)
+}
+
+// IOErrorSpec defines the IO fault chaos injection parameters
+type IOErrorSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	VolumeIdx       int `range:"0-0" dynamic:"true" description:"Flattened container volume index"`
+	ErrorCode       int `range:"1-5" description:"Errno (1=EIO, 2=ENOENT, 3=EACCES, 4=ENOSPC, 5=EROFS)"`
+	Percent         int `range:"1-100" description:"Percentage of IO operations affected"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
+}
+
+func (s *IOErrorSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	volume, err := getVolumeByIndex(ns, s.VolumeIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+
+	optss := []chaos.OptIOChaos{
+		chaos.WithIOErrorAction(getIOErrno(s.ErrorCode)),
+		chaos.WithIOPercent(s.Percent),
+	}
+
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(volume.AppLabel)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithIOPodSelector(selector...))
+
+	return controllers.CreateIOChaosWithContainer(cli, ctx, ns, volume.AppLabel, volume.VolumePath,
+		"io-error", duration, annotations, labels, []string{volume.ContainerName}, optss...)
+}
+
+// IOMistakeSpec defines the IO data corruption chaos injection parameters
+type IOMistakeSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	VolumeIdx       int `range:"0-0" dynamic:"true" description:"Flattened container volume index"`
+	Filling         int `range:"1-2" description:"Filling (1=zero, 2=random)"`
+	MaxOccurrences  int `range:"1-10" description:"Maximum number of mistakes per operation"`
+	MaxLength       int `range:"1-4096" description:"Maximum length of a mistake in bytes"`
+	Percent         int `range:"1-100" description:"Percentage of IO operations affected"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
+}
+
+func (s *IOMistakeSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	volume, err := getVolumeByIndex(ns, s.VolumeIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+
+	optss := []chaos.OptIOChaos{
+		chaos.WithIOMistakeAction(getIOFilling(s.Filling), int64(s.MaxOccurrences), int64(s.MaxLength)),
+		chaos.WithIOPercent(s.Percent),
+	}
+
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(volume.AppLabel)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithIOPodSelector(selector...))
+
+	return controllers.CreateIOChaosWithContainer(cli, ctx, ns, volume.AppLabel, volume.VolumePath,
+		"io-mistake", duration, annotations, labels, []string{volume.ContainerName}, optss...)
+}
+
+// IOAttrOverrideSpec defines the IO attribute override chaos injection parameters
+type IOAttrOverrideSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	VolumeIdx       int `range:"0-0" dynamic:"true" description:"Flattened container volume index"`
+	Perm            int `range:"0-511" description:"File permission bits in decimal (e.g. 292=0444)"`
+	Percent         int `range:"1-100" description:"Percentage of IO operations affected"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
+}
+
+func (s *IOAttrOverrideSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	volume, err := getVolumeByIndex(ns, s.VolumeIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+	perm := uint16(s.Perm)
+
+	optss := []chaos.OptIOChaos{
+		chaos.WithIOAttrOverrideAction(&chaosmeshv1alpha1.AttrOverrideSpec{Perm: &perm}),
+		chaos.WithIOPercent(s.Percent),
+	}
+
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(volume.AppLabel)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithIOPodSelector(selector...))
+
+	return controllers.CreateIOChaosWithContainer(cli, ctx, ns, volume.AppLabel, volume.VolumePath,
+		"io-attr-override", duration, annotations, labels, []string{volume.ContainerName}, optss...)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIOChaos(t *testing.T) {
+	pod := func(name, app string, containers ...corev1.Container) *corev1.Pod {
+		pod := testPod("iosys0", app)
+		pod.Name = name
+		pod.Spec.Containers = containers
+		return pod
+	}
+	token := corev1.VolumeMount{Name: "token", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"}
+	cart := corev1.Container{Name: "cart", VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}, token}}
+	sidecar := corev1.Container{Name: "sidecar", VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/var/log"}}}
+	frontend := corev1.Container{Name: "frontend", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/tmp/cache"}, token}}
+
+	fakeClient := newTestClient(t,
+		pod("cart-0", "cart", cart, sidecar),
+		pod("cart-1", "cart", cart, sidecar),
+		pod("frontend-0", "frontend", frontend),
+	)
+	useTestSystem(t, "iosys", 1, fakeClient)
+
+	// The replicas of an app share one entry per mount, service account tokens are left out
+	volumes, err := resourcelookup.GetAllVolumes("iosys0")
+	if err != nil {
+		t.Fatalf("GetAllVolumes() error = %v", err)
+	}
+	var got []string
+	for _, volume := range volumes {
+		got = append(got, volume.AppLabel+"/"+volume.ContainerName+":"+volume.VolumePath)
+	}
+	want := []string{"cart/cart:/data", "cart/sidecar:/var/log", "frontend/frontend:/tmp/cache"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetAllVolumes() = %v, want %v", got, want)
+	}
+
+	ctx := context.Background()
+	for _, idx := range []int{-1, len(volumes)} {
+		_, err := (&IODelaySpec{Duration: 1, VolumeIdx: idx, Delay: 100, Percent: 50}).Create(fakeClient, WithContext(ctx))
+		if err == nil || !strings.Contains(err.Error(), "volume index out of range") {
+			t.Errorf("Create() with volume index %d error = %v, want out of range", idx, err)
+		}
+	}
+
+	perm := uint16(292)
+	tests := []struct {
+		name   string
+		spec   Injection
+		volume int
+		want   chaosmeshv1alpha1.IOChaosSpec
+	}{
+		{
+			name:   "delay",
+			spec:   &IODelaySpec{Duration: 1, VolumeIdx: 1, Delay: 100, Percent: 50},
+			volume: 1,
+			want:   chaosmeshv1alpha1.IOChaosSpec{Action: chaosmeshv1alpha1.IoLatency, Delay: "100ms", Percent: 50, VolumePath: "/var/log"},
+		},
+		{
+			name:   "error",
+			spec:   &IOErrorSpec{Duration: 1, VolumeIdx: 0, ErrorCode: 4, Percent: 10},
+			volume: 0,
+			want:   chaosmeshv1alpha1.IOChaosSpec{Action: chaosmeshv1alpha1.IoFaults, Errno: 28, Percent: 10, VolumePath: "/data"},
+		},
+		{
+			name:   "mistake",
+			spec:   &IOMistakeSpec{Duration: 1, VolumeIdx: 2, Filling: 2, MaxOccurrences: 3, MaxLength: 64, Percent: 20},
+			volume: 2,
+			want: chaosmeshv1alpha1.IOChaosSpec{
+				Action:     chaosmeshv1alpha1.IoMistake,
+				Mistake:    &chaosmeshv1alpha1.MistakeSpec{Filling: chaosmeshv1alpha1.Random, MaxOccurrences: 3, MaxLength: 64},
+				Percent:    20,
+				VolumePath: "/tmp/cache",
+			},
+		},
+		{
+			name:   "attribute override",
+			spec:   &IOAttrOverrideSpec{Duration: 1, VolumeIdx: 0, Perm: int(perm), Percent: 100},
+			volume: 0,
+			want: chaosmeshv1alpha1.IOChaosSpec{
+				Action:     chaosmeshv1alpha1.IoAttrOverride,
+				Attr:       &chaosmeshv1alpha1.AttrOverrideSpec{Perm: &perm},
+				Percent:    100,
+				VolumePath: "/data",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := tt.spec.Create(fakeClient, WithContext(ctx))
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			ioChaos := &chaosmeshv1alpha1.IOChaos{}
+			if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "iosys0", Name: name}, ioChaos); err != nil {
+				t.Fatal(err)
+			}
+
+			spec := ioChaos.Spec
+			got := chaosmeshv1alpha1.IOChaosSpec{
+				Action:     spec.Action,
+				Delay:      spec.Delay,
+				Errno:      spec.Errno,
+				Attr:       spec.Attr,
+				Mistake:    spec.Mistake,
+				Percent:    spec.Percent,
+				VolumePath: spec.VolumePath,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IOChaos spec = %+v, want %+v", got, tt.want)
+			}
+
+			volume := volumes[tt.volume]
+			if !reflect.DeepEqual(spec.ContainerNames, []string{volume.ContainerName}) || spec.Selector.LabelSelectors["app"] != volume.AppLabel {
+				t.Errorf("IOChaos selects containers %v of app %s, want %s of %s",
+					spec.ContainerNames, spec.Selector.LabelSelectors["app"], volume.ContainerName, volume.AppLabel)
+			}
+		})
+	}
+}
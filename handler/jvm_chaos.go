@@ -3,7 +3,6 @@ package handler
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"strconv"
 	"strings"
 
@@ -39,6 +38,8 @@ type JVMLatencySpec struct {
 	MethodIdx       int `range:"0-0" dynamic:"true" description:"Flattened app+method index"`
 	LatencyDuration int `range:"1-5000" description:"Latency in ms"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMLatencySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -67,7 +68,7 @@ func (s *JVMLatencySpec) Create(cli cli.Client, opts ...Option) (string, error)
 		ns = conf.Namespace
 	}
 
-	methods, err := resourcelookup.GetAllJVMMethods()
+	methods, err := resourcelookup.GetAllJVMMethods(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get JVM methods: %w", err)
 	}
@@ -89,6 +90,12 @@ func (s *JVMLatencySpec) Create(cli cli.Client, opts ...Option) (string, error)
 		chaos.WithJVMLatencyDuration(s.LatencyDuration),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMLatencyAction, duration, annotations, labels, optss...)
 }
@@ -102,6 +109,8 @@ type JVMReturnSpec struct {
 	ReturnType      JVMReturnType `range:"1-2" description:"Return Type (1=String, 2=Int)"`
 	ReturnValueOpt  int           `range:"0-1" description:"Return value option (0=Default, 1=Random)"`
 	NamespaceTarget int           `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int           `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int           `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMReturnSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -130,7 +139,7 @@ func (s *JVMReturnSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 		ns = conf.Namespace
 	}
 
-	methods, err := resourcelookup.GetAllJVMMethods()
+	methods, err := resourcelookup.GetAllJVMMethods(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get JVM methods: %w", err)
 	}
@@ -159,14 +168,21 @@ func (s *JVMReturnSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 			optss = append(optss, chaos.WithJVMDefaultIntReturn())
 		}
 	} else {
-		// Use random value
+		// Use a random value drawn from the spec, so the same action returns the same value
+		r := specRand(s)
 		if s.ReturnType == StringReturn {
-			optss = append(optss, chaos.WithJVMRandomStringReturn(8))
+			optss = append(optss, chaos.WithJVMReturnValue(fmt.Sprintf("\"%s\"", randomString(r, 8))))
 		} else {
-			optss = append(optss, chaos.WithJVMRandomIntReturn(1, 1000))
+			optss = append(optss, chaos.WithJVMReturnValue(strconv.Itoa(1+r.Intn(1000))))
 		}
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMReturnAction, duration, annotations, labels, optss...)
 }
@@ -179,6 +195,8 @@ type JVMExceptionSpec struct {
 	MethodIdx       int `range:"0-0" dynamic:"true" description:"Flattened app+method index"`
 	ExceptionOpt    int `range:"0-1" description:"Exception option (0=Default, 1=Random)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMExceptionSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -207,7 +225,7 @@ func (s *JVMExceptionSpec) Create(cli cli.Client, opts ...Option) (string, error
 		ns = conf.Namespace
 	}
 
-	methods, err := resourcelookup.GetAllJVMMethods()
+	methods, err := resourcelookup.GetAllJVMMethods(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get JVM methods: %w", err)
 	}
@@ -240,11 +258,17 @@ func (s *JVMExceptionSpec) Create(cli cli.Client, opts ...Option) (string, error
 			"java.lang.RuntimeException(\"Unexpected error\")",
 			"java.sql.SQLException(\"Database error\")",
 		}
-		// Pick a random exception from the list
-		randomIndex := rand.Intn(len(randomExceptions))
+		// Pick a random exception from the list, drawn from the spec so the same action throws the same exception
+		randomIndex := specRand(s).Intn(len(randomExceptions))
 		optss = append(optss, chaos.WithJVMException(randomExceptions[randomIndex]))
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMExceptionAction, duration, annotations, labels, optss...)
 }
@@ -255,6 +279,8 @@ type JVMGCSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	AppIdx          int `range:"0-0" dynamic:"true" description:"App Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMGCSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -295,8 +321,13 @@ func (s *JVMGCSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 	appName := appLabels[s.AppIdx]
 	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
-		chaosmeshv1alpha1.JVMGCAction, duration, annotations, labels)
+		chaosmeshv1alpha1.JVMGCAction, duration, annotations, labels, chaos.WithJVMPodSelector(selector...))
 }
 
 // JVMCPUStressSpec defines the JVM CPU stress chaos injection parameters
@@ -307,6 +338,8 @@ type JVMCPUStressSpec struct {
 	MethodIdx       int `range:"0-0" dynamic:"true" description:"Flattened app+method index"`
 	CPUCount        int `range:"1-8" description:"Number of CPU cores to stress"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMCPUStressSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -335,7 +368,7 @@ func (s *JVMCPUStressSpec) Create(cli cli.Client, opts ...Option) (string, error
 		ns = conf.Namespace
 	}
 
-	methods, err := resourcelookup.GetAllJVMMethods()
+	methods, err := resourcelookup.GetAllJVMMethods(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get JVM methods: %w", err)
 	}
@@ -357,6 +390,12 @@ func (s *JVMCPUStressSpec) Create(cli cli.Client, opts ...Option) (string, error
 		chaos.WithJVMStressCPUCount(s.CPUCount),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMStressAction, duration, annotations, labels, optss...)
 }
@@ -369,6 +408,8 @@ type JVMMemoryStressSpec struct {
 	MethodIdx       int           `range:"0-0" dynamic:"true" description:"Flattened app+method index"`
 	MemType         JVMMemoryType `range:"1-2" description:"Memory Type (1=Heap, 2=Stack)"`
 	NamespaceTarget int           `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int           `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int           `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMMemoryStressSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -397,7 +438,7 @@ func (s *JVMMemoryStressSpec) Create(cli cli.Client, opts ...Option) (string, er
 		ns = conf.Namespace
 	}
 
-	methods, err := resourcelookup.GetAllJVMMethods()
+	methods, err := resourcelookup.GetAllJVMMethods(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get JVM methods: %w", err)
 	}
@@ -425,6 +466,12 @@ func (s *JVMMemoryStressSpec) Create(cli cli.Client, opts ...Option) (string, er
 		chaos.WithJVMStressMemType(memType),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMStressAction, duration, annotations, labels, optss...)
 }
@@ -456,6 +503,8 @@ type JVMMySQLLatencySpec struct {
 	DatabaseIdx     int `range:"0-0" dynamic:"true" description:"Flattened app+database+table index"`
 	LatencyMs       int `range:"10-5000" description:"Latency in ms"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMMySQLLatencySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -484,7 +533,7 @@ func (s *JVMMySQLLatencySpec) Create(cli cli.Client, opts ...Option) (string, er
 		ns = conf.Namespace
 	}
 
-	dbOps, err := resourcelookup.GetAllDatabaseOperations()
+	dbOps, err := resourcelookup.GetAllDatabaseOperations(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get database operations: %w", err)
 	}
@@ -508,6 +557,12 @@ func (s *JVMMySQLLatencySpec) Create(cli cli.Client, opts ...Option) (string, er
 		chaos.WithJVMLatencyDuration(s.LatencyMs),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMMySQLAction, duration, annotations, labels, optss...)
 }
@@ -518,6 +573,8 @@ type JVMMySQLExceptionSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	DatabaseIdx     int `range:"0-0" dynamic:"true" description:"Flattened app+database+table index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *JVMMySQLExceptionSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -546,7 +603,7 @@ func (s *JVMMySQLExceptionSpec) Create(cli cli.Client, opts ...Option) (string,
 		ns = conf.Namespace
 	}
 
-	dbOps, err := resourcelookup.GetAllDatabaseOperations()
+	dbOps, err := resourcelookup.GetAllDatabaseOperations(ns)
 	if err != nil {
 		return "", fmt.Errorf("failed to get database operations: %w", err)
 	}
@@ -573,6 +630,12 @@ func (s *JVMMySQLExceptionSpec) Create(cli cli.Client, opts ...Option) (string,
 		chaos.WithJVMException(exceptionMsg),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithJVMPodSelector(selector...))
+
 	return controllers.CreateJVMChaos(cli, ctx, ns, appName,
 		chaosmeshv1alpha1.JVMMySQLAction, duration, annotations, labels, optss...)
 }
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	chaos "github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	controllers "github.com/LGU-SE-Internal/chaos-experiment/controllers"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	"k8s.io/utils/pointer"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KernelFaultSpec defines the kernel fault injection parameters
+type KernelFaultSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	ContainerIdx    int `range:"0-0" dynamic:"true" description:"Container Index"`
+	FailType        int `range:"0-2" description:"Kernel call to fail: 0 slab, 1 alloc_page, 2 bio"`
+	Probability     int `range:"1-100" description:"Percentage of kernel calls that fail"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
+}
+
+func (s *KernelFaultSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	containers, err := resourcelookup.GetAllContainers(ns)
+	if err != nil {
+		return "", fmt.Errorf("failed to get containers: %w", err)
+	}
+
+	if s.ContainerIdx < 0 || s.ContainerIdx >= len(containers) {
+		return "", fmt.Errorf("container index out of range: %d (max: %d)", s.ContainerIdx, len(containers)-1)
+	}
+
+	containerInfo := containers[s.ContainerIdx]
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+
+	optss := []chaos.OptKernelChaos{
+		chaos.WithKernelProbability(uint32(s.Probability)),
+		chaos.WithKernelContainerNames([]string{containerInfo.ContainerName}),
+	}
+
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(containerInfo.AppLabel)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithKernelPodSelector(selector...))
+
+	return controllers.CreateKernelChaos(cli, ctx, ns, containerInfo.AppLabel, int32(s.FailType), duration, annotations, labels, optss...)
+}
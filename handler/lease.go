@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LeaseAnnotationKey holds the lease of a target namespace on the Namespace object, so every
+	// process injecting into the cluster sees the same leases
+	LeaseAnnotationKey = "chaos-experiment/lease"
+
+	// AutoNamespaceTarget lets InjectionConf.Create lease a free target namespace instead of using
+	// a fixed target index
+	AutoNamespaceTarget = -1
+
+	DefaultLeaseCooldown = 5 * time.Minute
+)
+
+// ErrNoFreeTarget is returned when every target namespace of a prefix is leased
+var ErrNoFreeTarget = errors.New("no free target namespace")
+
+// ErrTargetLeased is returned when injecting into a fixed target namespace that another holder leased
+var ErrTargetLeased = errors.New("target namespace is leased")
+
+// Lease is the exclusive use of a target namespace until ExpiresAt, which covers the chaos duration
+// and the cool-down after the recovery
+type Lease struct {
+	Namespace  string    `json:"namespace"`
+	Prefix     string    `json:"prefix"`
+	Target     int       `json:"target"`
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Active reports whether the lease still holds the namespace at the time
+func (l *Lease) Active(now time.Time) bool {
+	return l != nil && now.Before(l.ExpiresAt)
+}
+
+type LeaseConf struct {
+	Client       cli.Client
+	Holder       string
+	Cooldown     time.Duration
+	WaitInterval time.Duration
+}
+
+type LeaseOption func(*LeaseConf)
+
+// WithLeaseClient stores the leases through the client instead of the registered providers
+func WithLeaseClient(k8sClient cli.Client) LeaseOption {
+	return func(c *LeaseConf) {
+		c.Client = k8sClient
+	}
+}
+
+// WithLeaseHolder names the holder of the lease, by default the hostname and process ID
+func WithLeaseHolder(holder string) LeaseOption {
+	return func(c *LeaseConf) {
+		c.Holder = holder
+	}
+}
+
+// WithLeaseCooldown sets how long a namespace stays leased after the chaos recovers,
+// DefaultLeaseCooldown by default
+func WithLeaseCooldown(cooldown time.Duration) LeaseOption {
+	return func(c *LeaseConf) {
+		c.Cooldown = cooldown
+	}
+}
+
+// WithLeaseWait polls for a free target namespace at the interval instead of failing with
+// ErrNoFreeTarget, until the context is done
+func WithLeaseWait(interval time.Duration) LeaseOption {
+	return func(c *LeaseConf) {
+		c.WaitInterval = interval
+	}
+}
+
+func newLeaseConf(opts []LeaseOption) LeaseConf {
+	conf := LeaseConf{Cooldown: DefaultLeaseCooldown}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	if conf.Holder == "" {
+		hostname, _ := os.Hostname()
+		conf.Holder = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	return conf
+}
+
+// AcquireLease leases the first free target namespace of the prefix for the duration plus the
+// cool-down. The lease is written with optimistic locking, so concurrent callers never get the same
+// namespace.
+func AcquireLease(ctx context.Context, prefix string, duration time.Duration, opts ...LeaseOption) (*Lease, error) {
+	conf := newLeaseConf(opts)
+
+	count, ok := NamespaceTargetMap[prefix]
+	if !ok {
+		return nil, fmt.Errorf("namespace prefix %s is not configured (available: %v)", prefix, NamespacePrefixs)
+	}
+
+	targets := make([]int, 0, count)
+	for target := DefaultStartIndex; target < DefaultStartIndex+count; target++ {
+		targets = append(targets, target)
+	}
+
+	return acquireLease(ctx, prefix, targets, duration, conf, false)
+}
+
+// AcquireTargetLease leases the target namespace of the index, like AcquireLease but for a fixed
+// target. A lease the holder already has on the namespace is renewed, so a caller injecting into
+// the same target again, e.g. a campaign, does not wait for itself.
+func AcquireTargetLease(ctx context.Context, prefix string, target int, duration time.Duration, opts ...LeaseOption) (*Lease, error) {
+	conf := newLeaseConf(opts)
+
+	if _, ok := NamespaceTargetMap[prefix]; !ok {
+		return nil, fmt.Errorf("namespace prefix %s is not configured (available: %v)", prefix, NamespacePrefixs)
+	}
+
+	return acquireLease(ctx, prefix, []int{target}, duration, conf, true)
+}
+
+func acquireLease(ctx context.Context, prefix string, targets []int, duration time.Duration, conf LeaseConf, renew bool) (*Lease, error) {
+	k8sClient, err := leaseClient(conf, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		for _, target := range targets {
+			lease, err := tryAcquireLease(ctx, k8sClient, prefix, target, duration, conf, renew)
+			if err == nil {
+				return lease, nil
+			}
+			if !errors.Is(err, ErrNoFreeTarget) && !apierrors.IsConflict(err) {
+				return nil, err
+			}
+		}
+
+		if conf.WaitInterval <= 0 {
+			return nil, fmt.Errorf("%w in namespace prefix %s", ErrNoFreeTarget, prefix)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(conf.WaitInterval):
+		}
+	}
+}
+
+func tryAcquireLease(ctx context.Context, k8sClient cli.Client, prefix string, target int, duration time.Duration, conf LeaseConf, renew bool) (*Lease, error) {
+	namespace := &corev1.Namespace{}
+	name := fmt.Sprintf("%s%d", prefix, target)
+	if err := k8sClient.Get(ctx, cli.ObjectKey{Name: name}, namespace); err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+
+	now := time.Now()
+	if current, err := leaseOf(namespace); err != nil {
+		return nil, err
+	} else if current.Active(now) && !(renew && current.Holder == conf.Holder) {
+		return nil, ErrNoFreeTarget
+	}
+
+	lease := &Lease{
+		Namespace:  name,
+		Prefix:     prefix,
+		Target:     target,
+		Holder:     conf.Holder,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(duration + conf.Cooldown),
+	}
+	if err := writeLease(ctx, k8sClient, namespace, lease); err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+// ReleaseLease ends the lease once the cool-down has passed from now, for chaos that recovered
+// before the lease expired. A lease that was already taken over is left alone.
+func ReleaseLease(ctx context.Context, lease *Lease, opts ...LeaseOption) error {
+	conf := newLeaseConf(opts)
+
+	k8sClient, err := leaseClient(conf, lease.Prefix)
+	if err != nil {
+		return err
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, cli.ObjectKey{Name: lease.Namespace}, namespace); err != nil {
+		return fmt.Errorf("failed to get namespace %s: %w", lease.Namespace, err)
+	}
+
+	current, err := leaseOf(namespace)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Holder != lease.Holder || !current.AcquiredAt.Equal(lease.AcquiredAt) {
+		return nil
+	}
+
+	if expiresAt := time.Now().Add(conf.Cooldown); expiresAt.Before(current.ExpiresAt) {
+		current.ExpiresAt = expiresAt
+	}
+
+	return writeLease(ctx, k8sClient, namespace, current)
+}
+
+// ListLeases returns the active leases of the target namespaces of the prefix
+func ListLeases(ctx context.Context, prefix string, opts ...LeaseOption) ([]Lease, error) {
+	conf := newLeaseConf(opts)
+
+	k8sClient, err := leaseClient(conf, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Lease
+	now := time.Now()
+	for target := DefaultStartIndex; target < DefaultStartIndex+NamespaceTargetMap[prefix]; target++ {
+		namespace := &corev1.Namespace{}
+		name := fmt.Sprintf("%s%d", prefix, target)
+		if err := k8sClient.Get(ctx, cli.ObjectKey{Name: name}, namespace); err != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+
+		lease, err := leaseOf(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if lease.Active(now) {
+			result = append(result, *lease)
+		}
+	}
+
+	return result, nil
+}
+
+// CreateWithLease leases a free target namespace of the injection's prefix for the chaos duration
+// plus the cool-down and creates the injection there, through the client of WithLeaseClient if set.
+// The lease is released right away when the creation fails.
+func (ic *InjectionConf) CreateWithLease(ctx context.Context, annotations map[string]string, labels map[string]string, opts ...LeaseOption) (string, *Lease, error) {
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return "", nil, err
+	}
+
+	nsIdx := int(activeField.Elem().FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return "", nil, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+
+	lease, err := AcquireLease(ctx, NamespacePrefixs[nsIdx], chaosDuration(activeField), opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	k8sClient := newLeaseConf(opts).Client
+	if k8sClient == nil {
+		k8sClient, err = ic.client()
+	}
+	if err == nil {
+		var name string
+		if name, err = ic.createWithClient(ctx, k8sClient, lease.Target, annotations, labels); err == nil {
+			return name, lease, nil
+		}
+	}
+
+	if releaseErr := ReleaseLease(ctx, lease, append(opts, WithLeaseCooldown(0))...); releaseErr != nil {
+		err = errors.Join(err, releaseErr)
+	}
+	return "", nil, err
+}
+
+// checkLease fails with ErrTargetLeased when another holder than the default one of this process has
+// an active lease on the namespace. A namespace the client cannot find, e.g. that of a
+// client.DryRunClient, has no lease.
+func checkLease(ctx context.Context, k8sClient cli.Client, name string) error {
+	namespace := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, cli.ObjectKey{Name: name}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+
+	lease, err := leaseOf(namespace)
+	if err != nil {
+		return err
+	}
+	if lease.Active(time.Now()) && lease.Holder != newLeaseConf(nil).Holder {
+		return fmt.Errorf("%w: %s is held by %s until %s", ErrTargetLeased, name, lease.Holder, lease.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// chaosDuration is the Duration field of the active spec in minutes, zero for specs without one
+func chaosDuration(activeField reflect.Value) time.Duration {
+	if field := activeField.Elem().FieldByName("Duration"); field.IsValid() && field.CanInt() {
+		return time.Duration(field.Int()) * time.Minute
+	}
+	return 0
+}
+
+func leaseClient(conf LeaseConf, prefix string) (cli.Client, error) {
+	if conf.Client != nil {
+		return conf.Client, nil
+	}
+	return client.ProviderFor(prefix).Client()
+}
+
+// leaseOf returns the lease stored on the namespace, or nil
+func leaseOf(namespace *corev1.Namespace) (*Lease, error) {
+	value, ok := namespace.Annotations[LeaseAnnotationKey]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	lease := &Lease{}
+	if err := json.Unmarshal([]byte(value), lease); err != nil {
+		return nil, fmt.Errorf("failed to parse the lease of namespace %s: %w", namespace.Name, err)
+	}
+
+	return lease, nil
+}
+
+// writeLease updates the namespace, failing with a conflict when it changed since it was read
+func writeLease(ctx context.Context, k8sClient cli.Client, namespace *corev1.Namespace, lease *Lease) error {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the lease of namespace %s: %w", namespace.Name, err)
+	}
+
+	setAnnotation(namespace, LeaseAnnotationKey, string(data))
+	if err := k8sClient.Update(ctx, namespace); err != nil {
+		return fmt.Errorf("failed to write the lease of namespace %s: %w", namespace.Name, err)
+	}
+
+	return nil
+}
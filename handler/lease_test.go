@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNamespaceLease(t *testing.T) {
+	fakeClient := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leasesys0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leasesys1"}},
+		testPod("leasesys0", "frontend"),
+	)
+	useTestSystem(t, "leasesys", 2, fakeClient)
+
+	ctx := context.Background()
+	ic := &InjectionConf{PodFailure: &PodFailureSpec{Duration: 5}}
+
+	first, err := ic.Create(ctx, AutoNamespaceTarget, nil, nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	name, lease, err := ic.CreateWithLease(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWithLease() error = %v", err)
+	}
+	if lease.Namespace != "leasesys1" {
+		t.Errorf("second lease = %s, want the free leasesys1", lease.Namespace)
+	}
+
+	for namespace, name := range map[string]string{"leasesys0": first, "leasesys1": name} {
+		podChaos := &chaosmeshv1alpha1.PodChaos{}
+		if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: namespace, Name: name}, podChaos); err != nil {
+			t.Errorf("chaos %s not created in %s: %v", name, namespace, err)
+		}
+	}
+
+	if _, _, err := ic.CreateWithLease(ctx, nil, nil); !errors.Is(err, ErrNoFreeTarget) {
+		t.Fatalf("CreateWithLease() with every target leased error = %v, want ErrNoFreeTarget", err)
+	}
+
+	leases, err := ListLeases(ctx, "leasesys")
+	if err != nil || len(leases) != 2 {
+		t.Fatalf("ListLeases() = %d leases, error = %v", len(leases), err)
+	}
+
+	if err := ReleaseLease(ctx, lease, WithLeaseCooldown(0)); err != nil {
+		t.Fatalf("ReleaseLease() error = %v", err)
+	}
+
+	next, err := AcquireLease(ctx, "leasesys", 0, WithLeaseHolder("other"))
+	if err != nil {
+		t.Fatalf("AcquireLease() after release error = %v", err)
+	}
+	if next.Namespace != "leasesys1" || next.Holder != "other" {
+		t.Errorf("AcquireLease() = %+v, want the released leasesys1", next)
+	}
+
+	// releasing a lease that was taken over keeps the new holder's lease
+	if err := ReleaseLease(ctx, lease, WithLeaseCooldown(0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AcquireLease(ctx, "leasesys", 0); !errors.Is(err, ErrNoFreeTarget) {
+		t.Errorf("AcquireLease() error = %v, want ErrNoFreeTarget", err)
+	}
+
+	// a fixed target leased by another holder is refused, one leased by this process is not
+	if _, err := ic.Create(ctx, 1, nil, nil); !errors.Is(err, ErrTargetLeased) {
+		t.Errorf("Create() into the target leased by other error = %v, want ErrTargetLeased", err)
+	}
+	if _, err := ic.Create(ctx, 0, nil, nil); err != nil {
+		t.Errorf("Create() into the target leased by this process error = %v", err)
+	}
+
+	// the injection goes through the lease client as well
+	other := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leasesys0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leasesys1"}},
+	)
+	name, lease, err = ic.CreateWithLease(ctx, nil, nil, WithLeaseClient(other))
+	if err != nil {
+		t.Fatalf("CreateWithLease() with a lease client error = %v", err)
+	}
+	if err := other.Get(ctx, cli.ObjectKey{Namespace: lease.Namespace, Name: name}, &chaosmeshv1alpha1.PodChaos{}); err != nil {
+		t.Errorf("chaos %s not created through the lease client: %v", name, err)
+	}
+
+	// CreateWithClient leases the target namespace through its client instead of using the first one
+	leased := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leasesys0"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "leasesys1"}},
+	)
+	if _, err := AcquireTargetLease(ctx, "leasesys", 0, time.Minute, WithLeaseClient(leased), WithLeaseHolder("other")); err != nil {
+		t.Fatal(err)
+	}
+	name, err = ic.CreateWithClient(ctx, leased, AutoNamespaceTarget, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWithClient() with AutoNamespaceTarget error = %v", err)
+	}
+	if err := leased.Get(ctx, cli.ObjectKey{Namespace: "leasesys1", Name: name}, &chaosmeshv1alpha1.PodChaos{}); err != nil {
+		t.Errorf("chaos %s not created in the free target namespace: %v", name, err)
+	}
+	if leases, err := ListLeases(ctx, "leasesys", WithLeaseClient(leased)); err != nil || len(leases) != 2 {
+		t.Errorf("ListLeases() = %v, %v, want a lease on both target namespaces", leases, err)
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	DefaultWatchInterval = 5 * time.Second
+
+	physicalMachineChaosKind = "PhysicalMachineChaos"
+)
+
+// InjectionEvent is one Apply or Recover event recorded by Chaos Mesh on a target
+type InjectionEvent struct {
+	Operation chaosmeshv1alpha1.RecordEventOperation `json:"operation"`
+	Succeeded bool                                   `json:"succeeded"`
+	Target    string                                 `json:"target"`
+	Message   string                                 `json:"message,omitempty"`
+	Timestamp time.Time                              `json:"timestamp"`
+}
+
+// InjectionResult is the lifecycle of an injection as observed by the watcher
+type InjectionResult struct {
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Kind         string    `json:"kind"`
+	ApplyTime    time.Time `json:"apply_time"`
+	RecoverTime  time.Time `json:"recover_time"`
+	AffectedPods []string  `json:"affected_pods,omitempty"`
+	// AffectedMachines holds the PhysicalMachine objects or chaosd addresses a PhysicalMachineChaos targeted
+	AffectedMachines []string         `json:"affected_machines,omitempty"`
+	FailureReasons   []string         `json:"failure_reasons,omitempty"`
+	Events           []InjectionEvent `json:"events,omitempty"`
+	Applied          bool             `json:"applied"`
+	Recovered        bool             `json:"recovered"`
+}
+
+type WatchConf struct {
+	Interval     time.Duration
+	UntilApplied bool
+	Events       chan<- InjectionEvent
+}
+
+type WatchOption func(*WatchConf)
+
+// WithWatchInterval sets how often the chaos status is polled
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(c *WatchConf) {
+		c.Interval = interval
+	}
+}
+
+// WithUntilApplied makes the watcher return once the chaos is applied instead of waiting for recovery
+func WithUntilApplied() WatchOption {
+	return func(c *WatchConf) {
+		c.UntilApplied = true
+	}
+}
+
+// WithEventStream streams every new event to the channel while watching, the channel is not closed by the watcher.
+// Sends block until the event is received or the watch context is done.
+func WithEventStream(events chan<- InjectionEvent) WatchOption {
+	return func(c *WatchConf) {
+		c.Events = events
+	}
+}
+
+// WaitForInjection blocks until the chaos created with the given name has been applied and recovered
+// on all of its targets, or until ctx is done. The result observed so far is returned in both cases.
+// A nil client uses the client registered for the namespace.
+func WaitForInjection(ctx context.Context, k8sClient cli.Client, namespace, name string, opts ...WatchOption) (*InjectionResult, error) {
+	conf := WatchConf{Interval: DefaultWatchInterval}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	if k8sClient == nil {
+		var err error
+		if k8sClient, err = client.ProviderFor(namespace).Client(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &InjectionResult{Name: name, Namespace: namespace}
+	seen := make(map[string]bool)
+	found := false
+
+	ticker := time.NewTicker(conf.Interval)
+	defer ticker.Stop()
+
+	for {
+		obj, gvr, err := client.GetChaosByName(ctx, k8sClient, namespace, name)
+		switch {
+		case err == nil:
+			found = true
+			gvk, err := apiutil.GVKForObject(obj, k8sClient.Scheme())
+			if err != nil {
+				return result, fmt.Errorf("failed to resolve the kind of %s: %w", gvr.Resource, err)
+			}
+			result.Kind = gvk.Kind
+
+			if err := updateInjectionResult(ctx, result, obj.GetStatus(), seen, conf.Events); err != nil {
+				return result, fmt.Errorf("stopped watching chaos %s/%s: %w", namespace, name, err)
+			}
+
+			if result.Recovered || (conf.UntilApplied && result.Applied) {
+				return result, nil
+			}
+		case apierrors.IsNotFound(err) && found:
+			return result, fmt.Errorf("chaos %s/%s was deleted before it recovered", namespace, name)
+		case !apierrors.IsNotFound(err):
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("stopped watching chaos %s/%s: %w", namespace, name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateInjectionResult folds the records of the chaos status into the result. It only fails when
+// ctx is done before a new event could be streamed.
+func updateInjectionResult(ctx context.Context, result *InjectionResult, status *chaosmeshv1alpha1.ChaosStatus, seen map[string]bool, events chan<- InjectionEvent) error {
+	records := status.Experiment.Records
+
+	allApplied := len(records) > 0
+	allRecovered := len(records) > 0
+	for _, record := range records {
+		if result.Kind == physicalMachineChaosKind {
+			result.AffectedMachines = appendUnique(result.AffectedMachines, machineFromRecordID(record.Id))
+		} else {
+			result.AffectedPods = appendUnique(result.AffectedPods, podFromRecordID(record.Id))
+		}
+
+		var lastApply, lastRecover time.Time
+		for _, event := range record.Events {
+			var timestamp time.Time
+			if event.Timestamp != nil {
+				timestamp = event.Timestamp.Time
+			}
+
+			succeeded := event.Type == chaosmeshv1alpha1.TypeSucceeded
+			key := fmt.Sprintf("%s/%s/%s/%d", record.Id, event.Operation, event.Type, timestamp.UnixNano())
+			if !seen[key] {
+				seen[key] = true
+
+				injectionEvent := InjectionEvent{
+					Operation: event.Operation,
+					Succeeded: succeeded,
+					Target:    record.Id,
+					Message:   event.Message,
+					Timestamp: timestamp,
+				}
+				result.Events = append(result.Events, injectionEvent)
+
+				if events != nil {
+					select {
+					case events <- injectionEvent:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+
+			if !succeeded {
+				if event.Message != "" {
+					result.FailureReasons = appendUnique(result.FailureReasons, event.Message)
+				}
+				continue
+			}
+
+			switch event.Operation {
+			case chaosmeshv1alpha1.Apply:
+				lastApply = timestamp
+				if result.ApplyTime.IsZero() || timestamp.Before(result.ApplyTime) {
+					result.ApplyTime = timestamp
+				}
+			case chaosmeshv1alpha1.Recover:
+				lastRecover = timestamp
+				if timestamp.After(result.RecoverTime) {
+					result.RecoverTime = timestamp
+				}
+			}
+		}
+
+		if lastApply.IsZero() {
+			allApplied = false
+		}
+
+		if lastApply.IsZero() || lastRecover.Before(lastApply) {
+			allRecovered = false
+		}
+	}
+
+	result.Applied = result.Applied || allApplied
+	result.Recovered = allRecovered
+	return nil
+}
+
+// podFromRecordID extracts the pod name from a record id like "namespace/pod" or "namespace/pod/container"
+func podFromRecordID(id string) string {
+	parts := strings.Split(id, "/")
+	if len(parts) >= 2 {
+		return parts[1]
+	}
+	return id
+}
+
+// machineFromRecordID extracts the PhysicalMachine name from a record id like "namespace/machine",
+// a chaosd address like "http://10.0.0.1:31767" is kept whole
+func machineFromRecordID(id string) string {
+	if strings.Contains(id, "://") {
+		return id
+	}
+	return podFromRecordID(id)
+}
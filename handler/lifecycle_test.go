@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateInjectionResult(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) *metav1.Time {
+		ts := metav1.NewTime(start.Add(time.Duration(seconds) * time.Second))
+		return &ts
+	}
+
+	status := &chaosmeshv1alpha1.ChaosStatus{}
+	status.Experiment.Records = []*chaosmeshv1alpha1.Record{
+		{
+			Id: "ts0/ts-order-service-0",
+			Events: []chaosmeshv1alpha1.RecordEvent{
+				{Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Apply, Timestamp: at(1)},
+			},
+		},
+		{
+			Id: "ts0/ts-order-service-1",
+			Events: []chaosmeshv1alpha1.RecordEvent{
+				{Type: chaosmeshv1alpha1.TypeFailed, Operation: chaosmeshv1alpha1.Apply, Message: "pod not ready", Timestamp: at(1)},
+				{Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Apply, Timestamp: at(2)},
+			},
+		},
+	}
+
+	result := &InjectionResult{}
+	seen := make(map[string]bool)
+	events := make(chan InjectionEvent, 10)
+
+	if err := updateInjectionResult(context.Background(), result, status, seen, events); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Applied || result.Recovered {
+		t.Fatalf("after apply: Applied = %v, Recovered = %v", result.Applied, result.Recovered)
+	}
+
+	if !result.ApplyTime.Equal(at(1).Time) {
+		t.Errorf("ApplyTime = %v, want %v", result.ApplyTime, at(1).Time)
+	}
+
+	if got := len(events); got != 3 {
+		t.Errorf("streamed %d events, want 3", got)
+	}
+
+	for _, record := range status.Experiment.Records {
+		record.Events = append(record.Events, chaosmeshv1alpha1.RecordEvent{
+			Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Recover, Timestamp: at(10),
+		})
+	}
+
+	if err := updateInjectionResult(context.Background(), result, status, seen, events); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Recovered {
+		t.Fatalf("after recover: Recovered = false")
+	}
+
+	if !result.RecoverTime.Equal(at(10).Time) {
+		t.Errorf("RecoverTime = %v, want %v", result.RecoverTime, at(10).Time)
+	}
+
+	if got := len(result.Events); got != 5 {
+		t.Errorf("recorded %d events, want 5", got)
+	}
+
+	wantPods := []string{"ts-order-service-0", "ts-order-service-1"}
+	if !reflect.DeepEqual(result.AffectedPods, wantPods) {
+		t.Errorf("AffectedPods = %v, want %v", result.AffectedPods, wantPods)
+	}
+
+	if !reflect.DeepEqual(result.FailureReasons, []string{"pod not ready"}) {
+		t.Errorf("FailureReasons = %v", result.FailureReasons)
+	}
+}
+
+func TestUpdateInjectionResultMachines(t *testing.T) {
+	ts := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	status := &chaosmeshv1alpha1.ChaosStatus{}
+	for _, id := range []string{"ts0/machine-a", "http://10.0.0.1:31767"} {
+		status.Experiment.Records = append(status.Experiment.Records, &chaosmeshv1alpha1.Record{
+			Id:     id,
+			Events: []chaosmeshv1alpha1.RecordEvent{{Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Apply, Timestamp: &ts}},
+		})
+	}
+
+	result := &InjectionResult{Kind: "PhysicalMachineChaos"}
+	if err := updateInjectionResult(context.Background(), result, status, make(map[string]bool), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"machine-a", "http://10.0.0.1:31767"}
+	if !reflect.DeepEqual(result.AffectedMachines, want) || len(result.AffectedPods) != 0 {
+		t.Errorf("AffectedMachines = %v, AffectedPods = %v, want machines %v", result.AffectedMachines, result.AffectedPods, want)
+	}
+
+	// Nobody reads the stream, so the watcher gives up with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := updateInjectionResult(ctx, &InjectionResult{}, status, make(map[string]bool), make(chan InjectionEvent))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("updateInjectionResult() with a blocked stream error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWaitForInjectionKind(t *testing.T) {
+	ts := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	networkChaos := &chaosmeshv1alpha1.NetworkChaos{ObjectMeta: metav1.ObjectMeta{Name: "delay", Namespace: "ts0"}}
+	networkChaos.Status.Experiment.Records = []*chaosmeshv1alpha1.Record{{
+		Id: "ts0/ts-order-service-0",
+		Events: []chaosmeshv1alpha1.RecordEvent{
+			{Type: chaosmeshv1alpha1.TypeSucceeded, Operation: chaosmeshv1alpha1.Apply, Timestamp: &ts},
+		},
+	}}
+
+	result, err := WaitForInjection(context.Background(), newTestClient(t, networkChaos), "ts0", "delay", WithUntilApplied())
+	if err != nil {
+		t.Fatalf("WaitForInjection() error = %v", err)
+	}
+	if result.Kind != "NetworkChaos" {
+		t.Errorf("Kind = %s, want NetworkChaos", result.Kind)
+	}
+}
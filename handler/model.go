@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
 )
@@ -18,7 +19,8 @@ any node can be converted to a struct, and then to a map
 any map can be converted to a node, and then to a struct
 */
 
-// TODO 校验Node
+// Node is a node of the action space or of an action. InjectionConf value nodes can be checked
+// with Schema.ValidateNode.
 type Node struct {
 	Name        string           `json:"name"`
 	Range       []int            `json:"range"`
@@ -28,9 +30,35 @@ type Node struct {
 }
 
 var (
-	NodeNsPrefixMap map[*Node]string
+	// nodeNsPrefixMap holds the namespace prefix of every node conversion in progress, keyed by its
+	// root node, guarded by nodeNsPrefixMu as conversions run concurrently
+	nodeNsPrefixMu  sync.RWMutex
+	nodeNsPrefixMap = make(map[*Node]string)
 )
 
+// setNodeNsPrefix sets the namespace prefix of the conversion of the root node
+func setNodeNsPrefix(rootNode *Node, prefix string) {
+	nodeNsPrefixMu.Lock()
+	nodeNsPrefixMap[rootNode] = prefix
+	nodeNsPrefixMu.Unlock()
+}
+
+// nodeNsPrefix returns the namespace prefix of the conversion of the root node
+func nodeNsPrefix(rootNode *Node) (string, bool) {
+	nodeNsPrefixMu.RLock()
+	defer nodeNsPrefixMu.RUnlock()
+
+	prefix, ok := nodeNsPrefixMap[rootNode]
+	return prefix, ok
+}
+
+// deleteNodeNsPrefix drops the namespace prefix of the root node once its conversion is done
+func deleteNodeNsPrefix(rootNode *Node) {
+	nodeNsPrefixMu.Lock()
+	delete(nodeNsPrefixMap, rootNode)
+	nodeNsPrefixMu.Unlock()
+}
+
 func NodeToMap(n *Node, excludeUnset bool) map[string]any {
 	result := make(map[string]any)
 	if excludeUnset {
@@ -108,15 +136,43 @@ func StructToNode[T any](namespacePrefix string) (*Node, error) {
 	}
 
 	rootNode := &Node{}
-	if NodeNsPrefixMap == nil {
-		NodeNsPrefixMap = make(map[*Node]string)
+	setNodeNsPrefix(rootNode, namespacePrefix)
+	defer deleteNodeNsPrefix(rootNode)
+
+	if rt == reflect.TypeOf(InjectionConf{}) {
+		return buildInjectionNode(rootNode)
+	}
+	return buildNode(rt, "", rt.NumField()-1, rootNode)
+}
+
+// buildInjectionNode builds the InjectionConf node from the registered chaos types, keyed by their value
+func buildInjectionNode(rootNode *Node) (*Node, error) {
+	entries := registeredChaosTypes()
+	node := &Node{
+		Name:     reflect.TypeOf(InjectionConf{}).Name(),
+		Range:    []int{0, int(entries[len(entries)-1].id)},
+		Children: make(map[string]*Node, len(entries)),
 	}
 
-	NodeNsPrefixMap[rootNode] = namespacePrefix
-	return buildNode(rt, "", rootNode)
+	for _, entry := range entries {
+		child, err := buildFieldNode(entry.field(), rootNode)
+		if err != nil {
+			return nil, err
+		}
+
+		// Leave out specs without any injection point in the system, e.g. JVM faults on a Go system
+		if hasEmptyRange(child) {
+			continue
+		}
+
+		node.Children[strconv.Itoa(int(entry.id))] = child
+	}
+
+	return node, nil
 }
 
-func buildNode(rt reflect.Type, fieldName string, rootNode *Node) (*Node, error) {
+// buildNode builds the node of a struct from its fields up to maxNum, the end of the struct's range
+func buildNode(rt reflect.Type, fieldName string, maxNum int, rootNode *Node) (*Node, error) {
 	if rt.Kind() == reflect.Ptr {
 		rt = rt.Elem()
 	}
@@ -131,7 +187,8 @@ func buildNode(rt reflect.Type, fieldName string, rootNode *Node) (*Node, error)
 	if rt.Kind() == reflect.Struct {
 		for i := range rt.NumField() {
 			field := rt.Field(i)
-			if field.Name == KeyNamespaceTarget {
+			// Fields beyond the range are left out of the action space, as NodeToStruct ignores them
+			if field.Name == KeyNamespaceTarget || i > maxNum {
 				continue
 			}
 
@@ -140,6 +197,11 @@ func buildNode(rt reflect.Type, fieldName string, rootNode *Node) (*Node, error)
 				return nil, err
 			}
 
+			// Leave out specs without any injection point in the system, e.g. JVM faults on a Go system
+			if hasEmptyRange(child) {
+				continue
+			}
+
 			node.Children[strconv.Itoa(i)] = child
 		}
 	}
@@ -162,7 +224,8 @@ func buildFieldNode(field reflect.StructField, rootNode *Node) (*Node, error) {
 		}
 
 		description = mapToString(namespacePrefixMap)
-		value = namespacePrefixMap[NodeNsPrefixMap[rootNode]]
+		prefix, _ := nodeNsPrefix(rootNode)
+		value = namespacePrefixMap[prefix]
 	}
 
 	child := &Node{
@@ -178,7 +241,7 @@ func buildFieldNode(field reflect.StructField, rootNode *Node) (*Node, error) {
 	}
 
 	if fieldType.Kind() == reflect.Struct {
-		if nested, err := buildNode(fieldType, field.Name, rootNode); err != nil {
+		if nested, err := buildNode(fieldType, field.Name, end, rootNode); err != nil {
 			return nil, err
 		} else {
 			child.Children = nested.Children
@@ -188,6 +251,16 @@ func buildFieldNode(field reflect.StructField, rootNode *Node) (*Node, error) {
 	return child, nil
 }
 
+// hasEmptyRange reports whether one of the node's fields has no valid value
+func hasEmptyRange(n *Node) bool {
+	for _, child := range n.Children {
+		if len(child.Range) == 2 && child.Range[1] < child.Range[0] {
+			return true
+		}
+	}
+	return false
+}
+
 func mapToString(m map[string]int) string {
 	pairs := make([]string, 0, len(m))
 	for key, value := range m {
@@ -209,7 +282,7 @@ func NodeToStruct[T any](n *Node) (*T, error) {
 
 	val := reflect.New(rt).Elem()
 
-	if rt.Name() == "InjectionConf" && rt.PkgPath() == "github.com/LGU-SE-Internal/chaos-experiment/handler" {
+	if rt == reflect.TypeOf(InjectionConf{}) {
 		if len(n.Children) != 1 {
 			childCount := len(n.Children)
 			childKeys := make([]string, 0, childCount)
@@ -219,13 +292,14 @@ func NodeToStruct[T any](n *Node) (*T, error) {
 			return nil, fmt.Errorf("InjectionConf must have exactly one chaos type, got %d children with keys: %v", childCount, childKeys)
 		}
 
-		if NodeNsPrefixMap == nil {
-			NodeNsPrefixMap = make(map[*Node]string)
-		}
+		// The input node may be converted by several callers at once, so the prefix is kept under a
+		// root node of this conversion
+		rootNode := &Node{}
+		defer deleteNodeNsPrefix(rootNode)
 
-		intKey := n.Value
-		if intKey < 0 || intKey >= rt.NumField() {
-			return nil, fmt.Errorf("invalid field index %d for struct %s (valid range: 0-%d)", intKey, rt.Name(), rt.NumField()-1)
+		entry, ok := chaosTypeByID(n.Value)
+		if !ok {
+			return nil, fmt.Errorf("invalid chaos type %d for struct %s (registered: %v)", n.Value, rt.Name(), RegisteredChaosTypes())
 		}
 
 		expectedChildKey := strconv.Itoa(n.Value)
@@ -238,10 +312,11 @@ func NodeToStruct[T any](n *Node) (*T, error) {
 			return nil, fmt.Errorf("expected child key '%s' not found in node children, available keys: %v", expectedChildKey, availableKeys)
 		}
 
-		fieldName := rt.Field(intKey).Name
-		if err := processStructField(rt.Field(intKey), val.Field(intKey), childNode, n); err != nil {
-			return nil, fmt.Errorf("failed to process field '%s' (index %d) in struct %s: %w", fieldName, intKey, rt.Name(), err)
+		spec := reflect.New(reflect.PointerTo(entry.specType)).Elem()
+		if err := processStructField(entry.field(), spec, childNode, rootNode); err != nil {
+			return nil, fmt.Errorf("failed to process field '%s' (index %d) in struct %s: %w", entry.Name, n.Value, rt.Name(), err)
 		}
+		val.Addr().Interface().(*InjectionConf).setSpec(entry, spec)
 	}
 
 	return val.Addr().Interface().(*T), nil
@@ -355,7 +430,7 @@ func assignBasicType(field reflect.StructField, val reflect.Value, node, rootNod
 			return fmt.Errorf("field '%s': namespace index %d exceeds available namespaces count %d",
 				field.Name, node.Value, len(NamespacePrefixs))
 		}
-		NodeNsPrefixMap[rootNode] = NamespacePrefixs[node.Value]
+		setNodeNsPrefix(rootNode, NamespacePrefixs[node.Value])
 	}
 
 	if err := setValue(val, node.Value); err != nil {
@@ -378,7 +453,7 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			start = DefaultStartIndex
 			end = len(NamespacePrefixs) - 1
 		case KeyNamespaceTarget:
-			prefix, ok := NodeNsPrefixMap[rootNode]
+			prefix, ok := nodeNsPrefix(rootNode)
 			if !ok {
 				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyNamespaceTarget)
 			}
@@ -391,7 +466,7 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			start = DefaultStartIndex
 			end = targetCount - 1
 		case KeyApp:
-			prefix, ok := NodeNsPrefixMap[rootNode]
+			prefix, ok := nodeNsPrefix(rootNode)
 			if !ok {
 				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyApp)
 			}
@@ -406,7 +481,12 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			end = len(values) - 1
 		case KeyMethod:
 			// For flattened JVM methods
-			methods, err := resourcelookup.GetAllJVMMethods()
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyMethod)
+			}
+
+			methods, err := resourcelookup.GetAllJVMMethods(prefix)
 			if err != nil {
 				return 0, 0, fmt.Errorf("failed to get JVM methods: %w", err)
 			}
@@ -415,7 +495,12 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			end = len(methods) - 1
 		case KeyEndpoint:
 			// For flattened HTTP endpoints
-			endpoints, err := resourcelookup.GetAllHTTPEndpoints()
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyEndpoint)
+			}
+
+			endpoints, err := resourcelookup.GetAllHTTPEndpoints(prefix)
 			if err != nil {
 				return 0, 0, fmt.Errorf("failed to get HTTP endpoints: %w", err)
 			}
@@ -424,7 +509,12 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			end = len(endpoints) - 1
 		case KeyNetworkPair:
 			// For flattened network pairs
-			pairs, err := resourcelookup.GetAllNetworkPairs()
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyNetworkPair)
+			}
+
+			pairs, err := resourcelookup.GetAllNetworkPairs(prefix)
 			if err != nil {
 				return 0, 0, fmt.Errorf("failed to get network pairs: %w", err)
 			}
@@ -433,7 +523,7 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			end = len(pairs) - 1
 		case KeyContainer:
 			// For flattened containers
-			prefix, ok := NodeNsPrefixMap[rootNode]
+			prefix, ok := nodeNsPrefix(rootNode)
 			if !ok {
 				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyContainer)
 			}
@@ -448,7 +538,12 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			end = len(containers) - 1
 		case KeyDNSEndpoint:
 			// For flattened DNS endpoints
-			endpoints, err := resourcelookup.GetAllDNSEndpoints()
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyDNSEndpoint)
+			}
+
+			endpoints, err := resourcelookup.GetAllDNSEndpoints(prefix)
 			if err != nil {
 				return 0, 0, fmt.Errorf("failed to get DNS endpoints: %w", err)
 			}
@@ -457,13 +552,63 @@ func getValueRange(field reflect.StructField, rootNode *Node) (int, int, error)
 			end = len(endpoints) - 1
 		case KeyDatabase:
 			// For flattened database operations
-			dbOps, err := resourcelookup.GetAllDatabaseOperations()
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyDatabase)
+			}
+
+			dbOps, err := resourcelookup.GetAllDatabaseOperations(prefix)
 			if err != nil {
 				return 0, 0, fmt.Errorf("failed to get database operations: %w", err)
 			}
 
 			start = DefaultStartIndex
 			end = len(dbOps) - 1
+		case KeyVolume:
+			// For flattened container volumes
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyVolume)
+			}
+
+			namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+			volumes, err := resourcelookup.GetAllVolumes(namespace)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to get volumes: %w", err)
+			}
+
+			start = DefaultStartIndex
+			end = len(volumes) - 1
+		case KeyBlockVolume:
+			// For flattened container volumes backed by a persistent volume claim
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyBlockVolume)
+			}
+
+			namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+			volumes, err := resourcelookup.GetAllBlockVolumes(namespace)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to get block volumes: %w", err)
+			}
+
+			start = DefaultStartIndex
+			end = len(volumes) - 1
+		case KeyMachine:
+			// For the app labels of the physical machines
+			prefix, ok := nodeNsPrefix(rootNode)
+			if !ok {
+				return 0, 0, fmt.Errorf("failed to get namespace prefix in %s", KeyMachine)
+			}
+
+			namespace := fmt.Sprintf("%s%d", prefix, DefaultStartIndex)
+			machines, err := resourcelookup.GetAllMachineLabels(namespace)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to get physical machines: %w", err)
+			}
+
+			start = DefaultStartIndex
+			end = len(machines) - 1
 		}
 	}
 
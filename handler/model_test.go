@@ -4,10 +4,15 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
 	"github.com/k0kubun/pp/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNodeToMap(t *testing.T) {
@@ -133,3 +138,121 @@ func TestGenerateRandomAction(t *testing.T) {
 
 	fmt.Println("Correct?", reflect.DeepEqual(podNode, mappedNode))
 }
+
+func TestStructToNodeScopesToPrefix(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend-0", Namespace: "gosys0", Labels: map[string]string{"app": "frontend"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:         "frontend",
+			VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+		}}},
+	}
+	useTestSystem(t, "gosys", 1, newTestClient(t, pod))
+
+	// A Go system: HTTP endpoints but no JVM methods or database operations
+	resourcelookup.RegisterCatalog("gosys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cartservice", ServerPort: "7070"},
+			{AppName: "frontend", Method: "GET", Route: "/product", ServerAddress: "productcatalogservice", ServerPort: "3550"},
+		},
+	})
+
+	node, err := StructToNode[InjectionConf]("gosys")
+	if err != nil {
+		t.Fatalf("StructToNode() error = %v", err)
+	}
+
+	confType := reflect.TypeOf(InjectionConf{})
+	childOf := func(name string) *Node {
+		field, ok := confType.FieldByName(name)
+		if !ok {
+			t.Fatalf("InjectionConf has no field %s", name)
+		}
+		return node.Children[fmt.Sprint(field.Index[0])]
+	}
+
+	for _, name := range []string{"JVMLatency", "JVMMySQLLatency"} {
+		if childOf(name) != nil {
+			t.Errorf("%s should be excluded for a system without JVM methods", name)
+		}
+	}
+
+	abort := childOf("HTTPRequestAbort")
+	if abort == nil {
+		t.Fatalf("HTTPRequestAbort should be available")
+	}
+
+	field, _ := reflect.TypeOf(HTTPRequestAbortSpec{}).FieldByName(KeyEndpoint)
+	if got := abort.Children[fmt.Sprint(field.Index[0])].Range; !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("%s range = %v, want [0 1]", KeyEndpoint, got)
+	}
+
+	if childOf("PodKill") == nil || childOf("IODelay") == nil {
+		t.Errorf("cluster based specs should be available")
+	}
+}
+
+func TestStructToNodeConcurrentPrefixes(t *testing.T) {
+	useTestSystem(t, "raceone", 1, newTestClient(t, testPod("raceone0", "frontend")))
+	NamespacePrefixs = []string{"raceone", "racetwo"}
+	NamespaceTargetMap = map[string]int{"raceone": 1, "racetwo": 1}
+	client.RegisterProvider("racetwo", client.NewStaticProvider(newTestClient(t, testPod("racetwo0", "frontend"))))
+	t.Cleanup(func() {
+		client.UnregisterProvider("racetwo")
+		resourcelookup.UnregisterCatalog("racetwo")
+	})
+
+	// The systems differ in their number of HTTP endpoints, so a prefix read from the other
+	// conversion shows in the endpoint range
+	endpoints := map[string]int{"raceone": 1, "racetwo": 3}
+	for prefix, count := range endpoints {
+		catalog := &resourcelookup.StaticCatalog{}
+		for i := range count {
+			catalog.EndpointList = append(catalog.EndpointList, resourcelookup.AppEndpointPair{
+				AppName: "frontend", Method: "GET", Route: fmt.Sprintf("/route%d", i), ServerAddress: "cart", ServerPort: "8080",
+			})
+		}
+		resourcelookup.RegisterCatalog(prefix, catalog)
+	}
+
+	abortField, _ := reflect.TypeOf(InjectionConf{}).FieldByName("HTTPRequestAbort")
+	endpointField, _ := reflect.TypeOf(HTTPRequestAbortSpec{}).FieldByName(KeyEndpoint)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		nsIdx := i % 2
+		prefix := NamespacePrefixs[nsIdx]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			node, err := StructToNode[InjectionConf](prefix)
+			if err != nil {
+				t.Errorf("StructToNode(%s) error = %v", prefix, err)
+				return
+			}
+			want := []int{0, endpoints[prefix] - 1}
+			if got := node.Children[fmt.Sprint(abortField.Index[0])].Children[fmt.Sprint(endpointField.Index[0])].Range; !reflect.DeepEqual(got, want) {
+				t.Errorf("StructToNode(%s) endpoint range = %v, want %v", prefix, got, want)
+			}
+
+			action, err := InjectionConfToNode(&InjectionConf{HTTPRequestAbort: &HTTPRequestAbortSpec{
+				Duration: 1, Namespace: nsIdx, EndpointIdx: endpoints[prefix] - 1,
+			}})
+			if err != nil {
+				t.Errorf("InjectionConfToNode() error = %v", err)
+				return
+			}
+			if _, err := NodeToStruct[InjectionConf](action); err != nil {
+				t.Errorf("NodeToStruct(%s) error = %v", prefix, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	nodeNsPrefixMu.RLock()
+	defer nodeNsPrefixMu.RUnlock()
+	if len(nodeNsPrefixMap) != 0 {
+		t.Errorf("%d namespace prefixes left after the conversions", len(nodeNsPrefixMap))
+	}
+}
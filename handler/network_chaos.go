@@ -19,6 +19,20 @@ var directionMap = map[int]chaosmeshv1alpha1.Direction{
 	3: chaosmeshv1alpha1.Both,
 }
 
+// DirectionAlongCall is the direction code of NetworkPartitionSpec and NetworkDelaySpec that
+// affects the requests of the call between the pair, from the caller to the callee, whichever
+// order the pair lists them in. Their Direction range was 1-3 before this code was added, so their
+// action space, and the actions sampled or enumerated from it, now include it for every pair.
+const DirectionAlongCall = 4
+
+// directionName returns the name of a direction code for display
+func directionName(directionCode int) string {
+	if directionCode == DirectionAlongCall {
+		return "call"
+	}
+	return string(getDirection(directionCode))
+}
+
 // Convert int direction code to chaos-mesh Direction
 func getDirection(directionCode int) chaosmeshv1alpha1.Direction {
 	if direction, ok := directionMap[directionCode]; ok {
@@ -28,8 +42,8 @@ func getDirection(directionCode int) chaosmeshv1alpha1.Direction {
 }
 
 // Helper function to validate and get network pair from index
-func getNetworkPairByIndex(networkPairIdx int) (*resourcelookup.AppNetworkPair, error) {
-	networkPairs, err := resourcelookup.GetAllNetworkPairs()
+func getNetworkPairByIndex(namespace string, networkPairIdx int) (*resourcelookup.AppNetworkPair, error) {
+	networkPairs, err := resourcelookup.GetAllNetworkPairs(namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network pairs: %w", err)
 	}
@@ -42,13 +56,38 @@ func getNetworkPairByIndex(networkPairIdx int) (*resourcelookup.AppNetworkPair,
 	return &networkPairs[networkPairIdx], nil
 }
 
+// networkPairTarget returns the source and target of the network chaos and its direction. With
+// DirectionAlongCall the source is the caller of the pair and the direction is To, so only the
+// requests are affected.
+func networkPairTarget(namespace string, pair *resourcelookup.AppNetworkPair, directionCode int) (string, string, chaosmeshv1alpha1.Direction, error) {
+	if directionCode != DirectionAlongCall {
+		return pair.SourceService, pair.TargetService, getDirection(directionCode), nil
+	}
+
+	graph, err := resourcelookup.GetServiceGraph(namespace)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get service graph: %w", err)
+	}
+
+	if _, ok := graph.Edge(pair.SourceService, pair.TargetService); ok {
+		return pair.SourceService, pair.TargetService, chaosmeshv1alpha1.To, nil
+	}
+	if _, ok := graph.Edge(pair.TargetService, pair.SourceService); ok {
+		return pair.TargetService, pair.SourceService, chaosmeshv1alpha1.To, nil
+	}
+
+	return "", "", "", fmt.Errorf("no call between %s and %s", pair.SourceService, pair.TargetService)
+}
+
 // NetworkPartitionSpec defines network partition chaos parameters
 type NetworkPartitionSpec struct {
 	Duration        int `range:"1-60" description:"Time Unit Minute"`
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	NetworkPairIdx  int `range:"0-0" dynamic:"true" description:"Flattened network pair index"`
-	Direction       int `range:"1-3" description:"Direction (1=to, 2=from, 3=both)"`
+	Direction       int `range:"1-4" description:"Direction (1=to, 2=from, 3=both, 4=along the call from caller to callee)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *NetworkPartitionSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -77,22 +116,29 @@ func (s *NetworkPartitionSpec) Create(cli cli.Client, opts ...Option) (string, e
 		ns = conf.Namespace
 	}
 
-	pair, err := getNetworkPairByIndex(s.NetworkPairIdx)
+	pair, err := getNetworkPairByIndex(ns, s.NetworkPairIdx)
 	if err != nil {
 		return "", err
 	}
 
-	sourceName := pair.SourceService
-	targetName := pair.TargetService
+	sourceName, targetName, direction, err := networkPairTarget(ns, pair, s.Direction)
+	if err != nil {
+		return "", err
+	}
 
 	duration := pointer.String(fmt.Sprintf("%dm", s.Duration))
-	direction := getDirection(s.Direction)
 
 	// Create network partition between the source and target services
 	optss := []chaos.OptNetworkChaos{
 		chaos.WithNetworkTargetAndDirection(ns, targetName, direction),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(sourceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithNetworkPodSelector(selector...))
+
 	return controllers.CreateNetworkChaos(cli, ctx, ns, sourceName,
 		chaosmeshv1alpha1.PartitionAction, duration, annotations, labels, optss...)
 }
@@ -105,8 +151,10 @@ type NetworkDelaySpec struct {
 	Latency         int `range:"1-2000" description:"Latency in milliseconds"`
 	Correlation     int `range:"0-100" description:"Correlation percentage"`
 	Jitter          int `range:"0-1000" description:"Jitter in milliseconds"`
-	Direction       int `range:"1-3" description:"Direction (1=to, 2=from, 3=both)"`
+	Direction       int `range:"1-4" description:"Direction (1=to, 2=from, 3=both, 4=along the call from caller to callee)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *NetworkDelaySpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -135,20 +183,21 @@ func (s *NetworkDelaySpec) Create(cli cli.Client, opts ...Option) (string, error
 		ns = conf.Namespace
 	}
 
-	pair, err := getNetworkPairByIndex(s.NetworkPairIdx)
+	pair, err := getNetworkPairByIndex(ns, s.NetworkPairIdx)
 	if err != nil {
 		return "", err
 	}
 
-	sourceName := pair.SourceService
-	targetName := pair.TargetService
+	sourceName, targetName, direction, err := networkPairTarget(ns, pair, s.Direction)
+	if err != nil {
+		return "", err
+	}
 
 	// Convert int values to appropriate string format
 	latency := fmt.Sprintf("%dms", s.Latency)
 	correlation := fmt.Sprintf("%d", s.Correlation)
 	jitter := fmt.Sprintf("%dms", s.Jitter)
 	duration := pointer.String(fmt.Sprintf("%dm", s.Duration))
-	direction := getDirection(s.Direction)
 
 	// Create network delay between the source and target services
 	optss := []chaos.OptNetworkChaos{
@@ -156,6 +205,12 @@ func (s *NetworkDelaySpec) Create(cli cli.Client, opts ...Option) (string, error
 		chaos.WithNetworkDelay(latency, correlation, jitter),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(sourceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithNetworkPodSelector(selector...))
+
 	return controllers.CreateNetworkChaos(cli, ctx, ns, sourceName,
 		chaosmeshv1alpha1.DelayAction, duration, annotations, labels, optss...)
 }
@@ -169,6 +224,8 @@ type NetworkLossSpec struct {
 	Correlation     int `range:"0-100" description:"Correlation percentage"`
 	Direction       int `range:"1-3" description:"Direction (1=to, 2=from, 3=both)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *NetworkLossSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -197,7 +254,7 @@ func (s *NetworkLossSpec) Create(cli cli.Client, opts ...Option) (string, error)
 		ns = conf.Namespace
 	}
 
-	pair, err := getNetworkPairByIndex(s.NetworkPairIdx)
+	pair, err := getNetworkPairByIndex(ns, s.NetworkPairIdx)
 	if err != nil {
 		return "", err
 	}
@@ -217,6 +274,12 @@ func (s *NetworkLossSpec) Create(cli cli.Client, opts ...Option) (string, error)
 		chaos.WithNetworkLoss(loss, correlation),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(sourceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithNetworkPodSelector(selector...))
+
 	return controllers.CreateNetworkChaos(cli, ctx, ns, sourceName,
 		chaosmeshv1alpha1.LossAction, duration, annotations, labels, optss...)
 }
@@ -230,6 +293,8 @@ type NetworkDuplicateSpec struct {
 	Correlation     int `range:"0-100" description:"Correlation percentage"`
 	Direction       int `range:"1-3" description:"Direction (1=to, 2=from, 3=both)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *NetworkDuplicateSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -258,7 +323,7 @@ func (s *NetworkDuplicateSpec) Create(cli cli.Client, opts ...Option) (string, e
 		ns = conf.Namespace
 	}
 
-	pair, err := getNetworkPairByIndex(s.NetworkPairIdx)
+	pair, err := getNetworkPairByIndex(ns, s.NetworkPairIdx)
 	if err != nil {
 		return "", err
 	}
@@ -278,6 +343,12 @@ func (s *NetworkDuplicateSpec) Create(cli cli.Client, opts ...Option) (string, e
 		chaos.WithNetworkDuplicate(duplicate, correlation),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(sourceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithNetworkPodSelector(selector...))
+
 	return controllers.CreateNetworkChaos(cli, ctx, ns, sourceName,
 		chaosmeshv1alpha1.DuplicateAction, duration, annotations, labels, optss...)
 }
@@ -291,6 +362,8 @@ type NetworkCorruptSpec struct {
 	Correlation     int `range:"0-100" description:"Correlation percentage"`
 	Direction       int `range:"1-3" description:"Direction (1=to, 2=from, 3=both)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *NetworkCorruptSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -319,7 +392,7 @@ func (s *NetworkCorruptSpec) Create(cli cli.Client, opts ...Option) (string, err
 		ns = conf.Namespace
 	}
 
-	pair, err := getNetworkPairByIndex(s.NetworkPairIdx)
+	pair, err := getNetworkPairByIndex(ns, s.NetworkPairIdx)
 	if err != nil {
 		return "", err
 	}
@@ -339,6 +412,12 @@ func (s *NetworkCorruptSpec) Create(cli cli.Client, opts ...Option) (string, err
 		chaos.WithNetworkCorrupt(corrupt, correlation),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(sourceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithNetworkPodSelector(selector...))
+
 	return controllers.CreateNetworkChaos(cli, ctx, ns, sourceName,
 		chaosmeshv1alpha1.CorruptAction, duration, annotations, labels, optss...)
 }
@@ -353,6 +432,8 @@ type NetworkBandwidthSpec struct {
 	Buffer          int `range:"1-10000" description:"Maximum amount of bytes available instantaneously"`
 	Direction       int `range:"1-3" description:"Direction (1=to, 2=from, 3=both)"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *NetworkBandwidthSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -381,7 +462,7 @@ func (s *NetworkBandwidthSpec) Create(cli cli.Client, opts ...Option) (string, e
 		ns = conf.Namespace
 	}
 
-	pair, err := getNetworkPairByIndex(s.NetworkPairIdx)
+	pair, err := getNetworkPairByIndex(ns, s.NetworkPairIdx)
 	if err != nil {
 		return "", err
 	}
@@ -402,6 +483,12 @@ func (s *NetworkBandwidthSpec) Create(cli cli.Client, opts ...Option) (string, e
 		chaos.WithNetworkBandwidth(rate, limit, buffer),
 	}
 
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(sourceName)
+	if err != nil {
+		return "", err
+	}
+	optss = append(optss, chaos.WithNetworkPodSelector(selector...))
+
 	return controllers.CreateNetworkChaos(cli, ctx, ns, sourceName,
 		chaosmeshv1alpha1.BandwidthAction, duration, annotations, labels, optss...)
 }
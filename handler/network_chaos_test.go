@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestNetworkChaosAlongCall(t *testing.T) {
+	fakeClient := newTestClient(t, testPod("callsys0", "frontend"), testPod("callsys0", "cart"))
+	useTestSystem(t, "callsys", 1, fakeClient)
+	resourcelookup.RegisterCatalog("callsys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cart", ServerPort: "8080"},
+		},
+		NetworkPairList: []resourcelookup.AppNetworkPair{
+			{SourceService: "cart", TargetService: "frontend"},
+			{SourceService: "cart", TargetService: "ads"},
+		},
+	})
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		spec       Injection
+		wantSource string
+		wantTarget string
+		wantErr    bool
+	}{
+		{
+			name:       "delay along the call",
+			spec:       &NetworkDelaySpec{Duration: 1, NetworkPairIdx: 1, Latency: 100, Direction: DirectionAlongCall},
+			wantSource: "frontend",
+			wantTarget: "cart",
+		},
+		{
+			name:       "partition along the call",
+			spec:       &NetworkPartitionSpec{Duration: 1, NetworkPairIdx: 1, Direction: DirectionAlongCall},
+			wantSource: "frontend",
+			wantTarget: "cart",
+		},
+		{
+			name:    "no call between the pair",
+			spec:    &NetworkPartitionSpec{Duration: 1, NetworkPairIdx: 0, Direction: DirectionAlongCall},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := tt.spec.Create(fakeClient, WithContext(ctx), WithNs("callsys0"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			networkChaos := &chaosmeshv1alpha1.NetworkChaos{}
+			if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "callsys0", Name: name}, networkChaos); err != nil {
+				t.Fatal(err)
+			}
+			spec := networkChaos.Spec
+			if got := spec.Selector.LabelSelectors["app"]; got != tt.wantSource {
+				t.Errorf("source = %s, want %s", got, tt.wantSource)
+			}
+			if got := spec.Target.Selector.LabelSelectors["app"]; got != tt.wantTarget {
+				t.Errorf("target = %s, want %s", got, tt.wantTarget)
+			}
+			if spec.Direction != chaosmeshv1alpha1.To {
+				t.Errorf("direction = %s, want to", spec.Direction)
+			}
+
+			gt, err := tt.spec.(GroundtruthProvider).GetGroundtruth()
+			if err != nil {
+				t.Fatalf("GetGroundtruth() error = %v", err)
+			}
+			if want := []string{tt.wantSource, tt.wantTarget}; !reflect.DeepEqual(gt.Service, want) {
+				t.Errorf("groundtruth services = %v, want %v", gt.Service, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	chaos "github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	controllers "github.com/LGU-SE-Internal/chaos-experiment/controllers"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	"k8s.io/utils/pointer"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Physical machine faults target the PhysicalMachine objects of the namespace that carry the app
+// label of the service they host, MachineIdx ranges over the app labels found on those objects
+
+// Helper function to validate and get the app label of a physical machine from index
+func getMachineByIndex(namespace string, machineIdx int) (string, error) {
+	machines, err := resourcelookup.GetAllMachineLabels(namespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to get physical machines: %w", err)
+	}
+
+	if machineIdx < 0 || machineIdx >= len(machines) {
+		return "", fmt.Errorf("machine index out of range: %d (max: %d)", machineIdx, len(machines)-1)
+	}
+
+	return machines[machineIdx], nil
+}
+
+type PhysicalMachineCPUStressSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	MachineIdx      int `range:"0-0" dynamic:"true" description:"Physical Machine App Index"`
+	Load            int `range:"1-100" description:"CPU load percentage per worker"`
+	Workers         int `range:"1-8" description:"CPU Stress Threads"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+}
+
+func (s *PhysicalMachineCPUStressSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	appName, err := getMachineByIndex(ns, s.MachineIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+	return controllers.CreatePhysicalMachineChaos(cli, ctx, ns, appName, duration, annotations, labels,
+		chaos.WithPMStressCPUAction(s.Load, s.Workers))
+}
+
+type PhysicalMachineMemoryStressSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	MachineIdx      int `range:"0-0" dynamic:"true" description:"Physical Machine App Index"`
+	MemorySize      int `range:"1-4096" description:"Memory Size Unit MB"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+}
+
+func (s *PhysicalMachineMemoryStressSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	appName, err := getMachineByIndex(ns, s.MachineIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+	return controllers.CreatePhysicalMachineChaos(cli, ctx, ns, appName, duration, annotations, labels,
+		chaos.WithPMStressMemoryAction(fmt.Sprintf("%dMB", s.MemorySize)))
+}
+
+type PhysicalMachineDiskFillSpec struct {
+	Duration        int `range:"1-60" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	MachineIdx      int `range:"0-0" dynamic:"true" description:"Physical Machine App Index"`
+	Size            int `range:"1-100" description:"Disk fill size Unit GB"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+}
+
+func (s *PhysicalMachineDiskFillSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	conf := Conf{}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	annotations := make(map[string]string)
+	if conf.Annoations != nil {
+		annotations = conf.Annoations
+	}
+
+	ctx := context.Background()
+	if conf.Context != nil {
+		ctx = conf.Context
+	}
+
+	labels := make(map[string]string)
+	if conf.Labels != nil {
+		labels = conf.Labels
+	}
+
+	ns := GetTargetNamespace(s.Namespace, s.NamespaceTarget)
+	if conf.Namespace != "" {
+		ns = conf.Namespace
+	}
+
+	appName, err := getMachineByIndex(ns, s.MachineIdx)
+	if err != nil {
+		return "", err
+	}
+
+	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
+	return controllers.CreatePhysicalMachineChaos(cli, ctx, ns, appName, duration, annotations, labels,
+		chaos.WithPMDiskFillAction(fmt.Sprintf("%dGB", s.Size), "", true))
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderNodeLevelChaos(t *testing.T) {
+	machine := &chaosmeshv1alpha1.PhysicalMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-host", Namespace: "nodesys0", Labels: map[string]string{"app": "mysql-host"}},
+		Spec:       chaosmeshv1alpha1.PhysicalMachineSpec{Address: "http://10.0.0.2:31767"},
+	}
+	fakeClient := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nodesys0"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql-0", Namespace: "nodesys0", Labels: map[string]string{"app": "mysql"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "mysql",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "config", MountPath: "/etc/mysql"},
+						{Name: "data", MountPath: "/var/lib/mysql"},
+					},
+				}},
+				Volumes: []corev1.Volume{
+					{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+					{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "mysql-data"}}},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		machine,
+	)
+	useTestSystem(t, "nodesys", 1, fakeClient)
+
+	// Only the claim-backed volume is a block volume, and machines are indexed by their own labels
+	blockVolumes, err := resourcelookup.GetAllBlockVolumes("nodesys0")
+	if err != nil || len(blockVolumes) != 1 || blockVolumes[0].ClaimName != "mysql-data" {
+		t.Fatalf("GetAllBlockVolumes() = %+v, %v, want only the mysql-data claim", blockVolumes, err)
+	}
+	machines, err := resourcelookup.GetAllMachineLabels("nodesys0")
+	if err != nil || !reflect.DeepEqual(machines, []string{"mysql-host"}) {
+		t.Fatalf("GetAllMachineLabels() = %v, %v, want [mysql-host]", machines, err)
+	}
+
+	tests := []struct {
+		name string
+		conf *InjectionConf
+		want []string
+	}{
+		{
+			name: "kernel",
+			conf: &InjectionConf{KernelFault: &KernelFaultSpec{Duration: 1, FailType: 2, Probability: 50}},
+			want: []string{"kind: KernelChaos", "failtype: 2", "probability: 50", "- mysql"},
+		},
+		{
+			name: "block",
+			conf: &InjectionConf{BlockDelay: &BlockDelaySpec{Duration: 1, Latency: 100, Jitter: 10}},
+			want: []string{"kind: BlockChaos", "volumeName: data", "latency: 100ms", "jitter: 10ms"},
+		},
+		{
+			name: "physical machine",
+			conf: &InjectionConf{PhysicalMachineCPUStress: &PhysicalMachineCPUStressSpec{Duration: 1, Load: 80, Workers: 2}},
+			want: []string{"kind: PhysicalMachineChaos", "action: stress-cpu", "load: 80", "app: mysql-host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := tt.conf.Render(context.Background(), 0, nil, nil, client.FormatYAML)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(manifest), want) {
+					t.Errorf("manifest misses %q:\n%s", want, manifest)
+				}
+			}
+		})
+	}
+
+	gt, err := (&BlockDelaySpec{Duration: 1}).GetGroundtruth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gt.Metric, []string{string(MetricDisk)}) {
+		t.Errorf("block delay groundtruth metrics = %v, want the disk metric once", gt.Metric)
+	}
+	gt, err = (&PhysicalMachineDiskFillSpec{Duration: 1}).GetGroundtruth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gt.Service, []string{"mysql-host"}) {
+		t.Errorf("physical machine groundtruth services = %v, want [mysql-host]", gt.Service)
+	}
+
+	ic := &InjectionConf{PhysicalMachineMemoryStress: &PhysicalMachineMemoryStressSpec{Duration: 1, MemorySize: 256}}
+	if err := ic.Preflight(context.Background(), nil, 0); err != nil {
+		t.Errorf("Preflight() with a registered machine error = %v", err)
+	}
+
+	// The catalog still lists the machine after it is deregistered, the live check catches it
+	if err := fakeClient.Delete(context.Background(), machine); err != nil {
+		t.Fatal(err)
+	}
+	var errs PreflightErrors
+	if err := ic.Preflight(context.Background(), nil, 0); !errors.As(err, &errs) || errs[0].Check != CheckMachine {
+		t.Errorf("Preflight() error = %v, want a machine failure", err)
+	}
+}
@@ -17,6 +17,8 @@ type PodFailureSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	AppIdx          int `range:"0-0" dynamic:"true" description:"App Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *PodFailureSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -58,7 +60,12 @@ func (s *PodFailureSpec) Create(cli cli.Client, opts ...Option) (string, error)
 	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
 	action := chaosmeshv1alpha1.PodFailureAction
 
-	return controllers.CreatePodChaos(cli, ctx, ns, appName, action, duration, annotations, labels)
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreatePodChaos(cli, ctx, ns, appName, action, duration, annotations, labels, selector...)
 }
 
 // Update PodKillSpec to use flattened app index
@@ -67,6 +74,8 @@ type PodKillSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	AppIdx          int `range:"0-0" dynamic:"true" description:"App Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *PodKillSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -108,7 +117,12 @@ func (s *PodKillSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 	duration := pointer.String(strconv.Itoa(s.Duration) + "m")
 	action := chaosmeshv1alpha1.PodKillAction
 
-	return controllers.CreatePodChaos(cli, ctx, ns, appName, action, duration, annotations, labels)
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreatePodChaos(cli, ctx, ns, appName, action, duration, annotations, labels, selector...)
 }
 
 type ContainerKillSpec struct {
@@ -116,6 +130,8 @@ type ContainerKillSpec struct {
 	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
 	ContainerIdx    int `range:"0-0" dynamic:"true" description:"Container Index"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *ContainerKillSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -161,5 +177,10 @@ func (s *ContainerKillSpec) Create(cli cli.Client, opts ...Option) (string, erro
 	action := chaosmeshv1alpha1.ContainerKillAction
 
 	// Use the updated CreatePodChaosWithContainer function
-	return controllers.CreatePodChaosWithContainer(cli, ctx, ns, appName, action, duration, annotations, labels, []string{containerName})
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreatePodChaosWithContainer(cli, ctx, ns, appName, action, duration, annotations, labels, []string{containerName}, selector...)
 }
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/chaos"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+)
+
+// PodMode picks which pods of the target app a chaos is injected into, one of the Chaos Mesh
+// selector modes. The pod specs carry it in their optional Mode and ModeValue fields, which are part
+// of the action space, and pass it on to the chaos selector with chaos.WithSelectorMode.
+//
+// Chaos Mesh picks the pods when the chaos starts, so before that the groundtruth of a partial-pod
+// injection lists every pod of the app it picks from. Groundtruth.WithAffectedPods narrows it to the
+// pods Chaos Mesh reported, e.g. those of the InjectionResult of WaitForInjection.
+type PodMode int
+
+const (
+	AllPods PodMode = iota
+	// OnePod injects a single random pod
+	OnePod
+	// FixedPods injects ModeValue random pods
+	FixedPods
+	// FixedPercentPods injects ModeValue percent of the pods, chosen at random
+	FixedPercentPods
+	// RandomMaxPercentPods injects a random share of at most ModeValue percent of the pods
+	RandomMaxPercentPods
+)
+
+// podModeNames maps the pod modes to their Chaos Mesh selector modes
+var podModeNames = map[PodMode]chaosmeshv1alpha1.SelectorMode{
+	AllPods:              chaosmeshv1alpha1.AllMode,
+	OnePod:               chaosmeshv1alpha1.OneMode,
+	FixedPods:            chaosmeshv1alpha1.FixedMode,
+	FixedPercentPods:     chaosmeshv1alpha1.FixedPercentMode,
+	RandomMaxPercentPods: chaosmeshv1alpha1.RandomMaxPercentMode,
+}
+
+func (m PodMode) String() string {
+	if name, ok := podModeNames[m]; ok {
+		return string(name)
+	}
+	return "PodMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// podSelection is the pod mode of a spec
+type podSelection struct {
+	mode  PodMode
+	value int
+}
+
+// validate checks that the mode has the value it needs, so that an invalid mode fails before the
+// chaos is created rather than in Chaos Mesh
+func (p podSelection) validate() error {
+	switch p.mode {
+	case AllPods, OnePod:
+		return nil
+	case FixedPods:
+		if p.value < 1 {
+			return fmt.Errorf("pod mode %s needs a pod count of at least 1, got %d", p.mode, p.value)
+		}
+	case FixedPercentPods, RandomMaxPercentPods:
+		if p.value < 1 || p.value > 100 {
+			return fmt.Errorf("pod mode %s needs a percentage in [1, 100], got %d", p.mode, p.value)
+		}
+	default:
+		return fmt.Errorf("unknown pod mode %d", p.mode)
+	}
+	return nil
+}
+
+// selector returns the options setting the mode on the chaos selector, none for AllPods
+func (p podSelection) selector(app string) ([]chaos.OptPodSelector, error) {
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("pods of %s: %w", app, err)
+	}
+
+	switch p.mode {
+	case AllPods:
+		return nil, nil
+	case OnePod:
+		return []chaos.OptPodSelector{chaos.WithSelectorMode(podModeNames[p.mode], "")}, nil
+	default:
+		return []chaos.OptPodSelector{chaos.WithSelectorMode(podModeNames[p.mode], strconv.Itoa(p.value))}, nil
+	}
+}
+
+// groundtruth checks the mode of the groundtruth helper's results, whose pods are every pod the mode
+// picks from. It takes the results of the helper so that it can wrap its call.
+func (p podSelection) groundtruth(gt Groundtruth, err error) (Groundtruth, error) {
+	if err != nil {
+		return gt, err
+	}
+	if err := p.validate(); err != nil {
+		return Groundtruth{}, err
+	}
+	return gt, nil
+}
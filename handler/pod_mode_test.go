@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPodSelectionSelector(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      PodMode
+		value     int
+		wantMode  chaosmeshv1alpha1.SelectorMode
+		wantValue string
+		wantErr   string
+	}{
+		{name: "all", mode: AllPods, value: 30},
+		{name: "one", mode: OnePod, wantMode: chaosmeshv1alpha1.OneMode},
+		{name: "fixed", mode: FixedPods, value: 2, wantMode: chaosmeshv1alpha1.FixedMode, wantValue: "2"},
+		{name: "fixed percent", mode: FixedPercentPods, value: 50, wantMode: chaosmeshv1alpha1.FixedPercentMode, wantValue: "50"},
+		{name: "random max percent", mode: RandomMaxPercentPods, value: 80, wantMode: chaosmeshv1alpha1.RandomMaxPercentMode, wantValue: "80"},
+		{name: "fixed without a count", mode: FixedPods, wantErr: "at least 1"},
+		{name: "percent out of range", mode: FixedPercentPods, value: 120, wantErr: "[1, 100]"},
+		{name: "unknown mode", mode: PodMode(7), value: 1, wantErr: "unknown pod mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := podSelection{tt.mode, tt.value}.selector("cart")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("selector() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				if _, err := (podSelection{tt.mode, tt.value}).groundtruth(Groundtruth{Service: []string{"cart"}}, nil); err == nil {
+					t.Errorf("groundtruth() accepted the invalid mode")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selector() error = %v", err)
+			}
+
+			selector := &chaosmeshv1alpha1.PodSelector{}
+			for _, opt := range opts {
+				opt(selector)
+			}
+			if selector.Mode != tt.wantMode || selector.Value != tt.wantValue {
+				t.Errorf("selector() sets %q %q, want %q %q", selector.Mode, selector.Value, tt.wantMode, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestPodModeCreate(t *testing.T) {
+	pod := func(namespace, name string) *corev1.Pod {
+		pod := testPod(namespace, "cart")
+		pod.Name = name
+		return pod
+	}
+	fakeClient := newTestClient(t,
+		pod("podmodesys0", "cart-2"), pod("podmodesys0", "cart-0"), pod("podmodesys0", "cart-1"), testPod("podmodesys0", "frontend"),
+		pod("podmodesys1", "cart-b"), pod("podmodesys1", "cart-a"), testPod("podmodesys1", "frontend"),
+	)
+	useTestSystem(t, "podmodesys", 2, fakeClient)
+	ctx := context.Background()
+
+	spec := &PodFailureSpec{Duration: 1, AppIdx: 0, Mode: int(FixedPods), ModeValue: 2}
+	name, err := spec.Create(fakeClient, WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	podChaos := &chaosmeshv1alpha1.PodChaos{}
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "podmodesys0", Name: name}, podChaos); err != nil {
+		t.Fatal(err)
+	}
+	if selector := podChaos.Spec.ContainerSelector.PodSelector; selector.Mode != chaosmeshv1alpha1.FixedMode || selector.Value != "2" {
+		t.Errorf("selector mode = %q %q, want fixed 2", selector.Mode, selector.Value)
+	}
+
+	// Chaos Mesh picks the pods, until it reports them the groundtruth lists every pod of the app
+	gt, err := spec.GetGroundtruth()
+	if err != nil {
+		t.Fatalf("GetGroundtruth() error = %v", err)
+	}
+	if want := []string{"cart-0", "cart-1", "cart-2"}; !reflect.DeepEqual(gt.Pod, want) {
+		t.Errorf("groundtruth pods = %v, want %v", gt.Pod, want)
+	}
+	if got := gt.WithAffectedPods([]string{"cart-2", "cart-0"}).Pod; !reflect.DeepEqual(got, []string{"cart-0", "cart-2"}) {
+		t.Errorf("WithAffectedPods() pods = %v, want the affected ones", got)
+	}
+
+	// The candidate pods are those of the target namespace
+	spec = &PodFailureSpec{Duration: 1, AppIdx: 0, NamespaceTarget: 1, Mode: int(OnePod)}
+	if gt, err = spec.GetGroundtruth(); err != nil {
+		t.Fatalf("GetGroundtruth() in the second target error = %v", err)
+	}
+	if want := []string{"cart-a", "cart-b"}; !reflect.DeepEqual(gt.Pod, want) {
+		t.Errorf("groundtruth pods = %v, want %v", gt.Pod, want)
+	}
+
+	// The pod mode is part of the action space and round-trips through the node form
+	space, err := StructToNode[InjectionConf]("podmodesys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	podFailure := space.Children["1"]
+	if mode, value := podFailure.Children["4"], podFailure.Children["5"]; mode == nil || value == nil ||
+		!reflect.DeepEqual(mode.Range, []int{0, 4}) || !reflect.DeepEqual(value.Range, []int{1, 100}) {
+		t.Errorf("PodFailure node = %+v, want the pod mode fields with their ranges", podFailure.Children)
+	}
+
+	node, err := InjectionConfToNode(&InjectionConf{PodFailure: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := NodeToStruct[InjectionConf](node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.PodFailure == nil || back.PodFailure.Mode != spec.Mode || back.PodFailure.ModeValue != spec.ModeValue {
+		t.Errorf("NodeToStruct() = %+v, want the pod mode of %+v", back.PodFailure, spec)
+	}
+}
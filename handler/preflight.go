@@ -0,0 +1,642 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PreflightCheck names one live check of a resolved injection target
+type PreflightCheck string
+
+const (
+	CheckNamespace PreflightCheck = "namespace"
+	CheckApp       PreflightCheck = "app"
+	CheckContainer PreflightCheck = "container"
+	CheckJVM       PreflightCheck = "jvm"
+	CheckPort      PreflightCheck = "port"
+	CheckVolume    PreflightCheck = "volume"
+	CheckMachine   PreflightCheck = "machine"
+)
+
+// PreflightError is a resolved target that does not match the live cluster
+type PreflightError struct {
+	ChaosType string         `json:"chaos_type"`
+	Namespace string         `json:"namespace"`
+	Check     PreflightCheck `json:"check"`
+	Target    string         `json:"target"`
+	Reason    string         `json:"reason"`
+	Hint      string         `json:"hint,omitempty"`
+}
+
+func (e *PreflightError) Error() string {
+	msg := fmt.Sprintf("preflight %s check of %s failed for %s in %s: %s", e.Check, e.ChaosType, e.Target, e.Namespace, e.Reason)
+	if e.Hint != "" {
+		msg += " (" + e.Hint + ")"
+	}
+	return msg
+}
+
+// PreflightErrors holds every failed check of one injection
+type PreflightErrors []*PreflightError
+
+func (e PreflightErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// InjectionTarget is the injection point of a spec resolved to the workloads it touches
+type InjectionTarget struct {
+	ChaosType   string `json:"chaos_type"`
+	Namespace   string `json:"namespace"`
+	App         string `json:"app"`
+	Container   string `json:"container,omitempty"`
+	Peer        string `json:"peer,omitempty"`
+	Port        int32  `json:"port,omitempty"`
+	VolumePath  string `json:"volume_path,omitempty"`
+	RequiresJVM bool   `json:"requires_jvm,omitempty"`
+	// OnMachine targets the PhysicalMachine objects carrying the app label instead of pods
+	OnMachine bool `json:"on_machine,omitempty"`
+}
+
+// JVMDetector reports whether a container of a running pod runs a JVM
+type JVMDetector func(pod *corev1.Pod, container *corev1.Container) bool
+
+type PreflightConf struct {
+	JVMDetector JVMDetector
+	Skip        map[PreflightCheck]bool
+}
+
+type PreflightOption func(*PreflightConf)
+
+// WithJVMDetector replaces the image/command/env heuristic used by the jvm check
+func WithJVMDetector(detector JVMDetector) PreflightOption {
+	return func(c *PreflightConf) {
+		c.JVMDetector = detector
+	}
+}
+
+// WithSkipChecks disables checks that are known not to apply to a system
+func WithSkipChecks(checks ...PreflightCheck) PreflightOption {
+	return func(c *PreflightConf) {
+		for _, check := range checks {
+			c.Skip[check] = true
+		}
+	}
+}
+
+func newPreflightConf(opts []PreflightOption) PreflightConf {
+	conf := PreflightConf{
+		JVMDetector: DetectJVM,
+		Skip:        make(map[PreflightCheck]bool),
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return conf
+}
+
+var jvmHints = []string{"java", "jdk", "jre", "jvm", "temurin", "corretto", "zulu", "spring"}
+
+// DetectJVM guesses from the container spec whether it runs a JVM. Images that do not mention
+// Java anywhere in their spec need a custom detector, see WithJVMDetector.
+func DetectJVM(_ *corev1.Pod, container *corev1.Container) bool {
+	candidates := append([]string{container.Image}, container.Command...)
+	candidates = append(candidates, container.Args...)
+	for _, env := range container.Env {
+		if strings.HasPrefix(env.Name, "JAVA_") || strings.HasPrefix(env.Name, "JDK_") {
+			return true
+		}
+		candidates = append(candidates, env.Value)
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(candidate)
+		for _, hint := range jvmHints {
+			if strings.Contains(candidate, hint) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ResolveTarget resolves the injection point of the active spec against the current catalogs
+func (ic *InjectionConf) ResolveTarget(namespaceTargetIndex int) (*InjectionTarget, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return nil, err
+	}
+
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+
+	chaosType := entry.Name
+	specVal := activeField.Elem()
+
+	nsIdx := int(specVal.FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return nil, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+
+	pointField, ok := injectionPointField(specVal.Type())
+	if !ok {
+		return nil, fmt.Errorf("%s has no injection point field", chaosType)
+	}
+
+	system, err := currentSystem(NamespacePrefixs[nsIdx], injectionPointKinds[pointField])
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := resolveTarget(system, chaosType, pointField, int(specVal.FieldByName(pointField).Int()))
+	if err != nil {
+		return nil, err
+	}
+
+	target.Namespace = GetTargetNamespace(nsIdx, namespaceTargetIndex)
+	return target, nil
+}
+
+// Preflight checks the resolved target of the injection against the live pods and services of the
+// target namespace. It returns PreflightErrors listing every failed check, or nil.
+// A nil client uses the client registered for the namespace prefix.
+func (ic *InjectionConf) Preflight(ctx context.Context, k8sClient cli.Client, namespaceTargetIndex int, opts ...PreflightOption) error {
+	target, err := ic.ResolveTarget(namespaceTargetIndex)
+	if err != nil {
+		return err
+	}
+
+	if k8sClient == nil {
+		if k8sClient, err = client.ProviderFor(target.Namespace).Client(); err != nil {
+			return err
+		}
+	}
+
+	state, err := loadLiveState(ctx, k8sClient, target.Namespace)
+	if err != nil {
+		return err
+	}
+
+	conf := newPreflightConf(opts)
+	if errs := state.check(target, conf); len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// CreateWithPreflight runs Preflight and only creates the injection when every check passes
+func (ic *InjectionConf) CreateWithPreflight(ctx context.Context, namespaceTargetIndex int, annotations map[string]string, labels map[string]string, opts ...PreflightOption) (string, error) {
+	k8sClient, err := ic.client()
+	if err != nil {
+		return "", err
+	}
+
+	if err := ic.Preflight(ctx, k8sClient, namespaceTargetIndex, opts...); err != nil {
+		return "", err
+	}
+
+	return ic.CreateWithClient(ctx, k8sClient, namespaceTargetIndex, annotations, labels)
+}
+
+// ValidPoints maps each chaos type (the InjectionConf field name) to the sorted injection point
+// indices that pass the preflight checks
+type ValidPoints map[string][]int
+
+// Contains reports whether the injection point idx of the chaos type passed the preflight checks
+func (v ValidPoints) Contains(chaosType string, idx int) bool {
+	for _, point := range v[chaosType] {
+		if point == idx {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterNode drops the chaos types without valid points from a tree built by StructToNode and
+// narrows the injection point ranges to the valid indices. Ranges cannot express gaps, so use
+// Contains for points in between.
+func (v ValidPoints) FilterNode(n *Node) {
+	for key, child := range n.Children {
+		points := v[child.Name]
+		if len(points) == 0 {
+			delete(n.Children, key)
+			continue
+		}
+
+		for _, field := range child.Children {
+			if _, isPoint := injectionPointKinds[field.Name]; isPoint {
+				field.Range = []int{points[0], points[len(points)-1]}
+			}
+		}
+	}
+}
+
+// ValidInjectionPoints runs the preflight checks for every injection point of every chaos type
+// against one target namespace of the prefix. The cluster is read once, so this is cheap enough to
+// run before each sampling round. A nil client uses the client registered for the prefix.
+func ValidInjectionPoints(ctx context.Context, k8sClient cli.Client, prefix string, namespaceTargetIndex int, opts ...PreflightOption) (ValidPoints, error) {
+	nsIdx := -1
+	for idx, p := range NamespacePrefixs {
+		if p == prefix {
+			nsIdx = idx
+			break
+		}
+	}
+	if nsIdx < 0 {
+		return nil, fmt.Errorf("namespace prefix %s is not configured", prefix)
+	}
+
+	namespace := GetTargetNamespace(nsIdx, namespaceTargetIndex)
+	if k8sClient == nil {
+		var err error
+		if k8sClient, err = client.ProviderFor(namespace).Client(); err != nil {
+			return nil, err
+		}
+	}
+
+	system, err := currentSystem(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadLiveState(ctx, k8sClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := newPreflightConf(opts)
+	result := make(ValidPoints)
+
+	for _, entry := range registeredChaosTypes() {
+		pointField, ok := injectionPointField(entry.specType)
+		if !ok {
+			continue
+		}
+
+		ids, err := system.PointIDs(injectionPointKinds[pointField])
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := range ids {
+			target, err := resolveTarget(system, entry.Name, pointField, idx)
+			if err != nil {
+				return nil, err
+			}
+
+			target.Namespace = namespace
+			if len(state.check(target, conf)) == 0 {
+				result[entry.Name] = append(result[entry.Name], idx)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// injectionPointField returns the name of the field of a spec that indexes into a catalog
+func injectionPointField(specType reflect.Type) (string, bool) {
+	for i := range specType.NumField() {
+		if _, ok := injectionPointKinds[specType.Field(i).Name]; ok {
+			return specType.Field(i).Name, true
+		}
+	}
+	return "", false
+}
+
+func pointAt[T any](items []T, idx int, field string) (T, error) {
+	var zero T
+	if idx < 0 || idx >= len(items) {
+		return zero, fmt.Errorf("%s out of range: %d (max: %d)", field, idx, len(items)-1)
+	}
+	return items[idx], nil
+}
+
+func resolveTarget(system *resourcelookup.SystemSnapshot, chaosType, pointField string, idx int) (*InjectionTarget, error) {
+	target := &InjectionTarget{
+		ChaosType:   chaosType,
+		RequiresJVM: strings.HasPrefix(chaosType, "JVM"),
+		OnMachine:   strings.HasPrefix(chaosType, "PhysicalMachine"),
+	}
+
+	switch pointField {
+	case KeyApp:
+		app, err := pointAt(system.AppLabels, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = app
+	case KeyMethod:
+		method, err := pointAt(system.Methods, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = method.AppName
+	case KeyEndpoint:
+		endpoint, err := pointAt(system.Endpoints, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = endpoint.AppName
+		target.Peer = endpoint.ServerAddress
+		target.Port = (&HTTPEndpoint{Port: endpoint.ServerPort}).GetEndpointPort()
+	case KeyNetworkPair:
+		pair, err := pointAt(system.NetworkPairs, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = pair.SourceService
+		target.Peer = pair.TargetService
+	case KeyContainer:
+		container, err := pointAt(system.Containers, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = container.AppLabel
+		target.Container = container.ContainerName
+	case KeyDNSEndpoint:
+		endpoint, err := pointAt(system.DNSEndpoints, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = endpoint.AppName
+	case KeyDatabase:
+		operation, err := pointAt(system.DBOperations, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = operation.AppName
+	case KeyVolume:
+		volume, err := pointAt(system.Volumes, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = volume.AppLabel
+		target.Container = volume.ContainerName
+		target.VolumePath = volume.VolumePath
+	case KeyBlockVolume:
+		volume, err := pointAt(system.BlockVolumes(), idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = volume.AppLabel
+		target.Container = volume.ContainerName
+		target.VolumePath = volume.VolumePath
+	case KeyMachine:
+		machine, err := pointAt(system.Machines, idx, pointField)
+		if err != nil {
+			return nil, err
+		}
+		target.App = machine
+	default:
+		return nil, fmt.Errorf("unknown injection point field %s", pointField)
+	}
+
+	return target, nil
+}
+
+// liveState is the part of a namespace the checks look at, read once per validation
+type liveState struct {
+	namespaceFound bool
+	pods           []corev1.Pod
+	services       []corev1.Service
+	machines       []chaosmeshv1alpha1.PhysicalMachine
+}
+
+func loadLiveState(ctx context.Context, k8sClient cli.Client, namespace string) (*liveState, error) {
+	state := &liveState{namespaceFound: true}
+
+	if err := k8sClient.Get(ctx, cli.ObjectKey{Name: namespace}, &corev1.Namespace{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+		}
+
+		state.namespaceFound = false
+		return state, nil
+	}
+
+	var pods corev1.PodList
+	if err := k8sClient.List(ctx, &pods, cli.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+	state.pods = pods.Items
+
+	var services corev1.ServiceList
+	if err := k8sClient.List(ctx, &services, cli.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+	}
+	state.services = services.Items
+
+	// Physical machines are only registered when chaosd is in use, a missing CRD means there are none
+	var machines chaosmeshv1alpha1.PhysicalMachineList
+	if err := k8sClient.List(ctx, &machines, cli.InNamespace(namespace)); err != nil {
+		if !meta.IsNoMatchError(err) && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to list physical machines in namespace %s: %w", namespace, err)
+		}
+	}
+	state.machines = machines.Items
+
+	return state, nil
+}
+
+// podsOf returns the pods carrying the app label and the running ones among them
+func (s *liveState) podsOf(app string) (all []*corev1.Pod, running []*corev1.Pod) {
+	for i := range s.pods {
+		pod := &s.pods[i]
+		if pod.Labels[TargetLabelKey] != app {
+			continue
+		}
+
+		all = append(all, pod)
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	return all, running
+}
+
+func (s *liveState) hasMachine(app string) bool {
+	for _, machine := range s.machines {
+		if machine.Labels[TargetLabelKey] == app {
+			return true
+		}
+	}
+	return false
+}
+
+// containersOf returns the containers of the pods, only the named one if name is set
+func containersOf(pods []*corev1.Pod, name string) (containers []*corev1.Container, owners []*corev1.Pod) {
+	for _, pod := range pods {
+		for i := range pod.Spec.Containers {
+			if name == "" || pod.Spec.Containers[i].Name == name {
+				containers = append(containers, &pod.Spec.Containers[i])
+				owners = append(owners, pod)
+			}
+		}
+	}
+	return containers, owners
+}
+
+func (s *liveState) check(target *InjectionTarget, conf PreflightConf) PreflightErrors {
+	var errs PreflightErrors
+	fail := func(check PreflightCheck, subject, reason, hint string) {
+		errs = append(errs, &PreflightError{
+			ChaosType: target.ChaosType,
+			Namespace: target.Namespace,
+			Check:     check,
+			Target:    subject,
+			Reason:    reason,
+			Hint:      hint,
+		})
+	}
+
+	appSelector := fmt.Sprintf("%s=%s", TargetLabelKey, target.App)
+
+	if !conf.Skip[CheckNamespace] && !s.namespaceFound {
+		fail(CheckNamespace, target.Namespace, "namespace does not exist",
+			"deploy the system into the namespace or lower the namespace target index")
+		return errs
+	}
+
+	if target.OnMachine {
+		if !conf.Skip[CheckMachine] && !s.hasMachine(target.App) {
+			fail(CheckMachine, appSelector, "no PhysicalMachine carries the app label",
+				"register the hosts of the app as PhysicalMachine objects labeled with the app")
+		}
+		return errs
+	}
+
+	if conf.Skip[CheckApp] {
+		return errs
+	}
+
+	all, running := s.podsOf(target.App)
+	if len(all) == 0 {
+		fail(CheckApp, appSelector, "no pod carries the app label",
+			"the app was removed or renamed, rebuild the catalog or redeploy the app")
+		return errs
+	}
+	if len(running) == 0 {
+		fail(CheckApp, appSelector, fmt.Sprintf("none of the %d pods is running", len(all)),
+			"wait for the rollout to finish before injecting")
+		return errs
+	}
+
+	containers, owners := containersOf(running, target.Container)
+	if !conf.Skip[CheckContainer] && target.Container != "" && len(containers) == 0 {
+		fail(CheckContainer, fmt.Sprintf("%s/%s", target.App, target.Container), "no running pod has the container",
+			"the pod template changed since the containers were cached, invalidate the lookup cache")
+	}
+
+	if !conf.Skip[CheckJVM] && target.RequiresJVM {
+		found := false
+		for i, container := range containers {
+			if conf.JVMDetector(owners[i], container) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail(CheckJVM, appSelector, "no running container looks like it runs a JVM",
+				"pick a Java service, or pass WithJVMDetector if the image hides its runtime")
+		}
+	}
+
+	if !conf.Skip[CheckVolume] && target.VolumePath != "" {
+		found := false
+		for _, container := range containers {
+			for _, mount := range container.VolumeMounts {
+				if mount.MountPath == target.VolumePath {
+					found = true
+				}
+			}
+		}
+		if !found {
+			fail(CheckVolume, fmt.Sprintf("%s:%s", appSelector, target.VolumePath), "the path is not mounted in any running container",
+				"the volume was removed from the pod template, invalidate the lookup cache")
+		}
+	}
+
+	if target.Port != 0 {
+		if !conf.Skip[CheckPort] {
+			s.checkPort(target, fail)
+		}
+	} else if target.Peer != "" {
+		peerSelector := fmt.Sprintf("%s=%s", TargetLabelKey, target.Peer)
+		if _, peerRunning := s.podsOf(target.Peer); len(peerRunning) == 0 {
+			fail(CheckApp, peerSelector, "no running pod carries the app label of the peer",
+				"the peer was removed or is not ready, rebuild the catalog or wait for the rollout")
+		}
+	}
+
+	return errs
+}
+
+// checkPort looks for the port on the service the peer address points at, falling back to the
+// container ports of the peer's pods for headless setups without a service
+func (s *liveState) checkPort(target *InjectionTarget, fail func(check PreflightCheck, subject, reason, hint string)) {
+	host := target.Peer
+	if host == "" {
+		host = target.App
+	}
+	host, _, _ = strings.Cut(host, ".")
+	subject := fmt.Sprintf("%s:%d", host, target.Port)
+
+	for _, svc := range s.services {
+		if svc.Name != host {
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			if port.Port == target.Port || port.TargetPort.IntValue() == int(target.Port) {
+				return
+			}
+		}
+
+		fail(CheckPort, subject, fmt.Sprintf("service %s does not expose the port", host),
+			"the route's port changed, rebuild the catalog")
+		return
+	}
+
+	_, running := s.podsOf(host)
+	if len(running) == 0 {
+		fail(CheckPort, subject, "no service or running pod serves the address",
+			"the server address is probably outside the cluster, pick an in-cluster endpoint")
+		return
+	}
+
+	containers, _ := containersOf(running, "")
+	declared := false
+	for _, container := range containers {
+		for _, port := range container.Ports {
+			declared = true
+			if port.ContainerPort == target.Port {
+				return
+			}
+		}
+	}
+
+	// Containers are not required to declare their ports, so only a declared mismatch fails
+	if declared {
+		fail(CheckPort, subject, fmt.Sprintf("no container of %s declares the port", host),
+			"the route's port changed, rebuild the catalog")
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreflight(t *testing.T) {
+	pod := func(app, image string, port int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: app + "-0", Namespace: "livesys0", Labels: map[string]string{"app": app}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  app,
+				Image: image,
+				Ports: []corev1.ContainerPort{{ContainerPort: port}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	fakeClient := newTestClient(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "livesys0"}},
+		pod("frontend", "example/frontend:v1", 8080),
+		pod("cartservice", "eclipse-temurin:17-jre", 7070),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "cartservice", Namespace: "livesys0"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 7070}}},
+		},
+	)
+	useTestSystem(t, "livesys", 1, fakeClient)
+
+	resourcelookup.RegisterCatalog("livesys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cartservice", ServerPort: "7070"},
+			{AppName: "frontend", Method: "GET", Route: "/product", ServerAddress: "productcatalogservice", ServerPort: "3550"},
+		},
+		MethodList: []resourcelookup.AppMethodPair{
+			{AppName: "adservice", ClassName: "hipstershop.AdService", MethodName: "getAds"},
+			{AppName: "cartservice", ClassName: "hipstershop.CartService", MethodName: "getCart"},
+			{AppName: "frontend", ClassName: "main", MethodName: "serve"},
+		},
+	})
+
+	ctx := context.Background()
+
+	ic := &InjectionConf{HTTPRequestAbort: &HTTPRequestAbortSpec{Duration: 1, EndpointIdx: 1}}
+	err := ic.Preflight(ctx, nil, 0)
+	var errs PreflightErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Check != CheckPort || errs[0].Target != "productcatalogservice:3550" {
+		t.Fatalf("Preflight() error = %v, want one port failure for productcatalogservice:3550", err)
+	}
+
+	ic = &InjectionConf{HTTPRequestAbort: &HTTPRequestAbortSpec{Duration: 1, EndpointIdx: 0}}
+	if err := ic.Preflight(ctx, nil, 0); err != nil {
+		t.Errorf("Preflight() of an exposed endpoint error = %v", err)
+	}
+
+	valid, err := ValidInjectionPoints(ctx, nil, "livesys", 0)
+	if err != nil {
+		t.Fatalf("ValidInjectionPoints() error = %v", err)
+	}
+
+	methods, err := resourcelookup.GetAllJVMMethods("livesys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantMethods []int
+	for idx, method := range methods {
+		if method.AppName == "cartservice" {
+			wantMethods = append(wantMethods, idx)
+		}
+	}
+
+	tests := []struct {
+		chaosType string
+		want      []int
+	}{
+		{chaosType: "HTTPRequestAbort", want: []int{0}},
+		{chaosType: "JVMLatency", want: wantMethods},
+	}
+	for _, tt := range tests {
+		if got := valid[tt.chaosType]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("valid[%s] = %v, want %v", tt.chaosType, got, tt.want)
+		}
+	}
+
+	valid, err = ValidInjectionPoints(ctx, nil, "livesys", 0, WithJVMDetector(func(*corev1.Pod, *corev1.Container) bool { return false }))
+	if err != nil {
+		t.Fatalf("ValidInjectionPoints() error = %v", err)
+	}
+
+	node, err := StructToNode[InjectionConf]("livesys")
+	if err != nil {
+		t.Fatalf("StructToNode() error = %v", err)
+	}
+	valid.FilterNode(node)
+
+	for _, child := range node.Children {
+		if child.Name == "JVMLatency" {
+			t.Errorf("JVMLatency should be filtered out without a JVM")
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/networkdependencies"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultBlastRadiusHops is how many caller hops from the root cause services InjectionConf.GetGroundtruth
+// follows to fill Groundtruth.Propagated, see GetGroundtruthWithBlastRadius for other depths
+const DefaultBlastRadiusHops = 2
+
+// PropagatedService is a service the fault is expected to reach through its calls
+type PropagatedService struct {
+	Service string `json:"service"`
+	// Hops is the number of calls between the service and the closest root cause
+	Hops int `json:"hops"`
+	// Via is the service called on the way to the root cause
+	Via string `json:"via"`
+}
+
+// callGraph returns the directed call graph of the namespace's system. The database operations are
+// added as calls to the database services the app connects to in the catalog, operations of an app
+// without a known database connection are left out.
+func callGraph(namespace string) (*networkdependencies.Graph, error) {
+	graph, err := resourcelookup.GetServiceGraph(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service graph: %w", err)
+	}
+
+	operations, err := resourcelookup.GetAllDatabaseOperations(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database operations: %w", err)
+	}
+	for _, operation := range operations {
+		for _, database := range databaseServices(graph, operation.AppName) {
+			graph.AddCall(operation.AppName, database, networkdependencies.Call{
+				Kind:   networkdependencies.CallDatabase,
+				Method: operation.OperationType,
+				Route:  operation.TableName,
+			}, 0)
+		}
+	}
+
+	return graph, nil
+}
+
+// databaseServices returns the services the app calls with database calls
+func databaseServices(graph *networkdependencies.Graph, app string) []string {
+	var result []string
+	for _, callee := range graph.Callees(app) {
+		edge, _ := graph.Edge(app, callee)
+		if slices.Contains(edge.Kinds(), networkdependencies.CallDatabase) {
+			result = append(result, callee)
+		}
+	}
+	return result
+}
+
+// BlastRadius returns the callers of the root cause services up to the given number of hops,
+// closest first. The root causes themselves are not part of the result.
+func BlastRadius(namespace string, rootCauses []string, hops int) ([]PropagatedService, error) {
+	if hops <= 0 || len(rootCauses) == 0 {
+		return nil, nil
+	}
+
+	graph, err := callGraph(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := make(map[string]bool, len(rootCauses))
+	for _, service := range rootCauses {
+		visited[service] = true
+	}
+
+	var result []PropagatedService
+	frontier := append([]string(nil), rootCauses...)
+	for hop := 1; hop <= hops && len(frontier) > 0; hop++ {
+		var next []string
+		for _, callee := range frontier {
+			for _, caller := range graph.Callers(callee) {
+				if visited[caller] {
+					continue
+				}
+
+				visited[caller] = true
+				result = append(result, PropagatedService{Service: caller, Hops: hop, Via: callee})
+				next = append(next, caller)
+			}
+		}
+		frontier = next
+	}
+
+	return result, nil
+}
+
+// withBlastRadius labels the services of the groundtruth as root causes and adds their callers.
+// The callers are best effort: when the call graph cannot be built the groundtruth keeps its root
+// causes without propagated services.
+func (gt Groundtruth) withBlastRadius(namespace string, hops int) Groundtruth {
+	gt.RootCause = appendUnique(gt.RootCause, gt.Service...)
+
+	propagated, err := BlastRadius(namespace, gt.RootCause, hops)
+	if err != nil {
+		logrus.Warnf("Skipping the blast radius of %v in %s: %v", gt.RootCause, namespace, err)
+		return gt
+	}
+	gt.Propagated = mergePropagated(gt.Propagated, propagated...)
+
+	return gt
+}
+
+// mergePropagated adds the services, keeping the fewest hops of a service seen twice
+func mergePropagated(dst []PropagatedService, services ...PropagatedService) []PropagatedService {
+	for _, service := range services {
+		idx := slices.IndexFunc(dst, func(existing PropagatedService) bool { return existing.Service == service.Service })
+		switch {
+		case idx < 0:
+			dst = append(dst, service)
+		case service.Hops < dst[idx].Hops:
+			dst[idx] = service
+		}
+	}
+
+	return dst
+}
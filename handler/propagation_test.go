@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+func TestBlastRadius(t *testing.T) {
+	useTestSystem(t, "blastsys", 1, nil)
+	resourcelookup.RegisterCatalog("blastsys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "gateway", Method: "GET", Route: "/", ServerAddress: "frontend"},
+			{AppName: "frontend", Method: "POST", Route: "/checkout", ServerAddress: "checkout.blastsys0.svc.cluster.local"},
+			{AppName: "checkout", Method: "POST", Route: "/charge", ServerAddress: "payment"},
+			{AppName: "frontend", Method: "GET", Route: "/ads", ServerAddress: "ads"},
+			{AppName: "payment", ServerAddress: "pay-db.blastsys0.svc.cluster.local", ServerPort: "5432"},
+		},
+		DBOperationsList: []resourcelookup.AppDatabasePair{
+			{AppName: "payment", DBName: "pay", TableName: "charges", OperationType: "INSERT"},
+			{AppName: "ads", DBName: "ads", TableName: "banners", OperationType: "SELECT"},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		rootCauses []string
+		hops       int
+		want       []PropagatedService
+	}{
+		{
+			name:       "callers up to two hops",
+			rootCauses: []string{"payment"},
+			hops:       2,
+			want: []PropagatedService{
+				{Service: "checkout", Hops: 1, Via: "payment"},
+				{Service: "frontend", Hops: 2, Via: "checkout"},
+			},
+		},
+		{
+			name:       "database callers",
+			rootCauses: []string{"pay-db"},
+			hops:       1,
+			want:       []PropagatedService{{Service: "payment", Hops: 1, Via: "pay-db"}},
+		},
+		{
+			name:       "no database assumed for apps without a connection",
+			rootCauses: []string{"mysql"},
+			hops:       1,
+		},
+		{
+			name:       "root causes are not propagated",
+			rootCauses: []string{"frontend", "checkout"},
+			hops:       3,
+			want:       []PropagatedService{{Service: "gateway", Hops: 1, Via: "frontend"}},
+		},
+		{
+			name:       "disabled",
+			rootCauses: []string{"payment"},
+			hops:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BlastRadius("blastsys0", tt.rootCauses, tt.hops)
+			if err != nil {
+				t.Fatalf("BlastRadius() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BlastRadius() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	gt := Groundtruth{Service: []string{"checkout", "payment"}}.withBlastRadius("blastsys0", 1)
+	other := Groundtruth{Service: []string{"frontend"}}.withBlastRadius("blastsys0", 1)
+
+	merged := MergeGroundtruth(gt, other)
+	wantRootCause := []string{"checkout", "payment", "frontend"}
+	wantPropagated := []PropagatedService{{Service: "gateway", Hops: 1, Via: "frontend"}}
+	if !reflect.DeepEqual(merged.RootCause, wantRootCause) || !reflect.DeepEqual(merged.Propagated, wantPropagated) {
+		t.Errorf("MergeGroundtruth() = %+v, want root causes %v and propagated %+v", merged, wantRootCause, wantPropagated)
+	}
+
+}
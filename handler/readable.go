@@ -0,0 +1,562 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+	"github.com/LGU-SE-Internal/chaos-experiment/utils"
+	"sigs.k8s.io/yaml"
+)
+
+// readableTargetKeys are the keys naming an injection point of every catalog
+var readableTargetKeys = map[resourcelookup.PointKind][]string{
+	resourcelookup.PointApp:         {"app"},
+	resourcelookup.PointMethod:      {"app", "class", "method"},
+	resourcelookup.PointEndpoint:    {"app", "http_method", "route", "server"},
+	resourcelookup.PointNetworkPair: {"source", "target"},
+	resourcelookup.PointContainer:   {"app", "container"},
+	resourcelookup.PointDNSEndpoint: {"app", "domain"},
+	resourcelookup.PointDatabase:    {"app", "database", "table", "operation"},
+	resourcelookup.PointVolume:      {"app", "container", "volume"},
+	resourcelookup.PointBlockVolume: {"app", "container", "volume"},
+	resourcelookup.PointMachine:     {"app"},
+}
+
+// readableUnits are the units of the spec fields holding a time, which also accept Go durations
+// such as 200ms or 5m
+var readableUnits = map[string]time.Duration{
+	"Duration":        time.Minute,
+	"TimeOffset":      time.Second,
+	"Latency":         time.Millisecond,
+	"LatencyDuration": time.Millisecond,
+	"LatencyMs":       time.Millisecond,
+	"Jitter":          time.Millisecond,
+	"Delay":           time.Millisecond,
+	"DelayDuration":   time.Millisecond,
+}
+
+var readableUnitSuffixes = map[time.Duration]string{
+	time.Minute:      "m",
+	time.Second:      "s",
+	time.Millisecond: "ms",
+}
+
+// readableDirections names the direction codes of the network chaos specs
+var readableDirections = map[string]int{
+	"to":   1,
+	"from": 2,
+	"both": 3,
+	"call": DirectionAlongCall,
+}
+
+// readablePodModes names the pod modes of the pod specs, see PodMode
+var readablePodModes = map[string]int{
+	"all":                int(AllPods),
+	"one":                int(OnePod),
+	"fixed":              int(FixedPods),
+	"fixed-percent":      int(FixedPercentPods),
+	"random-max-percent": int(RandomMaxPercentPods),
+}
+
+// ReadableInjection is the hand-written form of an InjectionConf: the injection point is named by
+// its content, e.g. the source and target service of a network pair, and times carry their unit.
+// In JSON and YAML every key sits at the top level:
+//
+//	{type: NetworkDelay, source: ts-order-service, target: ts-travel-service, latency: 200ms, direction: to, duration: 5m}
+type ReadableInjection struct {
+	// Type is the registered chaos type name
+	Type string
+	// Namespace is the namespace prefix of the system, it may be left out with a single system
+	Namespace string
+	// Target names the injection point with the keys of its catalog
+	Target map[string]string
+	// Params holds the other spec fields by their snake case name
+	Params map[string]any
+}
+
+func (r ReadableInjection) MarshalJSON() ([]byte, error) {
+	result := make(map[string]any, len(r.Target)+len(r.Params)+2)
+	for key, value := range r.Params {
+		result[key] = value
+	}
+	for key, value := range r.Target {
+		result[key] = value
+	}
+	result["type"] = r.Type
+	if r.Namespace != "" {
+		result["namespace"] = r.Namespace
+	}
+
+	return json.Marshal(result)
+}
+
+func (r *ReadableInjection) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = ReadableInjection{Target: make(map[string]string), Params: make(map[string]any)}
+	for key, value := range raw {
+		switch {
+		case key == "type" || key == "namespace" || isReadableTargetKey(key):
+			text, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("'%s' must be a string, got %v", key, value)
+			}
+
+			switch key {
+			case "type":
+				r.Type = text
+			case "namespace":
+				r.Namespace = text
+			default:
+				r.Target[key] = text
+			}
+		default:
+			r.Params[key] = value
+		}
+	}
+
+	if r.Type == "" {
+		return fmt.Errorf("'type' is required")
+	}
+	return nil
+}
+
+// ParseReadableInjection reads an injection in the readable form from JSON or YAML
+func ParseReadableInjection(data []byte) (*ReadableInjection, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse injection: %w", err)
+	}
+
+	r := &ReadableInjection{}
+	if err := json.Unmarshal(jsonData, r); err != nil {
+		return nil, fmt.Errorf("failed to parse injection: %w", err)
+	}
+	return r, nil
+}
+
+// ToInjectionConf resolves the names of the injection against the current catalogs
+func (r *ReadableInjection) ToInjectionConf() (*InjectionConf, error) {
+	return r.toConf(currentSystem)
+}
+
+// ToNode resolves the injection into the value node NodeToStruct accepts
+func (r *ReadableInjection) ToNode() (*Node, error) {
+	conf, err := r.ToInjectionConf()
+	if err != nil {
+		return nil, err
+	}
+
+	return InjectionConfToNode(conf)
+}
+
+func (r *ReadableInjection) toConf(resolve systemResolver) (*InjectionConf, error) {
+	entry, ok := chaosTypeByName(r.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown chaos type '%s'", r.Type)
+	}
+
+	prefix := r.Namespace
+	switch {
+	case prefix == "" && len(NamespacePrefixs) == 1:
+		prefix = NamespacePrefixs[0]
+	case prefix == "":
+		return nil, fmt.Errorf("%s: namespace is required with several systems (available: %v)", r.Type, NamespacePrefixs)
+	case !slices.Contains(NamespacePrefixs, prefix):
+		return nil, fmt.Errorf("%s: namespace prefix %s is not configured (available: %v)", r.Type, prefix, NamespacePrefixs)
+	}
+
+	stable := &StableInjection{ChaosType: entry.Name, Namespace: prefix, Params: make(map[string]int)}
+
+	pointField, hasPoint := injectionPointField(entry.specType)
+	switch {
+	case hasPoint:
+		kind := injectionPointKinds[pointField]
+		system, err := resolve(prefix, kind)
+		if err != nil {
+			return nil, err
+		}
+
+		idx, err := matchReadableTarget(system, kind, r.Target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Type, err)
+		}
+
+		id, err := system.IDAt(kind, idx)
+		if err != nil {
+			return nil, err
+		}
+		stable.InjectionPoints = map[string]string{pointField: id}
+	case len(r.Target) > 0:
+		return nil, fmt.Errorf("%s has no injection point, remove %s", r.Type, strings.Join(sortedKeys(r.Target), ", "))
+	}
+
+	fields := readableParamFields(entry.specType, pointField)
+	for key, value := range r.Params {
+		field, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown parameter '%s' (known: %s)", r.Type, key, strings.Join(sortedKeys(fields), ", "))
+		}
+
+		param, err := parseReadableParam(field, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: parameter '%s': %w", r.Type, key, err)
+		}
+		stable.Params[field.Name] = param
+	}
+
+	// Parameters that can be 0, e.g. a jitter or correlation, are off when left out
+	for _, key := range sortedKeys(fields) {
+		field := fields[key]
+		if _, ok := r.Params[key]; ok || field.Tag.Get("optional") == "true" {
+			continue
+		}
+
+		start, end, err := parseRangeTag(field.Tag.Get("range"))
+		if err != nil || start > 0 || end < 0 {
+			return nil, fmt.Errorf("%s: missing parameter '%s'", r.Type, key)
+		}
+		stable.Params[field.Name] = 0
+	}
+
+	return stableToConf(stable, resolve)
+}
+
+// ToReadable renders the injection with its injection point named by the current catalogs
+func (ic *InjectionConf) ToReadable() (*ReadableInjection, error) {
+	return ic.toReadable(currentSystem)
+}
+
+// NodeToReadable renders an InjectionConf node in the readable form
+func NodeToReadable(n *Node) (*ReadableInjection, error) {
+	conf, err := NodeToStruct[InjectionConf](n)
+	if err != nil {
+		return nil, err
+	}
+
+	return conf.ToReadable()
+}
+
+func (ic *InjectionConf) toReadable(resolve systemResolver) (*ReadableInjection, error) {
+	entry, err := ic.activeChaosType()
+	if err != nil {
+		return nil, err
+	}
+
+	activeField, err := ic.getActiveField()
+	if err != nil {
+		return nil, err
+	}
+	specVal := activeField.Elem()
+
+	nsIdx := int(specVal.FieldByName(KeyNamespace).Int())
+	if nsIdx < 0 || nsIdx >= len(NamespacePrefixs) {
+		return nil, fmt.Errorf("namespace index %d exceeds available namespaces count %d", nsIdx, len(NamespacePrefixs))
+	}
+
+	result := &ReadableInjection{
+		Type:      entry.Name,
+		Namespace: NamespacePrefixs[nsIdx],
+		Target:    make(map[string]string),
+		Params:    make(map[string]any),
+	}
+
+	pointField, _ := injectionPointField(entry.specType)
+	for key, field := range readableParamFields(entry.specType, pointField) {
+		value, err := getIntValue(specVal.FieldByName(field.Name))
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.Name, err)
+		}
+		if value == 0 && field.Tag.Get("optional") == "true" {
+			continue
+		}
+		result.Params[key] = formatReadableParam(field, int(value))
+	}
+
+	if pointField == "" {
+		return result, nil
+	}
+
+	kind := injectionPointKinds[pointField]
+	system, err := resolve(result.Namespace, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := readableTargets(system, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := int(specVal.FieldByName(pointField).Int())
+	if idx < 0 || idx >= len(targets) {
+		return nil, fmt.Errorf("%s out of range: %d (max: %d)", pointField, idx, len(targets)-1)
+	}
+	for key, value := range targets[idx] {
+		if value != "" {
+			result.Target[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// readableParamFields returns the spec fields an injection sets besides its namespace and injection
+// point, by their snake case name
+func readableParamFields(specType reflect.Type, pointField string) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := range specType.NumField() {
+		field := specType.Field(i)
+		if field.Name == KeyNamespace || field.Name == KeyNamespaceTarget || field.Name == pointField {
+			continue
+		}
+		fields[utils.ToSnakeCase(field.Name)] = field
+	}
+	return fields
+}
+
+// parseReadableParam converts a parameter to the value of the spec field and checks its range
+func parseReadableParam(field reflect.StructField, raw any) (int, error) {
+	var value int
+	switch v := raw.(type) {
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("%v is not a whole number", v)
+		}
+		value = int(v)
+	case string:
+		parsed, err := parseReadableString(field, v)
+		if err != nil {
+			return 0, err
+		}
+		value = parsed
+	default:
+		return 0, fmt.Errorf("unsupported value %v", raw)
+	}
+
+	start, end, err := parseRangeTag(field.Tag.Get("range"))
+	if err != nil {
+		return 0, err
+	}
+	if value < start || value > end {
+		return 0, fmt.Errorf("%s is out of range [%s, %s]", formatReadableValue(field, value),
+			formatReadableValue(field, start), formatReadableValue(field, end))
+	}
+
+	return value, nil
+}
+
+func parseReadableString(field reflect.StructField, text string) (int, error) {
+	if value, err := strconv.Atoi(text); err == nil {
+		return value, nil
+	}
+
+	if field.Name == "Direction" {
+		direction, ok := readableDirections[strings.ToLower(text)]
+		if !ok {
+			return 0, fmt.Errorf("unknown direction '%s' (known: %s)", text, strings.Join(sortedKeys(readableDirections), ", "))
+		}
+		return direction, nil
+	}
+
+	if field.Name == "Mode" {
+		mode, ok := readablePodModes[strings.ToLower(text)]
+		if !ok {
+			return 0, fmt.Errorf("unknown pod mode '%s' (known: %s)", text, strings.Join(sortedKeys(readablePodModes), ", "))
+		}
+		return mode, nil
+	}
+
+	unit, ok := readableUnits[field.Name]
+	if !ok {
+		return 0, fmt.Errorf("'%s' is not a number", text)
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is neither a number of %s nor a duration", text, readableUnitSuffixes[unit])
+	}
+	if duration%unit != 0 {
+		return 0, fmt.Errorf("%s is not a whole number of %s", text, readableUnitSuffixes[unit])
+	}
+	return int(duration / unit), nil
+}
+
+// formatReadableParam is the inverse of parseReadableParam
+func formatReadableParam(field reflect.StructField, value int) any {
+	if field.Name == "Direction" {
+		return directionName(value)
+	}
+	if field.Name == "Mode" {
+		return PodMode(value).String()
+	}
+	if _, ok := readableUnits[field.Name]; ok {
+		return formatReadableValue(field, value)
+	}
+	return value
+}
+
+func formatReadableValue(field reflect.StructField, value int) string {
+	if unit, ok := readableUnits[field.Name]; ok {
+		return strconv.Itoa(value) + readableUnitSuffixes[unit]
+	}
+	return strconv.Itoa(value)
+}
+
+// readableTargets returns the injection points of a catalog in index order, described by the
+// keys of readableTargetKeys
+func readableTargets(system *resourcelookup.SystemSnapshot, kind resourcelookup.PointKind) ([]map[string]string, error) {
+	var targets []map[string]string
+	switch kind {
+	case resourcelookup.PointApp:
+		for _, app := range system.AppLabels {
+			targets = append(targets, map[string]string{"app": app})
+		}
+	case resourcelookup.PointMethod:
+		for _, item := range system.Methods {
+			targets = append(targets, map[string]string{"app": item.AppName, "class": item.ClassName, "method": item.MethodName})
+		}
+	case resourcelookup.PointEndpoint:
+		for _, item := range system.Endpoints {
+			targets = append(targets, map[string]string{"app": item.AppName, "http_method": item.Method, "route": item.Route, "server": item.ServerAddress})
+		}
+	case resourcelookup.PointNetworkPair:
+		for _, item := range system.NetworkPairs {
+			targets = append(targets, map[string]string{"source": item.SourceService, "target": item.TargetService})
+		}
+	case resourcelookup.PointContainer:
+		for _, item := range system.Containers {
+			targets = append(targets, map[string]string{"app": item.AppLabel, "container": item.ContainerName})
+		}
+	case resourcelookup.PointDNSEndpoint:
+		for _, item := range system.DNSEndpoints {
+			targets = append(targets, map[string]string{"app": item.AppName, "domain": item.Domain})
+		}
+	case resourcelookup.PointDatabase:
+		for _, item := range system.DBOperations {
+			targets = append(targets, map[string]string{"app": item.AppName, "database": item.DBName, "table": item.TableName, "operation": item.OperationType})
+		}
+	case resourcelookup.PointVolume, resourcelookup.PointBlockVolume:
+		volumes := system.Volumes
+		if kind == resourcelookup.PointBlockVolume {
+			volumes = system.BlockVolumes()
+		}
+		for _, item := range volumes {
+			targets = append(targets, map[string]string{"app": item.AppLabel, "container": item.ContainerName, "volume": item.VolumePath})
+		}
+	case resourcelookup.PointMachine:
+		for _, machine := range system.Machines {
+			targets = append(targets, map[string]string{"app": machine})
+		}
+	default:
+		return nil, fmt.Errorf("unknown injection point kind: %s", kind)
+	}
+
+	return targets, nil
+}
+
+// matchReadableTarget returns the index of the only injection point matching every key of the target.
+// Points listed twice in a catalog count once.
+func matchReadableTarget(system *resourcelookup.SystemSnapshot, kind resourcelookup.PointKind, target map[string]string) (int, error) {
+	keys := readableTargetKeys[kind]
+	for _, key := range sortedKeys(target) {
+		if !slices.Contains(keys, key) {
+			return 0, fmt.Errorf("'%s' does not name %s injection points (use %s)", key, kind, strings.Join(keys, ", "))
+		}
+	}
+	if len(target) == 0 {
+		return 0, fmt.Errorf("missing injection point, name the %s with %s", kind, strings.Join(keys, ", "))
+	}
+
+	targets, err := readableTargets(system, kind)
+	if err != nil {
+		return 0, err
+	}
+
+	var matches []int
+	seen := make(map[string]bool)
+	for idx, candidate := range targets {
+		if !matchesTarget(candidate, target) {
+			continue
+		}
+
+		id := describeTarget(candidate, keys)
+		if !seen[id] {
+			seen[id] = true
+			matches = append(matches, idx)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		// Name the first key no injection point has, e.g. a misspelled service
+		for _, key := range keys {
+			value, ok := target[key]
+			if !ok {
+				continue
+			}
+
+			if !slices.ContainsFunc(targets, func(candidate map[string]string) bool { return candidate[key] == value }) {
+				return 0, fmt.Errorf("unknown %s '%s' for %s injection points", key, value, kind)
+			}
+		}
+		return 0, fmt.Errorf("no %s injection point matches %s", kind, describeTarget(target, keys))
+	default:
+		const shown = 5
+		var candidates []string
+		for _, idx := range matches[:min(len(matches), shown)] {
+			candidates = append(candidates, describeTarget(targets[idx], keys))
+		}
+
+		var missing []string
+		for _, key := range keys {
+			if _, ok := target[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+
+		return 0, fmt.Errorf("%s is ambiguous, %d %s injection points match (%s), add %s to choose one",
+			describeTarget(target, keys), len(matches), kind, strings.Join(candidates, "; "), strings.Join(missing, " or "))
+	}
+}
+
+func matchesTarget(candidate, target map[string]string) bool {
+	for key, value := range target {
+		if candidate[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// describeTarget formats the keys of the target in order, e.g. source=a target=b
+func describeTarget(target map[string]string, keys []string) string {
+	var parts []string
+	for _, key := range keys {
+		if value, ok := target[key]; ok {
+			parts = append(parts, key+"="+value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func isReadableTargetKey(key string) bool {
+	for _, keys := range readableTargetKeys {
+		if slices.Contains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
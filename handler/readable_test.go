@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+func TestReadableInjection(t *testing.T) {
+	prevPrefixs := NamespacePrefixs
+	NamespacePrefixs = []string{"readsys"}
+	defer func() { NamespacePrefixs = prevPrefixs }()
+
+	resolve := snapshotSystem(&resourcelookup.CatalogSnapshot{Systems: map[string]*resourcelookup.SystemSnapshot{
+		"readsys": {
+			AppLabels: []string{"ts-order-service", "ts-travel-service"},
+			Endpoints: []resourcelookup.AppEndpointPair{
+				{AppName: "ts-order-service", Method: "GET", Route: "/orders", ServerAddress: "ts-travel-service"},
+				{AppName: "ts-order-service", Method: "POST", Route: "/orders", ServerAddress: "ts-travel-service"},
+			},
+			NetworkPairs: []resourcelookup.AppNetworkPair{
+				{SourceService: "ts-travel-service", TargetService: "ts-order-service"},
+				{SourceService: "ts-order-service", TargetService: "ts-travel-service"},
+			},
+		},
+	}})
+
+	r, err := ParseReadableInjection([]byte("{type: NetworkDelay, source: ts-order-service, target: ts-travel-service, latency: 200ms, direction: to, duration: 5m}"))
+	if err != nil {
+		t.Fatalf("ParseReadableInjection() error = %v", err)
+	}
+
+	conf, err := r.toConf(resolve)
+	if err != nil {
+		t.Fatalf("toConf() error = %v", err)
+	}
+	want := NetworkDelaySpec{Duration: 5, NetworkPairIdx: 1, Latency: 200, Direction: 1}
+	if conf.NetworkDelay == nil || *conf.NetworkDelay != want {
+		t.Fatalf("toConf() = %+v, want %+v", conf.NetworkDelay, want)
+	}
+
+	rendered, err := conf.toReadable(resolve)
+	if err != nil {
+		t.Fatalf("toReadable() error = %v", err)
+	}
+	data, err := json.Marshal(rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const wantJSON = `{"correlation":0,"direction":"to","duration":"5m","jitter":"0ms","latency":"200ms","namespace":"readsys","source":"ts-order-service","target":"ts-travel-service","type":"NetworkDelay"}`
+	if string(data) != wantJSON {
+		t.Errorf("rendered = %s, want %s", data, wantJSON)
+	}
+
+	reparsed, err := ParseReadableInjection(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := reparsed.toConf(resolve); err != nil || !reflect.DeepEqual(again, conf) {
+		t.Errorf("round trip = %+v, %v, want %+v", again, err, conf)
+	}
+
+	errorCases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unknown type", "{type: NetworkDelayy}", "unknown chaos type 'NetworkDelayy'"},
+		{"unknown service", "{type: PodKill, app: ts-ordr-service, duration: 1}", "unknown app 'ts-ordr-service'"},
+		{"ambiguous", "{type: HTTPRequestAbort, app: ts-order-service, duration: 1}", "2 endpoint injection points match"},
+		{"wrong target key", "{type: PodKill, source: ts-order-service, duration: 1}", "'source' does not name app injection points"},
+		{"unknown parameter", "{type: PodKill, app: ts-order-service, duration: 1, latency: 5}", "unknown parameter 'latency'"},
+		{"missing parameter", "{type: PodKill, app: ts-order-service}", "missing parameter 'duration'"},
+		{"out of range", "{type: PodKill, app: ts-order-service, duration: 2h}", "120m is out of range [1m, 60m]"},
+		{"partial unit", "{type: PodKill, app: ts-order-service, duration: 90s}", "90s is not a whole number of m"},
+		{"unknown direction", "{type: NetworkLoss, source: ts-order-service, target: ts-travel-service, duration: 1, loss: 5, direction: up}", "unknown direction 'up'"},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := ParseReadableInjection([]byte(tc.in))
+			if err == nil {
+				_, err = r.toConf(resolve)
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// FirstCustomChaosType is the lowest value RegisterChaosType accepts, the values below are kept for
+// the built-in chaos types
+const FirstCustomChaosType ChaosType = 1000
+
+// ChaosTypeRegistration describes a fault to RegisterChaosType
+type ChaosTypeRegistration struct {
+	// Name identifies the chaos type in campaigns, stable injections and datasets, it must not change
+	Name string
+	// ID is the value of the chaos type in action nodes, which datasets store, so it must not change
+	// either. It is at least FirstCustomChaosType and unique among the registered chaos types.
+	ID ChaosType
+	// Spec is a pointer to the zero spec, e.g. &PodKillSpec{}. Its int fields use the same tags as
+	// the built-in specs, with Namespace and NamespaceTarget fields.
+	Spec Injection
+	// Range is the range of the spec fields an action sets, in the format of the range tag.
+	// Empty means every field.
+	Range string
+	// Groundtruth computes the groundtruth of a spec, nil uses the spec's GroundtruthProvider
+	Groundtruth func(spec Injection) (Groundtruth, error)
+	// DisplayConfig describes a spec for humans, nil uses InjectionConf.GetDisplayConfig
+	DisplayConfig func(spec Injection) (map[string]any, error)
+}
+
+type chaosTypeEntry struct {
+	ChaosTypeRegistration
+
+	id       ChaosType
+	specType reflect.Type
+	// builtin chaos types are held by the InjectionConf field of their name
+	builtin bool
+}
+
+var (
+	registryMu       sync.RWMutex
+	chaosTypesByID   = make(map[ChaosType]*chaosTypeEntry)
+	chaosTypesByName = make(map[string]*chaosTypeEntry)
+	chaosTypesBySpec = make(map[reflect.Type]*chaosTypeEntry)
+)
+
+// builtinChaosTypes maps the InjectionConf field of every built-in chaos type to its value
+var builtinChaosTypes = map[string]ChaosType{
+	"PodKill":                     PodKill,
+	"PodFailure":                  PodFailure,
+	"ContainerKill":               ContainerKill,
+	"MemoryStress":                MemoryStress,
+	"CPUStress":                   CPUStress,
+	"HTTPRequestAbort":            HTTPRequestAbort,
+	"HTTPResponseAbort":           HTTPResponseAbort,
+	"HTTPRequestDelay":            HTTPRequestDelay,
+	"HTTPResponseDelay":           HTTPResponseDelay,
+	"HTTPResponseReplaceBody":     HTTPResponseReplaceBody,
+	"HTTPResponsePatchBody":       HTTPResponsePatchBody,
+	"HTTPRequestReplacePath":      HTTPRequestReplacePath,
+	"HTTPRequestReplaceMethod":    HTTPRequestReplaceMethod,
+	"HTTPResponseReplaceCode":     HTTPResponseReplaceCode,
+	"DNSError":                    DNSError,
+	"DNSRandom":                   DNSRandom,
+	"TimeSkew":                    TimeSkew,
+	"NetworkDelay":                NetworkDelay,
+	"NetworkLoss":                 NetworkLoss,
+	"NetworkDuplicate":            NetworkDuplicate,
+	"NetworkCorrupt":              NetworkCorrupt,
+	"NetworkBandwidth":            NetworkBandwidth,
+	"NetworkPartition":            NetworkPartition,
+	"JVMLatency":                  JVMLatency,
+	"JVMReturn":                   JVMReturn,
+	"JVMException":                JVMException,
+	"JVMGarbageCollector":         JVMGarbageCollector,
+	"JVMCPUStress":                JVMCPUStress,
+	"JVMMemoryStress":             JVMMemoryStress,
+	"JVMMySQLLatency":             JVMMySQLLatency,
+	"JVMMySQLException":           JVMMySQLException,
+	"IODelay":                     IODelay,
+	"IOError":                     IOError,
+	"IOMistake":                   IOMistake,
+	"IOAttrOverride":              IOAttrOverride,
+	"KernelFault":                 KernelFault,
+	"BlockDelay":                  BlockDelay,
+	"PhysicalMachineCPUStress":    PhysicalMachineCPUStress,
+	"PhysicalMachineMemoryStress": PhysicalMachineMemoryStress,
+	"PhysicalMachineDiskFill":     PhysicalMachineDiskFill,
+}
+
+func init() {
+	confType := reflect.TypeOf(InjectionConf{})
+	for i := range confType.NumField() {
+		field := confType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		id, ok := builtinChaosTypes[field.Name]
+		if !ok {
+			panic(fmt.Sprintf("InjectionConf field %s has no built-in chaos type value", field.Name))
+		}
+
+		reg := ChaosTypeRegistration{
+			Name:  field.Name,
+			ID:    id,
+			Spec:  reflect.New(field.Type.Elem()).Interface().(Injection),
+			Range: field.Tag.Get("range"),
+		}
+		if _, err := registerChaosType(reg, true); err != nil {
+			panic(err)
+		}
+	}
+
+	if len(chaosTypesByID) != len(builtinChaosTypes) {
+		panic(fmt.Sprintf("%d built-in chaos types for %d InjectionConf fields", len(builtinChaosTypes), len(chaosTypesByID)))
+	}
+}
+
+// RegisterChaosType adds a fault to the action space of StructToNode, NodeToStruct and every
+// InjectionConf helper, under the value of the registration
+func RegisterChaosType(reg ChaosTypeRegistration) (ChaosType, error) {
+	return registerChaosType(reg, false)
+}
+
+// MustRegisterChaosType is RegisterChaosType panicking on an invalid registration
+func MustRegisterChaosType(reg ChaosTypeRegistration) ChaosType {
+	chaosType, err := RegisterChaosType(reg)
+	if err != nil {
+		panic(err)
+	}
+	return chaosType
+}
+
+// registerChaosType adds the chaos type under the value of the registration, only built-in chaos
+// types take values below FirstCustomChaosType
+func registerChaosType(reg ChaosTypeRegistration, builtin bool) (ChaosType, error) {
+	if reg.Name == "" {
+		return 0, fmt.Errorf("chaos type name is empty")
+	}
+	if !builtin && reg.ID < FirstCustomChaosType {
+		return 0, fmt.Errorf("chaos type %s: value %d is below FirstCustomChaosType %d", reg.Name, reg.ID, FirstCustomChaosType)
+	}
+
+	specType := reflect.TypeOf(reg.Spec)
+	if specType == nil || specType.Kind() != reflect.Ptr || specType.Elem().Kind() != reflect.Struct {
+		return 0, fmt.Errorf("chaos type %s: spec must be a pointer to a struct, got %v", reg.Name, specType)
+	}
+	specType = specType.Elem()
+
+	for _, name := range []string{KeyNamespace, KeyNamespaceTarget} {
+		if _, ok := specType.FieldByName(name); !ok {
+			return 0, fmt.Errorf("chaos type %s: spec %s has no %s field", reg.Name, specType.Name(), name)
+		}
+	}
+
+	if reg.Range == "" {
+		reg.Range = fmt.Sprintf("0-%d", specType.NumField()-1)
+	}
+	if _, _, err := parseRangeTag(reg.Range); err != nil {
+		return 0, fmt.Errorf("chaos type %s: %w", reg.Name, err)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := chaosTypesByName[reg.Name]; exists {
+		return 0, fmt.Errorf("chaos type %s is already registered", reg.Name)
+	}
+	if existing, exists := chaosTypesBySpec[specType]; exists {
+		return 0, fmt.Errorf("chaos type %s: spec %s is already registered by %s", reg.Name, specType.Name(), existing.Name)
+	}
+
+	if existing, exists := chaosTypesByID[reg.ID]; exists {
+		return 0, fmt.Errorf("chaos type %s: value %d is already registered by %s", reg.Name, reg.ID, existing.Name)
+	}
+
+	id := reg.ID
+	entry := &chaosTypeEntry{ChaosTypeRegistration: reg, id: id, specType: specType, builtin: builtin}
+	chaosTypesByID[id] = entry
+	chaosTypesByName[reg.Name] = entry
+	chaosTypesBySpec[specType] = entry
+
+	ChaosTypeMap[id] = reg.Name
+	SpecMap[id] = reflect.Zero(specType).Interface()
+	ChaosHandlers[id] = reg.Spec
+
+	return id, nil
+}
+
+// LookupChaosType returns the chaos type registered under the name
+func LookupChaosType(name string) (ChaosType, bool) {
+	entry, ok := chaosTypeByName(name)
+	if !ok {
+		return 0, false
+	}
+	return entry.id, true
+}
+
+// RegisteredChaosTypes returns every registered chaos type in order of value
+func RegisteredChaosTypes() []ChaosType {
+	entries := registeredChaosTypes()
+	result := make([]ChaosType, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.id)
+	}
+	return result
+}
+
+// NewInjectionConf returns the injection of the spec, which must be of a registered chaos type
+func NewInjectionConf(spec Injection) (*InjectionConf, error) {
+	entry, err := chaosTypeOfSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &InjectionConf{}
+	conf.setSpec(entry, reflect.ValueOf(spec))
+	return conf, nil
+}
+
+func chaosTypeByID(id int) (*chaosTypeEntry, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	entry, ok := chaosTypesByID[ChaosType(id)]
+	return entry, ok
+}
+
+func chaosTypeByName(name string) (*chaosTypeEntry, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	entry, ok := chaosTypesByName[name]
+	return entry, ok
+}
+
+func chaosTypeOfSpec(spec Injection) (*chaosTypeEntry, error) {
+	specType := reflect.TypeOf(spec)
+	if specType == nil || specType.Kind() != reflect.Ptr || reflect.ValueOf(spec).IsNil() {
+		return nil, fmt.Errorf("spec must be a non-nil pointer, got %T", spec)
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	entry, ok := chaosTypesBySpec[specType.Elem()]
+	if !ok {
+		return nil, fmt.Errorf("spec %T is not a registered chaos type", spec)
+	}
+	return entry, nil
+}
+
+func registeredChaosTypes() []*chaosTypeEntry {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	entries := make([]*chaosTypeEntry, 0, len(chaosTypesByID))
+	for _, entry := range chaosTypesByID {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	return entries
+}
+
+// field describes the chaos type like a field of InjectionConf to the Node conversions
+func (e *chaosTypeEntry) field() reflect.StructField {
+	return reflect.StructField{
+		Name: e.Name,
+		Type: reflect.PointerTo(e.specType),
+		Tag:  reflect.StructTag(fmt.Sprintf(`range:"%s"`, e.Range)),
+	}
+}
+
+// newSpec returns a pointer to a new zero spec of the chaos type
+func (e *chaosTypeEntry) newSpec() reflect.Value {
+	return reflect.New(e.specType)
+}
+
+func (e *chaosTypeEntry) groundtruth(spec Injection) (Groundtruth, error) {
+	if e.Groundtruth != nil {
+		return e.Groundtruth(spec)
+	}
+
+	provider, ok := spec.(GroundtruthProvider)
+	if !ok {
+		return Groundtruth{}, fmt.Errorf("injection does not support groundtruth calculation")
+	}
+	return provider.GetGroundtruth()
+}
+
+// activeChaosType returns the chaos type of the spec the injection holds
+func (ic *InjectionConf) activeChaosType() (*chaosTypeEntry, error) {
+	val := reflect.ValueOf(ic).Elem()
+	for i := range val.NumField() {
+		field := val.Type().Field(i)
+		if !field.IsExported() || val.Field(i).IsNil() {
+			continue
+		}
+
+		entry, ok := chaosTypeByName(field.Name)
+		if !ok {
+			return nil, fmt.Errorf("chaos type %s is not registered", field.Name)
+		}
+		return entry, nil
+	}
+
+	if ic.custom != nil {
+		return chaosTypeOfSpec(ic.custom)
+	}
+
+	return nil, fmt.Errorf("failed to get the non-empty injection")
+}
+
+// setSpec makes the spec pointer the injection of the conf
+func (ic *InjectionConf) setSpec(entry *chaosTypeEntry, spec reflect.Value) {
+	if entry.builtin {
+		reflect.ValueOf(ic).Elem().FieldByName(entry.Name).Set(spec)
+		return
+	}
+	ic.custom = spec.Interface().(Injection)
+}
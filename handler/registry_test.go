@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/client"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type registryTestSpec struct {
+	Duration        int `range:"1-5" description:"Time Unit Minute"`
+	Namespace       int `range:"0-0" dynamic:"true" description:"String"`
+	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+}
+
+func (s *registryTestSpec) Create(cli cli.Client, opts ...Option) (string, error) {
+	return "registry-test", nil
+}
+
+// unregisterChaosType removes a chaos type registered by a test so other tests see the built-ins only
+func unregisterChaosType(chaosType ChaosType) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	entry := chaosTypesByID[chaosType]
+	delete(chaosTypesByID, chaosType)
+	delete(chaosTypesByName, entry.Name)
+	delete(chaosTypesBySpec, entry.specType)
+	delete(ChaosTypeMap, chaosType)
+	delete(SpecMap, chaosType)
+	delete(ChaosHandlers, chaosType)
+}
+
+func TestRegisterChaosType(t *testing.T) {
+	oldPrefixs := NamespacePrefixs
+	NamespacePrefixs = []string{"regsys"}
+	t.Cleanup(func() { NamespacePrefixs = oldPrefixs })
+
+	if got, ok := LookupChaosType("NetworkPartition"); !ok || got != NetworkPartition || NetworkPartition != 22 {
+		t.Errorf("LookupChaosType(NetworkPartition) = %d, %v, want the stable value 22", got, ok)
+	}
+
+	chaosType, err := RegisterChaosType(ChaosTypeRegistration{
+		Name: "RegistryTest",
+		ID:   FirstCustomChaosType + 7,
+		Spec: &registryTestSpec{},
+		Groundtruth: func(spec Injection) (Groundtruth, error) {
+			return Groundtruth{Service: []string{"registry-test"}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterChaosType() error = %v", err)
+	}
+	t.Cleanup(func() { unregisterChaosType(chaosType) })
+
+	if chaosType != FirstCustomChaosType+7 || GetChaosTypeName(chaosType) != "RegistryTest" {
+		t.Errorf("RegisterChaosType() = %d (%s), want the value of the registration", chaosType, GetChaosTypeName(chaosType))
+	}
+	if _, err := RegisterChaosType(ChaosTypeRegistration{Name: "RegistryTest", ID: FirstCustomChaosType, Spec: &PodKillSpec{}}); err == nil {
+		t.Error("RegisterChaosType() accepted a duplicate name")
+	}
+	if _, err := RegisterChaosType(ChaosTypeRegistration{Name: "NoNamespace", ID: FirstCustomChaosType, Spec: &struct{ Injection }{}}); err == nil {
+		t.Error("RegisterChaosType() accepted a spec without namespace fields")
+	}
+	if _, err := RegisterChaosType(ChaosTypeRegistration{Name: "SameValue", ID: chaosType, Spec: &twoPointTestSpec{}}); err == nil {
+		t.Error("RegisterChaosType() accepted a value that is already registered")
+	}
+	if _, err := RegisterChaosType(ChaosTypeRegistration{Name: "BuiltinValue", ID: PodFailure, Spec: &twoPointTestSpec{}}); err == nil {
+		t.Error("RegisterChaosType() accepted a value below FirstCustomChaosType")
+	}
+
+	conf, err := NewInjectionConf(&registryTestSpec{Duration: 3})
+	if err != nil {
+		t.Fatalf("NewInjectionConf() error = %v", err)
+	}
+
+	node, err := InjectionConfToNode(conf)
+	if err != nil {
+		t.Fatalf("InjectionConfToNode() error = %v", err)
+	}
+	if node.Value != int(chaosType) {
+		t.Errorf("InjectionConfToNode() value = %d, want %d", node.Value, chaosType)
+	}
+
+	decoded, err := NodeToStruct[InjectionConf](node)
+	if err != nil {
+		t.Fatalf("NodeToStruct() error = %v", err)
+	}
+	spec, ok := decoded.custom.(*registryTestSpec)
+	if !ok || spec.Duration != 3 {
+		t.Fatalf("NodeToStruct() spec = %#v, want registryTestSpec with duration 3", decoded.custom)
+	}
+
+	stable, err := decoded.ToStable()
+	if err != nil {
+		t.Fatalf("ToStable() error = %v", err)
+	}
+	if stable.ChaosType != "RegistryTest" || stable.Params["Duration"] != 3 {
+		t.Errorf("ToStable() = %+v", stable)
+	}
+
+	gt, err := decoded.GetGroundtruth()
+	if err != nil || len(gt.Service) != 1 || gt.Service[0] != "registry-test" {
+		t.Errorf("GetGroundtruth() = %+v, %v, want the registered groundtruth", gt, err)
+	}
+
+	dryRun, err := client.NewDryRunClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, err := decoded.CreateWithClient(context.Background(), dryRun, 0, nil, nil); err != nil || name != "registry-test" {
+		t.Errorf("CreateWithClient() = %s, %v", name, err)
+	}
+}
+
+func TestBuiltinChaosTypes(t *testing.T) {
+	tests := []struct {
+		chaosType ChaosType
+		spec      Injection
+	}{
+		{PodKill, &PodKillSpec{}},
+		{PodFailure, &PodFailureSpec{}},
+		{ContainerKill, &ContainerKillSpec{}},
+		{MemoryStress, &MemoryStressChaosSpec{}},
+		{CPUStress, &CPUStressChaosSpec{}},
+		{HTTPRequestAbort, &HTTPRequestAbortSpec{}},
+		{HTTPResponseAbort, &HTTPResponseAbortSpec{}},
+		{HTTPRequestDelay, &HTTPRequestDelaySpec{}},
+		{HTTPResponseDelay, &HTTPResponseDelaySpec{}},
+		{HTTPResponseReplaceBody, &HTTPResponseReplaceBodySpec{}},
+		{HTTPResponsePatchBody, &HTTPResponsePatchBodySpec{}},
+		{HTTPRequestReplacePath, &HTTPRequestReplacePathSpec{}},
+		{HTTPRequestReplaceMethod, &HTTPRequestReplaceMethodSpec{}},
+		{HTTPResponseReplaceCode, &HTTPResponseReplaceCodeSpec{}},
+		{DNSError, &DNSErrorSpec{}},
+		{DNSRandom, &DNSRandomSpec{}},
+		{TimeSkew, &TimeSkewSpec{}},
+		{NetworkDelay, &NetworkDelaySpec{}},
+		{NetworkLoss, &NetworkLossSpec{}},
+		{NetworkDuplicate, &NetworkDuplicateSpec{}},
+		{NetworkCorrupt, &NetworkCorruptSpec{}},
+		{NetworkBandwidth, &NetworkBandwidthSpec{}},
+		{NetworkPartition, &NetworkPartitionSpec{}},
+		{JVMLatency, &JVMLatencySpec{}},
+		{JVMReturn, &JVMReturnSpec{}},
+		{JVMException, &JVMExceptionSpec{}},
+		{JVMGarbageCollector, &JVMGCSpec{}},
+		{JVMCPUStress, &JVMCPUStressSpec{}},
+		{JVMMemoryStress, &JVMMemoryStressSpec{}},
+		{JVMMySQLLatency, &JVMMySQLLatencySpec{}},
+		{JVMMySQLException, &JVMMySQLExceptionSpec{}},
+		{IODelay, &IODelaySpec{}},
+		{IOError, &IOErrorSpec{}},
+		{IOMistake, &IOMistakeSpec{}},
+		{IOAttrOverride, &IOAttrOverrideSpec{}},
+		{KernelFault, &KernelFaultSpec{}},
+		{BlockDelay, &BlockDelaySpec{}},
+		{PhysicalMachineCPUStress, &PhysicalMachineCPUStressSpec{}},
+		{PhysicalMachineMemoryStress, &PhysicalMachineMemoryStressSpec{}},
+		{PhysicalMachineDiskFill, &PhysicalMachineDiskFillSpec{}},
+	}
+
+	if len(tests) != len(RegisteredChaosTypes()) {
+		t.Errorf("%d built-in chaos types are tested, %d are registered", len(tests), len(RegisteredChaosTypes()))
+	}
+	for _, tt := range tests {
+		conf, err := NewInjectionConf(tt.spec)
+		if err != nil {
+			t.Errorf("NewInjectionConf(%T) error = %v", tt.spec, err)
+			continue
+		}
+
+		node, err := InjectionConfToNode(conf)
+		if err != nil {
+			t.Errorf("InjectionConfToNode(%T) error = %v", tt.spec, err)
+			continue
+		}
+		if node.Value != int(tt.chaosType) {
+			t.Errorf("%T is registered as %d, want %d", tt.spec, node.Value, tt.chaosType)
+		}
+
+		name := GetChaosTypeName(tt.chaosType)
+		if field, ok := reflect.TypeOf(InjectionConf{}).FieldByName(name); !ok || field.Type != reflect.TypeOf(tt.spec) {
+			t.Errorf("chaos type %d is named %s, want the InjectionConf field of %T", tt.chaosType, name, tt.spec)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSamplerAttempts is how many duplicate or excluded draws in a row a Sampler accepts before
+// giving up
+const DefaultSamplerAttempts = 1000
+
+// ErrSpaceExhausted is returned when the sampler cannot draw another action satisfying its constraints
+var ErrSpaceExhausted = errors.New("no more actions satisfy the sampler constraints")
+
+type SamplerConf struct {
+	Seed     int64
+	Weights  map[string]float64
+	Excluded []string
+	// ExcludedValues maps "ChaosType.Field" or ".Field" for every chaos type to the excluded values
+	ExcludedValues map[string][]int
+	Unique         bool
+	// OptionalRate is the probability that a field tagged optional is left out
+	OptionalRate float64
+	Attempts     int
+}
+
+type SamplerOption func(*SamplerConf)
+
+// WithSamplerSeed makes the sampler draw the same actions from the same action space
+func WithSamplerSeed(seed int64) SamplerOption {
+	return func(c *SamplerConf) {
+		c.Seed = seed
+	}
+}
+
+// WithChaosTypeWeights sets the relative weight of chaos types by their registered name,
+// chaos types without a weight count 1
+func WithChaosTypeWeights(weights map[string]float64) SamplerOption {
+	return func(c *SamplerConf) {
+		for chaosType, weight := range weights {
+			c.Weights[chaosType] = weight
+		}
+	}
+}
+
+// WithExcludedChaosTypes never draws the chaos types
+func WithExcludedChaosTypes(chaosTypes ...string) SamplerOption {
+	return func(c *SamplerConf) {
+		c.Excluded = append(c.Excluded, chaosTypes...)
+	}
+}
+
+// WithExcludedValues never draws the values for the field of the chaos type, an empty chaos type
+// excludes them from the field of every chaos type, e.g. WithExcludedValues("", KeyApp, 0)
+func WithExcludedValues(chaosType, field string, values ...int) SamplerOption {
+	return func(c *SamplerConf) {
+		key := chaosType + "." + field
+		c.ExcludedValues[key] = append(c.ExcludedValues[key], values...)
+	}
+}
+
+// WithUniqueActions never draws the same action twice from the sampler
+func WithUniqueActions() SamplerOption {
+	return func(c *SamplerConf) {
+		c.Unique = true
+	}
+}
+
+// WithOptionalRate leaves out fields tagged optional with the probability
+func WithOptionalRate(rate float64) SamplerOption {
+	return func(c *SamplerConf) {
+		c.OptionalRate = rate
+	}
+}
+
+// WithSamplerAttempts sets how many rejected draws in a row end sampling with ErrSpaceExhausted
+func WithSamplerAttempts(attempts int) SamplerOption {
+	return func(c *SamplerConf) {
+		c.Attempts = attempts
+	}
+}
+
+// Sampler draws random valid actions from an InjectionConf action space built by StructToNode.
+// Draws only depend on the seed and the action space, so a dataset can be regenerated exactly.
+type Sampler struct {
+	conf SamplerConf
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	seen map[string]bool
+}
+
+// NewSampler returns a sampler, seeded from the clock unless WithSamplerSeed is given
+func NewSampler(opts ...SamplerOption) *Sampler {
+	conf := SamplerConf{
+		Seed:           time.Now().UnixNano(),
+		Weights:        make(map[string]float64),
+		ExcludedValues: make(map[string][]int),
+		Attempts:       DefaultSamplerAttempts,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return &Sampler{
+		conf: conf,
+		rand: rand.New(rand.NewSource(conf.Seed)),
+		seen: make(map[string]bool),
+	}
+}
+
+// Sample draws one action from the space without modifying it. The chaos type is drawn by weight,
+// then every field without a preset value, e.g. Namespace, is drawn uniformly from its range.
+func (s *Sampler) Sample(space *Node) (*Node, error) {
+	actions, err := s.SampleBatch(space, 1)
+	if err != nil {
+		return nil, err
+	}
+	return actions[0], nil
+}
+
+// SampleBatch draws n actions from the space, distinct from each other and from earlier draws with
+// WithUniqueActions
+func (s *Sampler) SampleBatch(space *Node, n int) ([]*Node, error) {
+	candidates, weights, err := s.chaosTypes(space)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	actions := make([]*Node, 0, n)
+	for rejected := 0; len(actions) < n; {
+		if rejected >= s.conf.Attempts {
+			return actions, fmt.Errorf("%w: drew %d of %d actions", ErrSpaceExhausted, len(actions), n)
+		}
+
+		idx := candidates[s.pick(weights)]
+		key := strconv.Itoa(idx)
+		spec := cloneNode(space.Children[key])
+		entry, _ := chaosTypeByID(idx)
+
+		if !s.fill(spec, entry.specType) {
+			rejected++
+			continue
+		}
+
+		action := &Node{Name: space.Name, Value: idx, Children: map[string]*Node{key: spec}}
+		if s.conf.Unique {
+			id := actionKey(action)
+			if s.seen[id] {
+				rejected++
+				continue
+			}
+			s.seen[id] = true
+		}
+
+		rejected = 0
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// chaosTypes returns the InjectionConf field indices of the space that may be drawn, in field order,
+// with their weights
+func (s *Sampler) chaosTypes(space *Node) ([]int, []float64, error) {
+	if space == nil || len(space.Children) == 0 {
+		return nil, nil, fmt.Errorf("empty action space")
+	}
+
+	var indices []int
+	var weights []float64
+	for _, key := range sortedChildKeys(space) {
+		idx, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid chaos type key '%s' in action space", key)
+		}
+
+		entry, ok := chaosTypeByID(idx)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid chaos type key '%s' in action space", key)
+		}
+
+		name := entry.Name
+		if slices.Contains(s.conf.Excluded, name) {
+			continue
+		}
+
+		weight, ok := s.conf.Weights[name]
+		if !ok {
+			weight = 1
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		indices = append(indices, idx)
+		weights = append(weights, weight)
+	}
+
+	if len(indices) == 0 {
+		return nil, nil, fmt.Errorf("%w: every chaos type is excluded", ErrSpaceExhausted)
+	}
+	return indices, weights, nil
+}
+
+// pick returns an index drawn with the weights
+func (s *Sampler) pick(weights []float64) int {
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+
+	r := s.rand.Float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			return i
+		}
+		r -= weight
+	}
+	return len(weights) - 1
+}
+
+// fill draws the fields of a spec node in field order and reports false when a drawn value is excluded
+func (s *Sampler) fill(spec *Node, specType reflect.Type) bool {
+	for _, key := range sortedChildKeys(spec) {
+		idx, err := strconv.Atoi(key)
+		if err != nil || idx < 0 || idx >= specType.NumField() {
+			return false
+		}
+		child := spec.Children[key]
+		field := specType.Field(idx)
+
+		if field.Tag.Get("optional") == "true" && s.rand.Float64() < s.conf.OptionalRate {
+			delete(spec.Children, key)
+			continue
+		}
+
+		if child.Value != ValueNotSet || len(child.Range) != 2 || child.Range[0] > child.Range[1] {
+			continue
+		}
+
+		child.Value = child.Range[0] + s.rand.Intn(child.Range[1]-child.Range[0]+1)
+		if slices.Contains(s.conf.ExcludedValues["."+field.Name], child.Value) ||
+			slices.Contains(s.conf.ExcludedValues[spec.Name+"."+field.Name], child.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// actionKey identifies an action by its chaos type and field values
+func actionKey(action *Node) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(action.Value))
+	for _, spec := range action.Children {
+		for _, key := range sortedChildKeys(spec) {
+			fmt.Fprintf(&b, ",%s=%d", key, spec.Children[key].Value)
+		}
+	}
+	return b.String()
+}
+
+func cloneNode(n *Node) *Node {
+	clone := *n
+	clone.Range = slices.Clone(n.Range)
+	if n.Children != nil {
+		clone.Children = make(map[string]*Node, len(n.Children))
+		for key, child := range n.Children {
+			clone.Children[key] = cloneNode(child)
+		}
+	}
+	return &clone
+}
+
+// sortedChildKeys returns the child keys of the node in numeric order
+func sortedChildKeys(n *Node) []string {
+	keys := make([]string, 0, len(n.Children))
+	for key := range n.Children {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return keys[i] < keys[j]
+		}
+		return a < b
+	})
+	return keys
+}
+
+// specRand returns a random source seeded from the spec's values, so the values a spec draws when
+// it is created, e.g. a random JVM exception, are the same for the same action
+func specRand(spec any) *rand.Rand {
+	val := reflect.Indirect(reflect.ValueOf(spec))
+	h := fnv.New64a()
+	h.Write([]byte(val.Type().Name()))
+	for i := range val.NumField() {
+		if val.Type().Field(i).Name == KeyNamespaceTarget {
+			continue
+		}
+		fmt.Fprintf(h, ",%v", val.Field(i).Interface())
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// randomString returns a string of lowercase letters and digits drawn from r
+func randomString(r *rand.Rand, length int) string {
+	const alphabet = "bcdfghjklmnpqrstvwxz2456789"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSampler(t *testing.T) {
+	fieldKey := func(name string) string {
+		field, _ := reflect.TypeOf(InjectionConf{}).FieldByName(name)
+		return strconv.Itoa(field.Index[0])
+	}
+	spec := func(name string) *Node {
+		return &Node{Name: name, Children: map[string]*Node{
+			"0": {Name: "Duration", Range: []int{1, 2}, Value: ValueNotSet},
+			"1": {Name: KeyNamespace, Range: []int{0, 0}, Value: 0},
+			"2": {Name: KeyApp, Range: []int{0, 1}, Value: ValueNotSet},
+		}}
+	}
+	space := &Node{Name: "InjectionConf", Children: map[string]*Node{
+		fieldKey("PodKill"):    spec("PodKill"),
+		fieldKey("PodFailure"): spec("PodFailure"),
+	}}
+
+	first, err := NewSampler(WithSamplerSeed(3)).SampleBatch(space, 20)
+	if err != nil {
+		t.Fatalf("SampleBatch() error = %v", err)
+	}
+	second, err := NewSampler(WithSamplerSeed(3)).SampleBatch(space, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("SampleBatch() with the same seed drew different actions")
+	}
+	for _, action := range first {
+		conf, err := decodeInjectionNode(action)
+		if err != nil {
+			t.Fatalf("decodeInjectionNode() error = %v", err)
+		}
+		if conf.PodKill == nil && conf.PodFailure == nil {
+			t.Errorf("action %+v is outside of the space", conf)
+		}
+	}
+	if space.Children[fieldKey("PodKill")].Children["0"].Value != ValueNotSet {
+		t.Error("SampleBatch() modified the action space")
+	}
+
+	sampler := NewSampler(
+		WithChaosTypeWeights(map[string]float64{"PodFailure": 0}),
+		WithExcludedValues("", KeyApp, 0),
+		WithUniqueActions(),
+		WithSamplerAttempts(100),
+	)
+	actions, err := sampler.SampleBatch(space, 3)
+	if !errors.Is(err, ErrSpaceExhausted) || len(actions) != 2 {
+		t.Fatalf("SampleBatch() = %d actions, error = %v, want the 2 distinct PodKill actions and ErrSpaceExhausted", len(actions), err)
+	}
+	for _, action := range actions {
+		conf, _ := decodeInjectionNode(action)
+		if conf.PodKill == nil || conf.PodKill.AppIdx != 1 {
+			t.Errorf("action = %+v, want PodKill on app 1", conf)
+		}
+	}
+
+	if _, err := NewSampler(WithExcludedChaosTypes("PodKill", "PodFailure")).Sample(space); !errors.Is(err, ErrSpaceExhausted) {
+		t.Errorf("Sample() with every chaos type excluded error = %v", err)
+	}
+
+	exception := &JVMExceptionSpec{MethodIdx: 4, ExceptionOpt: 1}
+	if specRand(exception).Int63() != specRand(&JVMExceptionSpec{MethodIdx: 4, ExceptionOpt: 1, NamespaceTarget: 2}).Int63() {
+		t.Error("specRand() differs for the same action in another target namespace")
+	}
+}
@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDialect is the JSON Schema version of InjectionSchema
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema, also valid as an OpenAPI 3.1 schema object, describing the
+// InjectionConf action space. Spec fields are integers, enum fields list their values in oneOf with
+// a title each.
+type Schema struct {
+	Dialect              string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	Const                *int               `json:"const,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// schemaEnumTypes names the values of the enum spec field types
+var schemaEnumTypes = map[reflect.Type]map[int]string{
+	reflect.TypeOf(HTTPMethod(0)):      enumLabels(httpMethodMap),
+	reflect.TypeOf(HTTPStatusCode(0)):  enumLabels(httpStatusCodeMap),
+	reflect.TypeOf(JVMReturnType(0)):   {int(StringReturn): "String", int(IntReturn): "Int"},
+	reflect.TypeOf(JVMMemoryType(0)):   {int(HeapMemory): "Heap", int(StackMemory): "Stack"},
+	reflect.TypeOf(ReplaceBodyType(0)): {int(EmptyBody): "Empty", int(RandomBody): "Random"},
+}
+
+// schemaEnumFields names the values of plain int spec fields by field name
+var schemaEnumFields = map[string]map[int]string{
+	"Direction": invertLabels(readableDirections),
+	"Mode":      invertLabels(readablePodModes),
+}
+
+func enumLabels[K ~int, V any](m map[K]V) map[int]string {
+	labels := make(map[int]string, len(m))
+	for value, label := range m {
+		labels[int(value)] = fmt.Sprint(label)
+	}
+	return labels
+}
+
+func invertLabels(m map[string]int) map[int]string {
+	labels := make(map[int]string, len(m))
+	for label, value := range m {
+		labels[value] = label
+	}
+	return labels
+}
+
+// SpecSchemas returns the schema of every registered chaos type with an injection point in the
+// system of the namespace prefix, by chaos type name. Dynamic ranges are resolved against the
+// current catalogs and the injection point values are titled with their content.
+func SpecSchemas(namespacePrefix string) (map[string]*Schema, error) {
+	nsIdx := -1
+	for idx, prefix := range NamespacePrefixs {
+		if prefix == namespacePrefix {
+			nsIdx = idx
+		}
+	}
+	if nsIdx < 0 {
+		return nil, fmt.Errorf("namespace prefix %s is not configured (available: %v)", namespacePrefix, NamespacePrefixs)
+	}
+
+	rootNode := &Node{}
+	setNodeNsPrefix(rootNode, namespacePrefix)
+	defer deleteNodeNsPrefix(rootNode)
+
+	result := make(map[string]*Schema)
+	for _, entry := range registeredChaosTypes() {
+		_, maxField, err := parseRangeTag(entry.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		spec := &Schema{
+			Title:                entry.Name,
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: new(bool),
+		}
+
+		empty := false
+		for i := range entry.specType.NumField() {
+			field := entry.specType.Field(i)
+			optional := field.Tag.Get("optional") == "true"
+			if field.Name == KeyNamespaceTarget || i > maxField {
+				continue
+			}
+
+			start, end, err := getValueRange(field, rootNode)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", entry.Name, field.Name, err)
+			}
+			if end < start {
+				empty = true
+				break
+			}
+
+			labels := schemaEnumFields[field.Name]
+			if labels == nil {
+				labels = schemaEnumTypes[field.Type]
+			}
+
+			switch kind, isPoint := injectionPointKinds[field.Name]; {
+			case field.Name == KeyNamespace:
+				start, end = nsIdx, nsIdx
+				labels = map[int]string{nsIdx: namespacePrefix}
+			case isPoint:
+				system, err := currentSystem(namespacePrefix, kind)
+				if err != nil {
+					return nil, err
+				}
+
+				targets, err := readableTargets(system, kind)
+				if err != nil {
+					return nil, err
+				}
+
+				labels = make(map[int]string, len(targets))
+				for idx, target := range targets {
+					labels[idx] = describeTarget(target, readableTargetKeys[kind])
+				}
+			}
+
+			spec.Properties[field.Name] = fieldSchema(field, start, end, labels)
+			if !optional {
+				spec.Required = append(spec.Required, field.Name)
+			}
+		}
+
+		if !empty {
+			result[entry.Name] = spec
+		}
+	}
+
+	return result, nil
+}
+
+func fieldSchema(field reflect.StructField, start, end int, labels map[int]string) *Schema {
+	schema := &Schema{
+		Title:       field.Name,
+		Description: field.Tag.Get("description"),
+		Type:        "integer",
+		Minimum:     &start,
+		Maximum:     &end,
+	}
+
+	for value := start; value <= end && labels != nil; value++ {
+		if label, ok := labels[value]; ok {
+			schema.OneOf = append(schema.OneOf, &Schema{Const: &value, Title: label})
+		}
+	}
+	return schema
+}
+
+// InjectionSchema returns the JSON Schema of an InjectionConf of the system in its value form,
+// e.g. {"PodKill": {"Duration": 5, "Namespace": 0, "AppIdx": 2}}, with the specs in $defs
+func InjectionSchema(namespacePrefix string) (*Schema, error) {
+	specs, err := SpecSchemas(namespacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := injectionConfSchema(specs, "#/$defs/")
+	schema.Dialect = JSONSchemaDialect
+	schema.Defs = specs
+	return schema, nil
+}
+
+// OpenAPIComponents returns the schemas of the system to put under components.schemas of an
+// OpenAPI 3.1 document: every spec and InjectionConf referencing them
+func OpenAPIComponents(namespacePrefix string) (map[string]*Schema, error) {
+	specs, err := SpecSchemas(namespacePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make(map[string]*Schema, len(specs)+1)
+	for name, spec := range specs {
+		components[name] = spec
+	}
+	components[reflect.TypeOf(InjectionConf{}).Name()] = injectionConfSchema(specs, "#/components/schemas/")
+	return components, nil
+}
+
+func injectionConfSchema(specs map[string]*Schema, refPrefix string) *Schema {
+	one := 1
+	schema := &Schema{
+		Title:                reflect.TypeOf(InjectionConf{}).Name(),
+		Description:          "Exactly one chaos type with its spec",
+		Type:                 "object",
+		Properties:           make(map[string]*Schema, len(specs)),
+		AdditionalProperties: new(bool),
+		MinProperties:        &one,
+		MaxProperties:        &one,
+	}
+	for name := range specs {
+		schema.Properties[name] = &Schema{Ref: refPrefix + name}
+	}
+	return schema
+}
+
+// ValidateNode checks an InjectionConf value node, as built by InjectionConfToNode or sent by a
+// client, against the schema of InjectionSchema
+func (s *Schema) ValidateNode(n *Node) error {
+	if n == nil {
+		return fmt.Errorf("input node is nil")
+	}
+
+	entry, ok := chaosTypeByID(n.Value)
+	if !ok {
+		return fmt.Errorf("invalid chaos type %d (registered: %v)", n.Value, RegisteredChaosTypes())
+	}
+
+	specNode, ok := n.Children[strconv.Itoa(n.Value)]
+	if !ok || specNode == nil {
+		return fmt.Errorf("expected child key '%d' not found in node children", n.Value)
+	}
+
+	spec := make(map[string]any, len(specNode.Children))
+	for key, child := range specNode.Children {
+		idx, err := strconv.Atoi(key)
+		if err != nil || idx < 0 || idx >= entry.specType.NumField() {
+			return fmt.Errorf("invalid child key '%s' for %s", key, entry.Name)
+		}
+		spec[entry.specType.Field(idx).Name] = child.Value
+	}
+
+	return s.Validate(map[string]any{entry.Name: spec})
+}
+
+// Validate checks a decoded JSON value against the schema and reports every violation
+func (s *Schema) Validate(value any) error {
+	return errors.Join(s.validate(s, value, "")...)
+}
+
+func (s *Schema) validate(root *Schema, value any, path string) []error {
+	if s.Ref != "" {
+		def, ok := root.Defs[s.Ref[strings.LastIndex(s.Ref, "/")+1:]]
+		if !ok {
+			return []error{fmt.Errorf("%s: unresolved reference %s", displayPath(path), s.Ref)}
+		}
+		return def.validate(root, value, path)
+	}
+
+	switch s.Type {
+	case "object":
+		return s.validateObject(root, value, path)
+	case "integer":
+		return s.validateInteger(value, path)
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(root *Schema, value any, path string) []error {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("%s: %v is not an object", displayPath(path), value)}
+	}
+
+	var errs []error
+	if s.MinProperties != nil && len(object) < *s.MinProperties {
+		errs = append(errs, fmt.Errorf("%s: has %d properties, want at least %d", displayPath(path), len(object), *s.MinProperties))
+	}
+	if s.MaxProperties != nil && len(object) > *s.MaxProperties {
+		errs = append(errs, fmt.Errorf("%s: has %d properties, want at most %d", displayPath(path), len(object), *s.MaxProperties))
+	}
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: missing required property %s", displayPath(path), name))
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		property, ok := s.Properties[key]
+		switch {
+		case ok:
+			errs = append(errs, property.validate(root, object[key], joinPath(path, key))...)
+		case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+			errs = append(errs, fmt.Errorf("%s: unknown property %s", displayPath(path), key))
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) validateInteger(value any, path string) []error {
+	var number int
+	switch v := value.(type) {
+	case int:
+		number = v
+	case float64:
+		if v != math.Trunc(v) {
+			return []error{fmt.Errorf("%s: %v is not an integer", displayPath(path), v)}
+		}
+		number = int(v)
+	default:
+		return []error{fmt.Errorf("%s: %v is not an integer", displayPath(path), value)}
+	}
+
+	switch {
+	case s.Minimum != nil && number < *s.Minimum:
+		return []error{fmt.Errorf("%s: %d is less than the minimum %d", displayPath(path), number, *s.Minimum)}
+	case s.Maximum != nil && number > *s.Maximum:
+		return []error{fmt.Errorf("%s: %d is greater than the maximum %d", displayPath(path), number, *s.Maximum)}
+	}
+
+	if len(s.OneOf) == 0 {
+		return nil
+	}
+	for _, option := range s.OneOf {
+		if option.Const != nil && *option.Const == number {
+			return nil
+		}
+	}
+	return []error{fmt.Errorf("%s: %d is not one of the allowed values", displayPath(path), number)}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
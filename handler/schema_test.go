@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/resourcelookup"
+)
+
+func TestInjectionSchema(t *testing.T) {
+	useTestSystem(t, "schemasys", 1, newTestClient(t, testPod("schemasys0", "cart"), testPod("schemasys0", "frontend")))
+	// schemasys is the second system, restored with the rest of the configuration
+	NamespacePrefixs = []string{"othersys", "schemasys"}
+	NamespaceTargetMap = map[string]int{"othersys": 1, "schemasys": 1}
+	resourcelookup.RegisterCatalog("schemasys", &resourcelookup.StaticCatalog{
+		EndpointList: []resourcelookup.AppEndpointPair{
+			{AppName: "frontend", Method: "GET", Route: "/cart", ServerAddress: "cart", ServerPort: "7070"},
+		},
+		NetworkPairList: []resourcelookup.AppNetworkPair{
+			{SourceService: "frontend", TargetService: "cart"},
+		},
+	})
+
+	schema, err := InjectionSchema("schemasys")
+	if err != nil {
+		t.Fatalf("InjectionSchema() error = %v", err)
+	}
+
+	podKill := schema.Defs["PodKill"]
+	if podKill == nil || schema.Properties["PodKill"].Ref != "#/$defs/PodKill" {
+		t.Fatalf("InjectionSchema() has no PodKill definition")
+	}
+	if _, ok := schema.Defs["JVMLatency"]; ok {
+		t.Error("InjectionSchema() has JVMLatency without any JVM method in the system")
+	}
+
+	titles := func(s *Schema) string {
+		var result []string
+		for _, option := range s.OneOf {
+			result = append(result, option.Title)
+		}
+		return strings.Join(result, ",")
+	}
+	if got := titles(podKill.Properties[KeyApp]); got != "app=cart,app=frontend" {
+		t.Errorf("AppIdx titles = %s", got)
+	}
+	if got := titles(podKill.Properties[KeyNamespace]); got != "schemasys" || *podKill.Properties[KeyNamespace].Minimum != 1 {
+		t.Errorf("Namespace titles = %s, minimum %d, want only schemasys at 1", got, *podKill.Properties[KeyNamespace].Minimum)
+	}
+	if got := titles(schema.Defs["NetworkDelay"].Properties["Direction"]); got != "to,from,both,call" {
+		t.Errorf("Direction titles = %s", got)
+	}
+	if got := titles(schema.Defs["HTTPRequestReplaceMethod"].Properties["ReplaceMethod"]); got != "GET,POST,PUT,DELETE,HEAD,OPTIONS,PATCH" {
+		t.Errorf("ReplaceMethod titles = %s", got)
+	}
+
+	components, err := OpenAPIComponents("schemasys")
+	if err != nil {
+		t.Fatalf("OpenAPIComponents() error = %v", err)
+	}
+	if ref := components["InjectionConf"].Properties["PodKill"].Ref; ref != "#/components/schemas/PodKill" {
+		t.Errorf("InjectionConf PodKill ref = %s", ref)
+	}
+
+	node, err := InjectionConfToNode(&InjectionConf{PodKill: &PodKillSpec{Duration: 5, Namespace: 1, AppIdx: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.ValidateNode(node); err != nil {
+		t.Errorf("ValidateNode() error = %v", err)
+	}
+
+	node.Children["0"].Children["0"].Value = 90
+	node.Children["0"].Children["2"].Value = 2
+	err = schema.ValidateNode(node)
+	for _, want := range []string{"PodKill.Duration: 90 is greater than the maximum 60", "PodKill.AppIdx: 2 is greater than the maximum 1"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateNode() error = %v, want it to contain %q", err, want)
+		}
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(`{"PodKill": {"Duration": 5, "Namespace": 1, "AppIdx": 0}, "PodFailure": {}}`), &value); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate(value); err == nil || !strings.Contains(err.Error(), "want at most 1") {
+		t.Errorf("Validate() of two chaos types error = %v", err)
+	}
+}
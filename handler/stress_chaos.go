@@ -18,6 +18,8 @@ type CPUStressChaosSpec struct {
 	CPULoad         int `range:"1-100" description:"CPU Load Percentage"`
 	CPUWorker       int `range:"1-3" description:"CPU Stress Threads"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *CPUStressChaosSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -65,7 +67,12 @@ func (s *CPUStressChaosSpec) Create(cli cli.Client, opts ...Option) (string, err
 		s.CPULoad,
 		s.CPUWorker,
 	)
-	return controllers.CreateStressChaosWithContainer(cli, ctx, ns, appName, stressors, "cpu-exhaustion", duration, annotations, labels, []string{containerName})
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreateStressChaosWithContainer(cli, ctx, ns, appName, stressors, "cpu-exhaustion", duration, annotations, labels, []string{containerName}, selector...)
 }
 
 type MemoryStressChaosSpec struct {
@@ -75,6 +82,8 @@ type MemoryStressChaosSpec struct {
 	MemorySize      int `range:"1-1024" description:"Memory Size Unit MB"`
 	MemWorker       int `range:"1-4" description:"Memory Stress Threads"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *MemoryStressChaosSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -122,5 +131,10 @@ func (s *MemoryStressChaosSpec) Create(cli cli.Client, opts ...Option) (string,
 		strconv.Itoa(s.MemorySize)+"MiB",
 		s.MemWorker,
 	)
-	return controllers.CreateStressChaosWithContainer(cli, ctx, ns, appName, stressors, "memory-exhaustion", duration, annotations, labels, []string{containerName})
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreateStressChaosWithContainer(cli, ctx, ns, appName, stressors, "memory-exhaustion", duration, annotations, labels, []string{containerName}, selector...)
 }
@@ -17,6 +17,8 @@ type TimeSkewSpec struct {
 	ContainerIdx    int `range:"0-0" dynamic:"true" description:"Container Index"`
 	TimeOffset      int `range:"-600-600" description:"Time offset in seconds"`
 	NamespaceTarget int `range:"0-0" dynamic:"true" description:"Namespace Target Index (0-based)"`
+	Mode            int `range:"0-4" optional:"true" description:"Pod Mode (0 all, 1 one, 2 fixed, 3 fixed-percent, 4 random-max-percent)"`
+	ModeValue       int `range:"1-100" optional:"true" description:"Pod Count for fixed, Percentage for the percent modes"`
 }
 
 func (s *TimeSkewSpec) Create(cli cli.Client, opts ...Option) (string, error) {
@@ -62,5 +64,10 @@ func (s *TimeSkewSpec) Create(cli cli.Client, opts ...Option) (string, error) {
 	// Format the TimeOffset with "s" unit
 	timeOffset := fmt.Sprintf("%ds", s.TimeOffset)
 
-	return controllers.CreateTimeChaosWithContainer(cli, ctx, ns, appName, timeOffset, duration, annotations, labels, []string{containerName})
+	selector, err := podSelection{PodMode(s.Mode), s.ModeValue}.selector(appName)
+	if err != nil {
+		return "", err
+	}
+
+	return controllers.CreateTimeChaosWithContainer(cli, ctx, ns, appName, timeOffset, duration, annotations, labels, []string{containerName}, selector...)
 }
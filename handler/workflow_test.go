@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/controllers"
+	chaosmeshv1alpha1 "github.com/chaos-mesh/chaos-mesh/api/v1alpha1"
+	cli "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestWorkflowBuilder(t *testing.T) {
+	fakeClient := newTestClient(t, testPod("flowsys0", "frontend"))
+	useTestSystem(t, "flowsys", 1, fakeClient)
+
+	ctx := context.Background()
+	podFailure, err := (&InjectionConf{PodFailure: &PodFailureSpec{Duration: 2}}).BuildObject(ctx, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildObject() error = %v", err)
+	}
+	cpuStress, err := (&InjectionConf{CPUStress: &CPUStressChaosSpec{Duration: 3, CPULoad: 50, CPUWorker: 1}}).BuildObject(ctx, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildObject() error = %v", err)
+	}
+
+	name, err := controllers.NewWorkflowBuilder("flowsys0", "nested").
+		Serial("entry", "30m", "faults", "cooldown").
+		Parallel("faults", "10m", "pod-failure", "cpu-stress").
+		Chaos("pod-failure", podFailure, "").
+		Chaos("cpu-stress", cpuStress, "").
+		Suspend("cooldown", "5m").
+		Submit(fakeClient, ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	workflow := &chaosmeshv1alpha1.Workflow{}
+	if err := fakeClient.Get(ctx, cli.ObjectKey{Namespace: "flowsys0", Name: name}, workflow); err != nil {
+		t.Fatal(err)
+	}
+	if workflow.Spec.Entry != "entry" || len(workflow.Spec.Templates) != 5 {
+		t.Fatalf("workflow spec = %+v, want 5 templates starting at entry", workflow.Spec)
+	}
+
+	pod := workflow.Spec.Templates[2]
+	if pod.Type != chaosmeshv1alpha1.TypePodChaos || pod.PodChaos == nil || pod.PodChaos.Duration != nil {
+		t.Errorf("pod-failure template = %+v, want a PodChaos without duration", pod)
+	}
+	if pod.Deadline == nil || *pod.Deadline != "2m" {
+		t.Errorf("pod-failure deadline = %v, want the duration 2m of the injection", pod.Deadline)
+	}
+}
@@ -0,0 +1,290 @@
+package networkdependencies
+
+import (
+	"net"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CallKind is the protocol of a call between two services
+type CallKind string
+
+const (
+	CallHTTP     CallKind = "http"
+	CallDatabase CallKind = "database"
+	CallMQ       CallKind = "mq"
+	CallTCP      CallKind = "tcp"
+)
+
+// databasePorts and mqPorts classify calls without a route by the port they connect to
+var (
+	databasePorts = map[string]bool{"3306": true, "5432": true, "6379": true, "27017": true, "9042": true}
+	mqPorts       = map[string]bool{"5672": true, "9092": true, "4222": true, "1883": true}
+)
+
+// Call is one kind of request the source service sends to the target service
+type Call struct {
+	Kind   CallKind `json:"kind"`
+	Method string   `json:"method,omitempty"`
+	Route  string   `json:"route,omitempty"`
+	Port   string   `json:"port,omitempty"`
+}
+
+// Edge is the directed dependency of the source service (the caller) on the target service (the callee)
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Calls  []Call `json:"calls"`
+	// Frequency is the sum of the known call rates in calls per second, 0 when unknown
+	Frequency float64 `json:"frequency,omitempty"`
+}
+
+// Kinds returns the distinct call kinds of the edge
+func (e *Edge) Kinds() []CallKind {
+	var kinds []CallKind
+	for _, call := range e.Calls {
+		if !slices.Contains(kinds, call.Kind) {
+			kinds = append(kinds, call.Kind)
+		}
+	}
+	return kinds
+}
+
+// Ports returns the distinct ports the source connects to on the target
+func (e *Edge) Ports() []string {
+	var ports []string
+	for _, call := range e.Calls {
+		if call.Port != "" && !slices.Contains(ports, call.Port) {
+			ports = append(ports, call.Port)
+		}
+	}
+	return ports
+}
+
+// Graph is a directed graph of calls between services. Unlike the dependency graph behind
+// GetDependenciesForService, an edge only goes from the caller to the callee.
+type Graph struct {
+	edges   map[string]map[string]*Edge
+	callers map[string][]string
+}
+
+// NewGraph returns an empty graph
+func NewGraph() *Graph {
+	return &Graph{
+		edges:   make(map[string]map[string]*Edge),
+		callers: make(map[string][]string),
+	}
+}
+
+// ServiceName returns the service of a server address, i.e. the host up to the first dot of a
+// cluster DNS name. IP addresses are kept as they are.
+func ServiceName(address string) string {
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	name, _, _ := strings.Cut(host, ".")
+	return name
+}
+
+// ClassifyCall returns the kind of a call from its route and the server address and port
+func ClassifyCall(route, address, port string) CallKind {
+	switch {
+	case route != "":
+		return CallHTTP
+	case databasePorts[port] || strings.Contains(address, "mysql") || strings.Contains(address, "redis"):
+		return CallDatabase
+	case mqPorts[port] || strings.Contains(address, "rabbitmq") || strings.Contains(address, "kafka"):
+		return CallMQ
+	default:
+		return CallTCP
+	}
+}
+
+// AddCall records a call from the source to the target service, frequency is its rate in calls
+// per second or 0 when unknown. Self calls and repeated calls only add to the frequency.
+func (g *Graph) AddCall(source, target string, call Call, frequency float64) {
+	if source == "" || target == "" || source == target {
+		return
+	}
+
+	targets, exists := g.edges[source]
+	if !exists {
+		targets = make(map[string]*Edge)
+		g.edges[source] = targets
+	}
+
+	edge, exists := targets[target]
+	if !exists {
+		edge = &Edge{Source: source, Target: target}
+		targets[target] = edge
+		g.callers[target] = insertSorted(g.callers[target], source)
+	}
+
+	edge.Frequency += frequency
+	if !slices.Contains(edge.Calls, call) {
+		edge.Calls = append(edge.Calls, call)
+	}
+}
+
+// Edge returns the edge from the source to the target service
+func (g *Graph) Edge(source, target string) (*Edge, bool) {
+	edge, exists := g.edges[source][target]
+	return edge, exists
+}
+
+// Edges returns every edge sorted by source and target
+func (g *Graph) Edges() []*Edge {
+	var edges []*Edge
+	for _, source := range sortedKeys(g.edges) {
+		for _, target := range sortedKeys(g.edges[source]) {
+			edges = append(edges, g.edges[source][target])
+		}
+	}
+	return edges
+}
+
+// Services returns every service that calls or is called, sorted
+func (g *Graph) Services() []string {
+	var services []string
+	for source, targets := range g.edges {
+		services = insertSorted(services, source)
+		for target := range targets {
+			services = insertSorted(services, target)
+		}
+	}
+	return services
+}
+
+// Callers returns the services calling the service, sorted
+func (g *Graph) Callers(service string) []string {
+	return append([]string(nil), g.callers[service]...)
+}
+
+// Callees returns the services called by the service, sorted
+func (g *Graph) Callees(service string) []string {
+	return sortedKeys(g.edges[service])
+}
+
+// Paths returns every call path from one service to another that visits no service twice and
+// has at most maxHops calls, 0 meaning no limit. Paths are ordered by length, then by name.
+func (g *Graph) Paths(from, to string, maxHops int) [][]string {
+	var paths [][]string
+	visited := map[string]bool{from: true}
+	path := []string{from}
+
+	var walk func(service string)
+	walk = func(service string) {
+		if service == to && len(path) > 1 {
+			paths = append(paths, append([]string(nil), path...))
+			return
+		}
+		if maxHops > 0 && len(path)-1 >= maxHops {
+			return
+		}
+
+		for _, callee := range g.Callees(service) {
+			if visited[callee] {
+				continue
+			}
+
+			visited[callee] = true
+			path = append(path, callee)
+			walk(callee)
+			path = path[:len(path)-1]
+			visited[callee] = false
+		}
+	}
+	if from != to {
+		walk(from)
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return strings.Join(paths[i], "\x00") < strings.Join(paths[j], "\x00")
+	})
+	return paths
+}
+
+// StronglyConnectedComponents returns the groups of services that can all reach each other
+// through calls, i.e. call cycles. Services outside of any cycle form a component of their own.
+// Each component is sorted and the components are sorted by their first service.
+func (g *Graph) StronglyConnectedComponents() [][]string {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	// Tarjan's algorithm
+	var connect func(service string)
+	connect = func(service string) {
+		index[service] = len(index)
+		lowlink[service] = index[service]
+		stack = append(stack, service)
+		onStack[service] = true
+
+		for _, callee := range g.Callees(service) {
+			if _, seen := index[callee]; !seen {
+				connect(callee)
+				lowlink[service] = min(lowlink[service], lowlink[callee])
+			} else if onStack[callee] {
+				lowlink[service] = min(lowlink[service], index[callee])
+			}
+		}
+
+		if lowlink[service] != index[service] {
+			return
+		}
+
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == service {
+				break
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	for _, service := range g.Services() {
+		if _, seen := index[service]; !seen {
+			connect(service)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+func insertSorted(values []string, value string) []string {
+	i := sort.SearchStrings(values, value)
+	if i < len(values) && values[i] == value {
+		return values
+	}
+	values = append(values, "")
+	copy(values[i+1:], values[i:])
+	values[i] = value
+	return values
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
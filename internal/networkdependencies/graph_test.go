@@ -0,0 +1,70 @@
+package networkdependencies_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/networkdependencies"
+)
+
+func TestServiceGraph(t *testing.T) {
+	graph := networkdependencies.NewGraph()
+	addCall := func(source, target, route, port string) {
+		graph.AddCall(source, networkdependencies.ServiceName(target), networkdependencies.Call{
+			Kind:  networkdependencies.ClassifyCall(route, target, port),
+			Route: route,
+			Port:  port,
+		}, 1)
+	}
+	addCall("frontend", "cart.shop.svc.cluster.local", "/cart", "8080")
+	addCall("frontend", "cart", "/cart/items", "8080")
+	addCall("frontend", "checkout", "/checkout", "8080")
+	addCall("checkout", "cart", "/cart", "8080")
+	addCall("checkout", "payment", "/charge", "8080")
+	addCall("payment", "checkout", "/callback", "8080")
+	addCall("cart", "mysql", "", "3306")
+	addCall("checkout", "rabbitmq", "", "5672")
+
+	edge, ok := graph.Edge("frontend", "cart")
+	if !ok {
+		t.Fatal("Edge(frontend, cart) not found")
+	}
+	if len(edge.Calls) != 2 || edge.Frequency != 2 || !reflect.DeepEqual(edge.Ports(), []string{"8080"}) {
+		t.Errorf("Edge(frontend, cart) = %+v", edge)
+	}
+	if _, ok := graph.Edge("cart", "frontend"); ok {
+		t.Error("Edge(cart, frontend) exists, want only the caller to callee edge")
+	}
+	if edge, _ := graph.Edge("cart", "mysql"); !reflect.DeepEqual(edge.Kinds(), []networkdependencies.CallKind{networkdependencies.CallDatabase}) {
+		t.Errorf("Edge(cart, mysql).Kinds() = %v", edge.Kinds())
+	}
+	if edge, _ := graph.Edge("checkout", "rabbitmq"); !reflect.DeepEqual(edge.Kinds(), []networkdependencies.CallKind{networkdependencies.CallMQ}) {
+		t.Errorf("Edge(checkout, rabbitmq).Kinds() = %v", edge.Kinds())
+	}
+
+	if got, want := graph.Callers("cart"), []string{"checkout", "frontend"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Callers(cart) = %v, want %v", got, want)
+	}
+	if got, want := graph.Callees("checkout"), []string{"cart", "payment", "rabbitmq"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Callees(checkout) = %v, want %v", got, want)
+	}
+
+	wantPaths := [][]string{
+		{"frontend", "cart", "mysql"},
+		{"frontend", "checkout", "cart", "mysql"},
+	}
+	if got := graph.Paths("frontend", "mysql", 0); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("Paths(frontend, mysql) = %v, want %v", got, wantPaths)
+	}
+	if got := graph.Paths("frontend", "mysql", 2); !reflect.DeepEqual(got, wantPaths[:1]) {
+		t.Errorf("Paths(frontend, mysql, 2) = %v, want %v", got, wantPaths[:1])
+	}
+	if got := graph.Paths("mysql", "frontend", 0); len(got) != 0 {
+		t.Errorf("Paths(mysql, frontend) = %v, want none against the call direction", got)
+	}
+
+	wantComponents := [][]string{{"cart"}, {"checkout", "payment"}, {"frontend"}, {"mysql"}, {"rabbitmq"}}
+	if got := graph.StronglyConnectedComponents(); !reflect.DeepEqual(got, wantComponents) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, wantComponents)
+	}
+}
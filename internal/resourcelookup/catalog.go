@@ -0,0 +1,250 @@
+package resourcelookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/databaseoperations"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/javaclassmethods"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/networkdependencies"
+	"github.com/LGU-SE-Internal/chaos-experiment/internal/serviceendpoints"
+	"sigs.k8s.io/yaml"
+)
+
+// Catalog provides the static injection points of one system under test, i.e. everything
+// that cannot be discovered from the cluster
+type Catalog interface {
+	// Endpoints returns every outgoing call of every service, including the ones without a route
+	Endpoints() []AppEndpointPair
+	Methods() []AppMethodPair
+	NetworkPairs() []AppNetworkPair
+	DatabaseOperations() []AppDatabasePair
+}
+
+// generatedCatalog serves the catalog compiled into the internal packages
+type generatedCatalog struct{}
+
+func (generatedCatalog) Endpoints() []AppEndpointPair {
+	var result []AppEndpointPair
+	for _, serviceName := range serviceendpoints.GetAllServices() {
+		for _, endpoint := range serviceendpoints.GetEndpointsByService(serviceName) {
+			result = append(result, AppEndpointPair{
+				AppName:       serviceName,
+				Route:         endpoint.Route,
+				Method:        endpoint.RequestMethod,
+				ServerAddress: endpoint.ServerAddress,
+				ServerPort:    endpoint.ServerPort,
+			})
+		}
+	}
+	return result
+}
+
+func (generatedCatalog) Methods() []AppMethodPair {
+	var result []AppMethodPair
+	for _, serviceName := range javaclassmethods.ListAllServiceNames() {
+		for _, method := range javaclassmethods.GetClassMethodsByService(serviceName) {
+			result = append(result, AppMethodPair{
+				AppName:    serviceName,
+				ClassName:  method.ClassName,
+				MethodName: method.MethodName,
+			})
+		}
+	}
+	return result
+}
+
+func (generatedCatalog) NetworkPairs() []AppNetworkPair {
+	var result []AppNetworkPair
+	for _, pair := range networkdependencies.GetAllServicePairs() {
+		result = append(result, AppNetworkPair{
+			SourceService: pair.SourceService,
+			TargetService: pair.TargetService,
+		})
+	}
+	return result
+}
+
+func (generatedCatalog) DatabaseOperations() []AppDatabasePair {
+	var result []AppDatabasePair
+	for _, serviceName := range databaseoperations.GetAllDatabaseServices() {
+		for _, op := range databaseoperations.GetOperationsByService(serviceName) {
+			result = append(result, AppDatabasePair{
+				AppName:       serviceName,
+				DBName:        op.DBName,
+				TableName:     op.DBTable,
+				OperationType: op.Operation,
+			})
+		}
+	}
+	return result
+}
+
+// StaticCatalog is a catalog loaded at runtime, see LoadCatalogFile for the file format
+type StaticCatalog struct {
+	EndpointList     []AppEndpointPair `json:"endpoints"`
+	MethodList       []AppMethodPair   `json:"methods"`
+	NetworkPairList  []AppNetworkPair  `json:"network_pairs,omitempty"`
+	DBOperationsList []AppDatabasePair `json:"db_operations"`
+}
+
+func (c *StaticCatalog) Endpoints() []AppEndpointPair {
+	return c.EndpointList
+}
+
+func (c *StaticCatalog) Methods() []AppMethodPair {
+	return c.MethodList
+}
+
+// NetworkPairs returns the configured pairs, or derives them from the endpoints in both directions
+// like the generated dependency graph does
+func (c *StaticCatalog) NetworkPairs() []AppNetworkPair {
+	if len(c.NetworkPairList) > 0 {
+		return c.NetworkPairList
+	}
+
+	seen := make(map[AppNetworkPair]bool)
+	var result []AppNetworkPair
+	for _, endpoint := range c.EndpointList {
+		if endpoint.ServerAddress == "" || endpoint.ServerAddress == endpoint.AppName {
+			continue
+		}
+
+		for _, pair := range []AppNetworkPair{
+			{SourceService: endpoint.AppName, TargetService: endpoint.ServerAddress},
+			{SourceService: endpoint.ServerAddress, TargetService: endpoint.AppName},
+		} {
+			if !seen[pair] {
+				seen[pair] = true
+				result = append(result, pair)
+			}
+		}
+	}
+	return result
+}
+
+func (c *StaticCatalog) DatabaseOperations() []AppDatabasePair {
+	return c.DBOperationsList
+}
+
+// LoadCatalogFile reads a catalog from a JSON or YAML file, chosen by the file extension.
+// The file holds the flattened lists, e.g. in YAML:
+//
+//	endpoints:
+//	  - {app_name: frontend, method: GET, route: /cart, server_address: cartservice, server_port: "7070"}
+//	methods:
+//	  - {app_name: adservice, class_name: hipstershop.AdService, method_name: getAds}
+//	db_operations:
+//	  - {app_name: cartservice, db_name: redis, table_name: cart, operation_type: GET}
+//
+// network_pairs is optional and derived from the endpoints when left out.
+func LoadCatalogFile(filename string) (*StaticCatalog, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	var catalog StaticCatalog
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &catalog)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &catalog)
+	default:
+		return nil, fmt.Errorf("unsupported catalog file extension %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal catalog %s: %w", filename, err)
+	}
+
+	return &catalog, nil
+}
+
+// LoadCatalogDir registers every <prefix>.json, <prefix>.yaml or <prefix>.yml file of the directory
+// as the catalog of that namespace prefix and returns the registered prefixes
+func LoadCatalogDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog directory: %w", err)
+	}
+
+	var prefixes []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		catalog, err := LoadCatalogFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		prefix := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		RegisterCatalog(prefix, catalog)
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// GeneratedCatalogPrefix is the namespace prefix of the system the generated catalog was extracted
+// from, TrainTicket. Other prefixes never see its methods, endpoints or database operations.
+const GeneratedCatalogPrefix = "ts"
+
+var (
+	catalogMu      sync.RWMutex
+	catalogs               = map[string]Catalog{GeneratedCatalogPrefix: generatedCatalog{}}
+	defaultCatalog Catalog = &StaticCatalog{}
+)
+
+// RegisterCatalog sets the catalog of a namespace prefix and drops the lookups cached from the previous one
+func RegisterCatalog(prefix string, catalog Catalog) {
+	catalogMu.Lock()
+	catalogs[prefix] = catalog
+	catalogMu.Unlock()
+
+	InvalidateCache()
+}
+
+// UnregisterCatalog drops the catalog of a namespace prefix and the lookups cached from it
+func UnregisterCatalog(prefix string) {
+	catalogMu.Lock()
+	delete(catalogs, prefix)
+	catalogMu.Unlock()
+
+	InvalidatePrefix(prefix)
+}
+
+// DefaultCatalog returns the catalog used for namespace prefixes without a registered catalog, an
+// empty one unless replaced, so the catalog based specs drop out of their action space
+func DefaultCatalog() Catalog {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	return defaultCatalog
+}
+
+// SetDefaultCatalog replaces the empty catalog as the fallback of every namespace prefix
+func SetDefaultCatalog(catalog Catalog) {
+	catalogMu.Lock()
+	defaultCatalog = catalog
+	catalogMu.Unlock()
+
+	InvalidateCache()
+}
+
+// CatalogFor returns the catalog registered for the namespace prefix, or the default catalog
+func CatalogFor(prefix string) Catalog {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	if catalog, ok := catalogs[prefix]; ok {
+		return catalog
+	}
+	return defaultCatalog
+}
@@ -0,0 +1,83 @@
+package resourcelookup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadCatalogDir(t *testing.T) {
+	dir := t.TempDir()
+	data := `endpoints:
+  - {app_name: frontend, method: GET, route: /cart, server_address: cartservice, server_port: "7070"}
+  - {app_name: frontend, method: GET, route: /product, server_address: productcatalogservice, server_port: "3550"}
+methods:
+  - {app_name: adservice, class_name: hipstershop.AdService, method_name: getAds}
+db_operations:
+  - {app_name: cartservice, db_name: redis, table_name: cart, operation_type: GET}
+`
+	if err := os.WriteFile(filepath.Join(dir, "ob.yaml"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	prefixes, err := LoadCatalogDir(dir)
+	if err != nil {
+		t.Fatalf("LoadCatalogDir() error = %v", err)
+	}
+	defer func() {
+		catalogMu.Lock()
+		delete(catalogs, "ob")
+		catalogMu.Unlock()
+	}()
+
+	if !reflect.DeepEqual(prefixes, []string{"ob"}) {
+		t.Fatalf("prefixes = %v, want [ob]", prefixes)
+	}
+
+	catalog := CatalogFor("ob")
+	if got := len(catalog.Endpoints()); got != 2 {
+		t.Errorf("len(Endpoints()) = %d, want 2", got)
+	}
+
+	if got := catalog.Methods(); len(got) != 1 || got[0].MethodName != "getAds" {
+		t.Errorf("Methods() = %+v", got)
+	}
+
+	wantPairs := []AppNetworkPair{
+		{SourceService: "frontend", TargetService: "cartservice"},
+		{SourceService: "cartservice", TargetService: "frontend"},
+		{SourceService: "frontend", TargetService: "productcatalogservice"},
+		{SourceService: "productcatalogservice", TargetService: "frontend"},
+	}
+	if got := catalog.NetworkPairs(); !reflect.DeepEqual(got, wantPairs) {
+		t.Errorf("NetworkPairs() = %+v, want %+v", got, wantPairs)
+	}
+
+	if _, ok := CatalogFor(GeneratedCatalogPrefix).(generatedCatalog); !ok {
+		t.Errorf("CatalogFor() of %s is not the generated catalog", GeneratedCatalogPrefix)
+	}
+
+	// Another system must not see the TrainTicket methods, endpoints or database operations
+	for _, prefix := range []string{"hs", "ts-other"} {
+		methods, err := GetAllJVMMethods(prefix + "0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		endpoints, err := GetAllHTTPEndpoints(prefix + "0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		operations, err := GetAllDatabaseOperations(prefix + "0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(methods) != 0 || len(endpoints) != 0 || len(operations) != 0 {
+			t.Errorf("%s has %d methods, %d endpoints and %d database operations of an unregistered catalog, want none",
+				prefix, len(methods), len(endpoints), len(operations))
+		}
+	}
+}
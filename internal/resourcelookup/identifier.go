@@ -0,0 +1,279 @@
+package resourcelookup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// PointKind identifies the catalog an injection point belongs to
+type PointKind string
+
+const (
+	PointApp         PointKind = "app"
+	PointMethod      PointKind = "method"
+	PointEndpoint    PointKind = "endpoint"
+	PointNetworkPair PointKind = "network"
+	PointContainer   PointKind = "container"
+	PointDNSEndpoint PointKind = "dns"
+	PointDatabase    PointKind = "database"
+	PointVolume      PointKind = "volume"
+	PointBlockVolume PointKind = "block-volume"
+	PointMachine     PointKind = "machine"
+)
+
+// pointID hashes the identifying parts of an injection point, so the ID does not depend on
+// where the point ends up after the generated catalogs are re-sorted
+func pointID(kind PointKind, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return fmt.Sprintf("%s-%s", kind, hex.EncodeToString(sum[:])[:16])
+}
+
+// AppID returns the stable identifier of an app label
+func AppID(appName string) string {
+	return pointID(PointApp, appName)
+}
+
+// MachineID returns the stable identifier of the app label of a physical machine
+func MachineID(appName string) string {
+	return pointID(PointMachine, appName)
+}
+
+// ID returns the stable identifier of the app+class+method
+func (p AppMethodPair) ID() string {
+	return pointID(PointMethod, p.AppName, p.ClassName, p.MethodName)
+}
+
+// ID returns the stable identifier of the app+route+method
+func (p AppEndpointPair) ID() string {
+	return pointID(PointEndpoint, p.AppName, p.Method, p.Route, p.ServerAddress)
+}
+
+// ID returns the stable identifier of the source+target
+func (p AppNetworkPair) ID() string {
+	return pointID(PointNetworkPair, p.SourceService, p.TargetService)
+}
+
+// ID returns the stable identifier of the app+domain
+func (p AppDNSPair) ID() string {
+	return pointID(PointDNSEndpoint, p.AppName, p.Domain)
+}
+
+// ID returns the stable identifier of the app+database+table+operation
+func (p AppDatabasePair) ID() string {
+	return pointID(PointDatabase, p.AppName, p.DBName, p.TableName, p.OperationType)
+}
+
+// ID returns the stable identifier of the app+container, pod names are left out since they change on every rollout
+func (c ContainerInfo) ID() string {
+	return pointID(PointContainer, c.AppLabel, c.ContainerName)
+}
+
+// ID returns the stable identifier of the app+container+volume path
+func (v VolumeInfo) ID() string {
+	return pointID(PointVolume, v.AppLabel, v.ContainerName, v.VolumePath)
+}
+
+// SystemSnapshot holds every flattened injection point list of one system under test
+type SystemSnapshot struct {
+	AppLabels    []string          `json:"app_labels"`
+	Machines     []string          `json:"machines,omitempty"`
+	Methods      []AppMethodPair   `json:"methods"`
+	Endpoints    []AppEndpointPair `json:"endpoints"`
+	NetworkPairs []AppNetworkPair  `json:"network_pairs"`
+	DNSEndpoints []AppDNSPair      `json:"dns_endpoints"`
+	DBOperations []AppDatabasePair `json:"db_operations"`
+	Containers   []ContainerInfo   `json:"containers"`
+	Volumes      []VolumeInfo      `json:"volumes"`
+}
+
+// CatalogSnapshot is a saved copy of the injection point lists keyed by namespace prefix,
+// used to re-resolve index-based configs after the catalogs have been regenerated
+type CatalogSnapshot struct {
+	CreatedAt time.Time                  `json:"created_at"`
+	Systems   map[string]*SystemSnapshot `json:"systems"`
+}
+
+// BlockVolumes returns the volumes backed by a persistent volume claim, see GetAllBlockVolumes
+func (s *SystemSnapshot) BlockVolumes() []VolumeInfo {
+	return blockVolumes(s.Volumes)
+}
+
+// PointIDs returns the stable identifiers of a catalog in index order
+func (s *SystemSnapshot) PointIDs(kind PointKind) ([]string, error) {
+	var ids []string
+	switch kind {
+	case PointApp:
+		for _, app := range s.AppLabels {
+			ids = append(ids, AppID(app))
+		}
+	case PointMethod:
+		for _, item := range s.Methods {
+			ids = append(ids, item.ID())
+		}
+	case PointEndpoint:
+		for _, item := range s.Endpoints {
+			ids = append(ids, item.ID())
+		}
+	case PointNetworkPair:
+		for _, item := range s.NetworkPairs {
+			ids = append(ids, item.ID())
+		}
+	case PointDNSEndpoint:
+		for _, item := range s.DNSEndpoints {
+			ids = append(ids, item.ID())
+		}
+	case PointDatabase:
+		for _, item := range s.DBOperations {
+			ids = append(ids, item.ID())
+		}
+	case PointContainer:
+		for _, item := range s.Containers {
+			ids = append(ids, item.ID())
+		}
+	case PointVolume:
+		for _, item := range s.Volumes {
+			ids = append(ids, item.ID())
+		}
+	case PointBlockVolume:
+		for _, item := range s.BlockVolumes() {
+			ids = append(ids, item.ID())
+		}
+	case PointMachine:
+		for _, machine := range s.Machines {
+			ids = append(ids, MachineID(machine))
+		}
+	default:
+		return nil, fmt.Errorf("unknown injection point kind: %s", kind)
+	}
+
+	return ids, nil
+}
+
+// IDAt returns the stable identifier of the injection point at index idx
+func (s *SystemSnapshot) IDAt(kind PointKind, idx int) (string, error) {
+	ids, err := s.PointIDs(kind)
+	if err != nil {
+		return "", err
+	}
+
+	if idx < 0 || idx >= len(ids) {
+		return "", fmt.Errorf("%s index out of range: %d (max: %d)", kind, idx, len(ids)-1)
+	}
+
+	return ids[idx], nil
+}
+
+// IndexOf returns the first index of the injection point with the given stable identifier
+func (s *SystemSnapshot) IndexOf(kind PointKind, id string) (int, error) {
+	ids, err := s.PointIDs(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	for idx, existing := range ids {
+		if existing == id {
+			return idx, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s with id %s not found in catalog", kind, id)
+}
+
+// PointKinds lists every injection point kind in snapshot order
+var PointKinds = []PointKind{
+	PointApp, PointMethod, PointEndpoint, PointNetworkPair, PointDNSEndpoint, PointDatabase, PointContainer, PointVolume,
+	PointBlockVolume, PointMachine,
+}
+
+// TakeSystemSnapshot copies the current injection point lists of the namespace's system
+func TakeSystemSnapshot(namespace string, labelKey string) (*SystemSnapshot, error) {
+	return TakePointSnapshot(namespace, labelKey, PointKinds...)
+}
+
+// TakePointSnapshot copies only the injection point lists of the kinds and leaves the others empty.
+// Methods, endpoints, network pairs, DNS endpoints and database operations come from the catalogs,
+// so a snapshot of those kinds needs no cluster.
+func TakePointSnapshot(namespace string, labelKey string, kinds ...PointKind) (*SystemSnapshot, error) {
+	snapshot := &SystemSnapshot{}
+
+	var err error
+	for _, kind := range kinds {
+		switch kind {
+		case PointApp:
+			if snapshot.AppLabels, err = GetAllAppLabels(namespace, labelKey); err != nil {
+				return nil, fmt.Errorf("failed to get app labels: %w", err)
+			}
+		case PointMethod:
+			if snapshot.Methods, err = GetAllJVMMethods(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get JVM methods: %w", err)
+			}
+		case PointEndpoint:
+			if snapshot.Endpoints, err = GetAllHTTPEndpoints(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get HTTP endpoints: %w", err)
+			}
+		case PointNetworkPair:
+			if snapshot.NetworkPairs, err = GetAllNetworkPairs(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get network pairs: %w", err)
+			}
+		case PointDNSEndpoint:
+			if snapshot.DNSEndpoints, err = GetAllDNSEndpoints(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get DNS endpoints: %w", err)
+			}
+		case PointDatabase:
+			if snapshot.DBOperations, err = GetAllDatabaseOperations(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get database operations: %w", err)
+			}
+		case PointContainer:
+			if snapshot.Containers, err = GetAllContainers(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get containers: %w", err)
+			}
+		case PointVolume, PointBlockVolume:
+			// Block volumes are the claim-backed part of the volumes
+			if snapshot.Volumes, err = GetAllVolumes(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get volumes: %w", err)
+			}
+		case PointMachine:
+			if snapshot.Machines, err = GetAllMachineLabels(namespace); err != nil {
+				return nil, fmt.Errorf("failed to get physical machines: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown injection point kind: %s", kind)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// SaveSnapshot writes the snapshot to a JSON file
+func SaveSnapshot(snapshot *CatalogSnapshot, filename string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write catalog snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot
+func LoadSnapshot(filename string) (*CatalogSnapshot, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog snapshot: %w", err)
+	}
+
+	var snapshot CatalogSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal catalog snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
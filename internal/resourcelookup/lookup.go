@@ -7,10 +7,7 @@ import (
 	"sync"
 
 	"github.com/LGU-SE-Internal/chaos-experiment/client"
-	"github.com/LGU-SE-Internal/chaos-experiment/internal/databaseoperations"
-	"github.com/LGU-SE-Internal/chaos-experiment/internal/javaclassmethods"
 	"github.com/LGU-SE-Internal/chaos-experiment/internal/networkdependencies"
-	"github.com/LGU-SE-Internal/chaos-experiment/internal/serviceendpoints"
 	"github.com/LGU-SE-Internal/chaos-experiment/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -29,6 +26,8 @@ type AppEndpointPair struct {
 	Method        string `json:"method"`
 	ServerAddress string `json:"server_address"`
 	ServerPort    string `json:"server_port"`
+	// Frequency is the observed call rate in calls per second, 0 when unknown
+	Frequency float64 `json:"frequency,omitempty"`
 }
 
 // AppNetworkPair represents a flattened source+target combination for network chaos
@@ -58,15 +57,36 @@ type ContainerInfo struct {
 	ContainerName string `json:"container_name"`
 }
 
-// Global cache for lookups
+// VolumeInfo represents a container volume mount that IO chaos can target
+type VolumeInfo struct {
+	PodName       string `json:"pod_name"`
+	AppLabel      string `json:"app_label"`
+	ContainerName string `json:"container_name"`
+	VolumeName    string `json:"volume_name"`
+	VolumePath    string `json:"volume_path"`
+	// ClaimName is the persistent volume claim behind the volume, empty for configmaps, emptyDirs and the like
+	ClaimName string `json:"claim_name,omitempty"`
+}
+
+// Global cache for lookups, keyed by namespace prefix
 var (
+	cacheMu sync.RWMutex
+
 	cachedAppLabels     map[string][]string
-	cachedAppMethods    []AppMethodPair
-	cachedAppEndpoints  []AppEndpointPair
-	cachedNetworkPairs  []AppNetworkPair
-	cachedDNSEndpoints  []AppDNSPair
+	cachedMachineLabels map[string][]string
+	cachedAppMethods    map[string][]AppMethodPair
+	cachedAppEndpoints  map[string][]AppEndpointPair
+	cachedNetworkPairs  map[string][]AppNetworkPair
+	cachedDNSEndpoints  map[string][]AppDNSPair
 	cachedContainerInfo map[string][]ContainerInfo
-	cachedDBOperations  []AppDatabasePair
+	cachedVolumeInfo    map[string][]VolumeInfo
+	cachedDBOperations  map[string][]AppDatabasePair
+
+	// cachedPodInfo holds the containers of every target namespace, keyed by namespace rather than
+	// prefix, since each namespace runs its own pods
+	cachedPodInfo map[string][]ContainerInfo
+	// snapshotPrefixes are the prefixes loaded by LoadSystemSnapshot, their pods come from the snapshot
+	snapshotPrefixes map[string]bool
 )
 
 // GetAllAppLabels returns all application labels sorted alphabetically
@@ -76,7 +96,7 @@ func GetAllAppLabels(namespace string, key string) ([]string, error) {
 		return nil, err
 	}
 
-	if labels, exists := cachedAppLabels[prefix]; exists && len(labels) > 0 {
+	if labels, exists := getCached(&cachedAppLabels, prefix); exists && len(labels) > 0 {
 		return labels, nil
 	}
 
@@ -88,31 +108,18 @@ func GetAllAppLabels(namespace string, key string) ([]string, error) {
 
 	// Sort alphabetically
 	sort.Strings(labels)
-	cachedAppLabels[prefix] = labels
+	setCached(&cachedAppLabels, prefix, labels)
 	return labels, nil
 }
 
-// GetAllJVMMethods returns all app+method pairs sorted by app name
-func GetAllJVMMethods() ([]AppMethodPair, error) {
-	if cachedAppMethods != nil {
-		return cachedAppMethods, nil
+// GetAllJVMMethods returns all app+method pairs of the namespace's system sorted by app name
+func GetAllJVMMethods(namespace string) ([]AppMethodPair, error) {
+	prefix := catalogPrefix(namespace)
+	if result, exists := getCached(&cachedAppMethods, prefix); exists {
+		return result, nil
 	}
 
-	// Get all service names first
-	services := javaclassmethods.ListAllServiceNames()
-	result := make([]AppMethodPair, 0)
-
-	// For each service, get its methods
-	for _, serviceName := range services {
-		methods := javaclassmethods.GetClassMethodsByService(serviceName)
-		for _, method := range methods {
-			result = append(result, AppMethodPair{
-				AppName:    serviceName,
-				ClassName:  method.ClassName,
-				MethodName: method.MethodName,
-			})
-		}
-	}
+	result := append([]AppMethodPair{}, CatalogFor(prefix).Methods()...)
 
 	// Sort by app name for consistency
 	sort.Slice(result, func(i, j int) bool {
@@ -125,39 +132,27 @@ func GetAllJVMMethods() ([]AppMethodPair, error) {
 		return result[i].MethodName < result[j].MethodName
 	})
 
-	cachedAppMethods = result
+	setCached(&cachedAppMethods, prefix, result)
 	return result, nil
 }
 
-// GetAllHTTPEndpoints returns all app+endpoint pairs sorted by app name
-func GetAllHTTPEndpoints() ([]AppEndpointPair, error) {
-	if cachedAppEndpoints != nil {
-		return cachedAppEndpoints, nil
+// GetAllHTTPEndpoints returns all app+endpoint pairs of the namespace's system sorted by app name
+func GetAllHTTPEndpoints(namespace string) ([]AppEndpointPair, error) {
+	prefix := catalogPrefix(namespace)
+	if result, exists := getCached(&cachedAppEndpoints, prefix); exists {
+		return result, nil
 	}
 
-	// Get all service names
-	services := serviceendpoints.GetAllServices()
 	result := make([]AppEndpointPair, 0)
+	for _, endpoint := range CatalogFor(prefix).Endpoints() {
+		// Skip non-HTTP endpoints like rabbitmq
+		if endpoint.ServerAddress == "ts-rabbitmq" {
+			continue
+		}
 
-	// For each service, get its endpoints
-	for _, serviceName := range services {
-		endpoints := serviceendpoints.GetEndpointsByService(serviceName)
-		for _, endpoint := range endpoints {
-			// Skip non-HTTP endpoints like rabbitmq
-			if endpoint.ServerAddress == "ts-rabbitmq" {
-				continue
-			}
-
-			// Only include endpoints with a valid route
-			if endpoint.Route != "" {
-				result = append(result, AppEndpointPair{
-					AppName:       serviceName,
-					Route:         endpoint.Route,
-					Method:        endpoint.RequestMethod,
-					ServerAddress: endpoint.ServerAddress,
-					ServerPort:    endpoint.ServerPort,
-				})
-			}
+		// Only include endpoints with a valid route
+		if endpoint.Route != "" {
+			result = append(result, endpoint)
 		}
 	}
 
@@ -169,26 +164,18 @@ func GetAllHTTPEndpoints() ([]AppEndpointPair, error) {
 		return result[i].Route < result[j].Route
 	})
 
-	cachedAppEndpoints = result
+	setCached(&cachedAppEndpoints, prefix, result)
 	return result, nil
 }
 
-// GetAllNetworkPairs returns all network pairs sorted by source service
-func GetAllNetworkPairs() ([]AppNetworkPair, error) {
-	if cachedNetworkPairs != nil {
-		return cachedNetworkPairs, nil
+// GetAllNetworkPairs returns all network pairs of the namespace's system sorted by source service
+func GetAllNetworkPairs(namespace string) ([]AppNetworkPair, error) {
+	prefix := catalogPrefix(namespace)
+	if result, exists := getCached(&cachedNetworkPairs, prefix); exists {
+		return result, nil
 	}
 
-	// Get all service-to-service pairs
-	pairs := networkdependencies.GetAllServicePairs()
-	result := make([]AppNetworkPair, 0, len(pairs))
-
-	for _, pair := range pairs {
-		result = append(result, AppNetworkPair{
-			SourceService: pair.SourceService,
-			TargetService: pair.TargetService,
-		})
-	}
+	result := append([]AppNetworkPair{}, CatalogFor(prefix).NetworkPairs()...)
 
 	// Sort by source service for consistency
 	sort.Slice(result, func(i, j int) bool {
@@ -198,39 +185,50 @@ func GetAllNetworkPairs() ([]AppNetworkPair, error) {
 		return result[i].TargetService < result[j].TargetService
 	})
 
-	cachedNetworkPairs = result
+	setCached(&cachedNetworkPairs, prefix, result)
 	return result, nil
 }
 
-// GetAllDNSEndpoints returns all app+domain pairs for DNS chaos sorted by app name
-func GetAllDNSEndpoints() ([]AppDNSPair, error) {
-	if cachedDNSEndpoints != nil {
-		return cachedDNSEndpoints, nil
+// GetServiceGraph returns the directed call graph of the namespace's system. It is built from every
+// endpoint of the catalog, including database and message queue connections without a route, and
+// keeps which service is the caller, unlike GetAllNetworkPairs.
+func GetServiceGraph(namespace string) (*networkdependencies.Graph, error) {
+	graph := networkdependencies.NewGraph()
+	for _, endpoint := range CatalogFor(catalogPrefix(namespace)).Endpoints() {
+		graph.AddCall(endpoint.AppName, networkdependencies.ServiceName(endpoint.ServerAddress), networkdependencies.Call{
+			Kind:   networkdependencies.ClassifyCall(endpoint.Route, endpoint.ServerAddress, endpoint.ServerPort),
+			Method: endpoint.Method,
+			Route:  endpoint.Route,
+			Port:   endpoint.ServerPort,
+		}, endpoint.Frequency)
+	}
+
+	return graph, nil
+}
+
+// GetAllDNSEndpoints returns all app+domain pairs of the namespace's system for DNS chaos sorted by app name
+func GetAllDNSEndpoints(namespace string) ([]AppDNSPair, error) {
+	prefix := catalogPrefix(namespace)
+	if result, exists := getCached(&cachedDNSEndpoints, prefix); exists {
+		return result, nil
 	}
 
-	// Get all service names
-	services := serviceendpoints.GetAllServices()
 	result := make([]AppDNSPair, 0)
+	uniqueDomains := make(map[AppDNSPair]bool)
 
-	// For each service, get its endpoints
-	for _, serviceName := range services {
-		endpoints := serviceendpoints.GetEndpointsByService(serviceName)
-		uniqueDomains := make(map[string]bool)
-
-		for _, endpoint := range endpoints {
-			// Only include valid server addresses that are not the service itself
-			if endpoint.ServerAddress != "" &&
-				endpoint.ServerAddress != serviceName {
-				uniqueDomains[endpoint.ServerAddress] = true
-			}
+	for _, endpoint := range CatalogFor(prefix).Endpoints() {
+		// Only include valid server addresses that are not the service itself
+		if endpoint.ServerAddress == "" || endpoint.ServerAddress == endpoint.AppName {
+			continue
 		}
 
-		// Convert unique domains to AppDNSPairs
-		for domain := range uniqueDomains {
-			result = append(result, AppDNSPair{
-				AppName: serviceName,
-				Domain:  domain,
-			})
+		pair := AppDNSPair{
+			AppName: endpoint.AppName,
+			Domain:  endpoint.ServerAddress,
+		}
+		if !uniqueDomains[pair] {
+			uniqueDomains[pair] = true
+			result = append(result, pair)
 		}
 	}
 
@@ -242,32 +240,18 @@ func GetAllDNSEndpoints() ([]AppDNSPair, error) {
 		return result[i].Domain < result[j].Domain
 	})
 
-	cachedDNSEndpoints = result
+	setCached(&cachedDNSEndpoints, prefix, result)
 	return result, nil
 }
 
-// GetAllDatabaseOperations returns all app+database operations pairs sorted by app name
-func GetAllDatabaseOperations() ([]AppDatabasePair, error) {
-	if cachedDBOperations != nil {
-		return cachedDBOperations, nil
+// GetAllDatabaseOperations returns all app+database operations pairs of the namespace's system sorted by app name
+func GetAllDatabaseOperations(namespace string) ([]AppDatabasePair, error) {
+	prefix := catalogPrefix(namespace)
+	if result, exists := getCached(&cachedDBOperations, prefix); exists {
+		return result, nil
 	}
 
-	// Get all service names that have database operations
-	services := databaseoperations.GetAllDatabaseServices()
-	result := make([]AppDatabasePair, 0)
-
-	// For each service, get its database operations
-	for _, serviceName := range services {
-		operations := databaseoperations.GetOperationsByService(serviceName)
-		for _, op := range operations {
-			result = append(result, AppDatabasePair{
-				AppName:       serviceName,
-				DBName:        op.DBName,
-				TableName:     op.DBTable,
-				OperationType: op.Operation,
-			})
-		}
-	}
+	result := append([]AppDatabasePair{}, CatalogFor(prefix).DatabaseOperations()...)
 
 	// Sort by app name for consistency
 	sort.Slice(result, func(i, j int) bool {
@@ -283,7 +267,7 @@ func GetAllDatabaseOperations() ([]AppDatabasePair, error) {
 		return result[i].OperationType < result[j].OperationType
 	})
 
-	cachedDBOperations = result
+	setCached(&cachedDBOperations, prefix, result)
 	return result, nil
 }
 
@@ -294,10 +278,49 @@ func GetAllContainers(namespace string) ([]ContainerInfo, error) {
 		return nil, err
 	}
 
-	if result, exists := cachedContainerInfo[prefix]; exists {
+	if result, exists := getCached(&cachedContainerInfo, prefix); exists {
 		return result, nil
 	}
 
+	result, err := listContainers(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(&cachedContainerInfo, prefix, result)
+	setCached(&cachedPodInfo, namespace, result)
+	return result, nil
+}
+
+// namespaceContainers returns the containers of the namespace itself, whose pods are those of the
+// namespace rather than of the first namespace of the system. A system loaded from a snapshot has
+// only the pods of the namespace it was taken in, so those are returned for every namespace of it.
+func namespaceContainers(namespace string) ([]ContainerInfo, error) {
+	prefix, err := utils.ExtractNsPrefix(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheMu.RLock()
+	result, exists := cachedPodInfo[namespace]
+	if !exists && snapshotPrefixes[prefix] {
+		result, exists = cachedContainerInfo[prefix]
+	}
+	cacheMu.RUnlock()
+	if exists {
+		return result, nil
+	}
+
+	if result, err = listContainers(namespace); err != nil {
+		return nil, err
+	}
+
+	setCached(&cachedPodInfo, namespace, result)
+	return result, nil
+}
+
+// listContainers lists the containers of the namespace from the cluster
+func listContainers(namespace string) ([]ContainerInfo, error) {
 	containers, err := client.GetContainersWithAppLabel(context.Background(), namespace)
 	if err != nil {
 		return nil, err
@@ -320,10 +343,101 @@ func GetAllContainers(namespace string) ([]ContainerInfo, error) {
 		return result[i].ContainerName < result[j].ContainerName
 	})
 
-	cachedContainerInfo[prefix] = result
 	return result, nil
 }
 
+// GetAllVolumes returns all container volume mounts sorted by app label
+func GetAllVolumes(namespace string) ([]VolumeInfo, error) {
+	prefix, err := utils.ExtractNsPrefix(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if result, exists := getCached(&cachedVolumeInfo, prefix); exists {
+		return result, nil
+	}
+
+	mounts, err := client.GetContainersWithVolumeMounts(context.Background(), namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pods of the same app share their mounts, keep one entry per app+container+path
+	seen := make(map[string]bool)
+	result := make([]VolumeInfo, 0, len(mounts))
+	for _, m := range mounts {
+		key := m["appLabel"] + "/" + m["containerName"] + "/" + m["volumePath"]
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, VolumeInfo{
+			PodName:       m["podName"],
+			AppLabel:      m["appLabel"],
+			ContainerName: m["containerName"],
+			VolumeName:    m["volumeName"],
+			VolumePath:    m["volumePath"],
+			ClaimName:     m["claimName"],
+		})
+	}
+
+	// Sort by app label for consistency
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AppLabel != result[j].AppLabel {
+			return result[i].AppLabel < result[j].AppLabel
+		}
+		if result[i].ContainerName != result[j].ContainerName {
+			return result[i].ContainerName < result[j].ContainerName
+		}
+		return result[i].VolumePath < result[j].VolumePath
+	})
+
+	setCached(&cachedVolumeInfo, prefix, result)
+	return result, nil
+}
+
+// GetAllBlockVolumes returns the container volume mounts backed by a persistent volume claim, the only
+// ones block chaos can inject into, in the order of GetAllVolumes
+func GetAllBlockVolumes(namespace string) ([]VolumeInfo, error) {
+	volumes, err := GetAllVolumes(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockVolumes(volumes), nil
+}
+
+func blockVolumes(volumes []VolumeInfo) []VolumeInfo {
+	result := []VolumeInfo{}
+	for _, volume := range volumes {
+		if volume.ClaimName != "" {
+			result = append(result, volume)
+		}
+	}
+	return result
+}
+
+// GetAllMachineLabels returns the app labels of the PhysicalMachine objects sorted alphabetically
+func GetAllMachineLabels(namespace string) ([]string, error) {
+	prefix, err := utils.ExtractNsPrefix(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if labels, exists := getCached(&cachedMachineLabels, prefix); exists {
+		return labels, nil
+	}
+
+	labels, err := client.GetPhysicalMachineLabels(context.Background(), namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	setCached(&cachedMachineLabels, prefix, labels)
+	return labels, nil
+}
+
 // GetContainersByService returns all container names for a specific service
 func GetContainersByService(namespace string, serviceName string) ([]string, error) {
 	allContainers, err := GetAllContainers(namespace)
@@ -343,9 +457,9 @@ func GetContainersByService(namespace string, serviceName string) ([]string, err
 	return containerNames, nil
 }
 
-// GetPodsByService returns all pod names for a specific service
+// GetPodsByService returns all pod names for a specific service in the namespace
 func GetPodsByService(namespace string, serviceName string) ([]string, error) {
-	allContainers, err := GetAllContainers(namespace)
+	allContainers, err := namespaceContainers(namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -370,9 +484,9 @@ func GetPodsByService(namespace string, serviceName string) ([]string, error) {
 }
 
 // GetContainersAndPodsByServices returns containers and pods for multiple services
-// This is useful for chaos that affects multiple services
+// This is useful for chaos that affects multiple services. The pods are those of the namespace.
 func GetContainersAndPodsByServices(namespace string, serviceNames []string) ([]string, []string, error) {
-	allContainers, err := GetAllContainers(namespace)
+	allContainers, err := namespaceContainers(namespace)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -413,18 +527,60 @@ func GetContainersAndPodsByServices(namespace string, serviceNames []string) ([]
 	return containers, pods, nil
 }
 
+func init() {
+	InitCaches()
+}
+
+// getCached reads the cached lookup of a namespace prefix
+func getCached[T any](cache *map[string][]T, prefix string) ([]T, bool) {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
+	result, exists := (*cache)[prefix]
+	return result, exists
+}
+
+// setCached stores the lookup of a namespace prefix
+func setCached[T any](cache *map[string][]T, prefix string, result []T) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	(*cache)[prefix] = result
+}
+
 func InitCaches() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
 	cachedAppLabels = make(map[string][]string)
+	cachedMachineLabels = make(map[string][]string)
+	cachedAppMethods = make(map[string][]AppMethodPair)
+	cachedAppEndpoints = make(map[string][]AppEndpointPair)
+	cachedNetworkPairs = make(map[string][]AppNetworkPair)
+	cachedDNSEndpoints = make(map[string][]AppDNSPair)
 	cachedContainerInfo = make(map[string][]ContainerInfo)
+	cachedVolumeInfo = make(map[string][]VolumeInfo)
+	cachedDBOperations = make(map[string][]AppDatabasePair)
+	cachedPodInfo = make(map[string][]ContainerInfo)
+	snapshotPrefixes = make(map[string]bool)
+}
+
+// catalogPrefix returns the namespace prefix the catalog lookups are keyed by,
+// a bare prefix like "ts" is accepted as well as a namespace like "ts0"
+func catalogPrefix(namespace string) string {
+	if prefix, err := utils.ExtractNsPrefix(namespace); err == nil {
+		return prefix
+	}
+	return namespace
 }
 
 // PreloadCaches preloads resource caches to reduce first-access latency
 func PreloadCaches(namespace string, labelKey string) error {
 	// Create error channel to collect all errors
-	errChan := make(chan error, 7)
+	errChan := make(chan error, 8)
 
 	var wg sync.WaitGroup
-	wg.Add(7)
+	wg.Add(8)
 
 	// Preload app labels
 	go func() {
@@ -438,7 +594,7 @@ func PreloadCaches(namespace string, labelKey string) error {
 	// Preload JVM methods
 	go func() {
 		defer wg.Done()
-		_, err := GetAllJVMMethods()
+		_, err := GetAllJVMMethods(namespace)
 		if err != nil {
 			errChan <- fmt.Errorf("failed to preload JVM methods cache: %v", err)
 		}
@@ -447,7 +603,7 @@ func PreloadCaches(namespace string, labelKey string) error {
 	// Preload HTTP endpoints
 	go func() {
 		defer wg.Done()
-		_, err := GetAllHTTPEndpoints()
+		_, err := GetAllHTTPEndpoints(namespace)
 		if err != nil {
 			errChan <- fmt.Errorf("failed to preload HTTP endpoints cache: %v", err)
 		}
@@ -456,7 +612,7 @@ func PreloadCaches(namespace string, labelKey string) error {
 	// Preload network pairs
 	go func() {
 		defer wg.Done()
-		_, err := GetAllNetworkPairs()
+		_, err := GetAllNetworkPairs(namespace)
 		if err != nil {
 			errChan <- fmt.Errorf("failed to preload network pairs cache: %v", err)
 		}
@@ -465,7 +621,7 @@ func PreloadCaches(namespace string, labelKey string) error {
 	// Preload DNS endpoints
 	go func() {
 		defer wg.Done()
-		_, err := GetAllDNSEndpoints()
+		_, err := GetAllDNSEndpoints(namespace)
 		if err != nil {
 			errChan <- fmt.Errorf("failed to preload DNS endpoints cache: %v", err)
 		}
@@ -474,7 +630,7 @@ func PreloadCaches(namespace string, labelKey string) error {
 	// Preload database operations
 	go func() {
 		defer wg.Done()
-		_, err := GetAllDatabaseOperations()
+		_, err := GetAllDatabaseOperations(namespace)
 		if err != nil {
 			errChan <- fmt.Errorf("failed to preload database operations cache: %v", err)
 		}
@@ -489,6 +645,15 @@ func PreloadCaches(namespace string, labelKey string) error {
 		}
 	}()
 
+	// Preload volume info
+	go func() {
+		defer wg.Done()
+		_, err := GetAllVolumes(namespace)
+		if err != nil {
+			errChan <- fmt.Errorf("failed to preload volume info cache: %v", err)
+		}
+	}()
+
 	// Wait for all initialization to complete
 	wg.Wait()
 	close(errChan)
@@ -507,14 +672,53 @@ func PreloadCaches(namespace string, labelKey string) error {
 	return nil
 }
 
+// LoadSystemSnapshot serves the lookups of the namespace prefix from the snapshot until InvalidatePrefix,
+// so injections of the system can be built without a cluster
+func LoadSystemSnapshot(prefix string, system *SystemSnapshot) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cachedAppLabels[prefix] = system.AppLabels
+	cachedMachineLabels[prefix] = system.Machines
+	cachedAppMethods[prefix] = system.Methods
+	cachedAppEndpoints[prefix] = system.Endpoints
+	cachedNetworkPairs[prefix] = system.NetworkPairs
+	cachedDNSEndpoints[prefix] = system.DNSEndpoints
+	cachedContainerInfo[prefix] = system.Containers
+	cachedVolumeInfo[prefix] = system.Volumes
+	cachedDBOperations[prefix] = system.DBOperations
+	snapshotPrefixes[prefix] = true
+	deletePodInfo(prefix)
+}
+
 // InvalidateCache clears all cached data
 func InvalidateCache() {
-	cachedAppLabels = make(map[string][]string)
+	InitCaches()
+}
 
-	cachedAppMethods = nil
-	cachedAppEndpoints = nil
-	cachedNetworkPairs = nil
-	cachedDNSEndpoints = nil
-	cachedContainerInfo = nil
-	cachedDBOperations = nil
+// InvalidatePrefix clears the data cached for one namespace prefix
+func InvalidatePrefix(prefix string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	delete(cachedAppLabels, prefix)
+	delete(cachedMachineLabels, prefix)
+	delete(cachedAppMethods, prefix)
+	delete(cachedAppEndpoints, prefix)
+	delete(cachedNetworkPairs, prefix)
+	delete(cachedDNSEndpoints, prefix)
+	delete(cachedContainerInfo, prefix)
+	delete(cachedVolumeInfo, prefix)
+	delete(cachedDBOperations, prefix)
+	delete(snapshotPrefixes, prefix)
+	deletePodInfo(prefix)
+}
+
+// deletePodInfo drops the pods cached for the namespaces of the prefix, the caller holds cacheMu
+func deletePodInfo(prefix string) {
+	for namespace := range cachedPodInfo {
+		if nsPrefix, err := utils.ExtractNsPrefix(namespace); err == nil && nsPrefix == prefix {
+			delete(cachedPodInfo, namespace)
+		}
+	}
 }